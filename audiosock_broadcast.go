@@ -3,6 +3,7 @@ package gorpitx
 import (
 	"encoding/json"
 	"io"
+	"net"
 	"slices"
 	"strconv"
 
@@ -25,30 +26,56 @@ const (
 	ModulationRAW ModulationType = "RAW"
 )
 
+// AudioSourceType defines the kind of socket AudioSockBroadcast reads from.
+type AudioSourceType = string
+
+const (
+	AudioSourceUnix AudioSourceType = "unix"
+	AudioSourceTCP  AudioSourceType = "tcp"
+)
+
 const (
 	defaultAudioSockBroadcastSampleRate = 48000
 )
 
 type AudioSockBroadcast struct {
-	// SocketPath specifies the Unix socket path for audio input. Required.
+	// SocketPath specifies where the audio is read from: the Unix socket
+	// path, or the host:port to connect to when SourceType is "tcp".
+	// Required.
 	SocketPath string `json:"socketPath"`
 
+	// SourceType specifies the kind of socket ("unix" or "tcp"). Optional
+	// parameter. Default: "unix"
+	SourceType AudioSourceType `json:"sourceType,omitempty" schema:"enum=unix|tcp"` //nolint:lll
+
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// SampleRate specifies the audio sample rate. Optional parameter.
-	// Default: 48000 Hz
-	SampleRate *int `json:"sampleRate,omitempty"`
+	// Default: Config.AudioSockBroadcastSampleRate, or 48000 Hz when unset
+	SampleRate *int `json:"sampleRate,omitempty" schema:"exclusiveMinimum=0"`
 
 	// Modulation specifies the modulation type. Optional parameter.
 	// If not specified, uses default "FM".
 	// Available: AM, DSB, USB, LSB, FM, RAW
-	Modulation *string `json:"modulation,omitempty"`
+	Modulation *string `json:"modulation,omitempty" schema:"enum=AM|DSB|USB|LSB|FM|RAW"` //nolint:lll
 
 	// Gain specifies the gain multiplier for the audio signal. Optional parameter.
 	// Default: 1.0
-	Gain *float64 `json:"gain,omitempty"`
+	Gain *float64 `json:"gain,omitempty" schema:"minimum=0"`
+
+	// RampMs specifies how long in milliseconds the audio is faded in from
+	// silence when it starts, before modulation. It only shapes the audio:
+	// the carrier still keys at full power, FM having a constant envelope
+	// and the AGC of the other modulations levelling the fade back out.
+	// Optional parameter. Must not be negative if specified.
+	// Default: 0 (no fade)
+	RampMs *int `json:"rampMs,omitempty" schema:"minimum=0"`
+
+	// defaultSampleRate is the sample rate used when SampleRate is nil,
+	// from Config.AudioSockBroadcastSampleRate. Zero uses the built-in one.
+	defaultSampleRate int
 }
 
 func (m *AudioSockBroadcast) ParseArgs(
@@ -78,12 +105,7 @@ func (m *AudioSockBroadcast) buildArgs() []string {
 	args = append(args, m.SocketPath)
 
 	// Add sample rate argument (default if not specified)
-	sampleRate := defaultAudioSockBroadcastSampleRate
-	if m.SampleRate != nil {
-		sampleRate = *m.SampleRate
-	}
-
-	args = append(args, strconv.Itoa(sampleRate))
+	args = append(args, strconv.Itoa(m.sampleRate()))
 
 	// Add modulation argument (default if not specified)
 	modulation := ModulationFM
@@ -101,44 +123,91 @@ func (m *AudioSockBroadcast) buildArgs() []string {
 
 	args = append(args, strconv.FormatFloat(gain, 'f', -1, 64))
 
-	return args
-}
+	// Add source type argument (default if not specified)
+	args = append(args, m.sourceType())
 
-// validate validates all AudioSock parameters.
-func (m *AudioSockBroadcast) validate() error {
-	if err := m.validateSocketPath(); err != nil {
-		return err
+	// Add ramp argument (default if not specified)
+	rampMs := 0
+	if m.RampMs != nil {
+		rampMs = *m.RampMs
 	}
 
-	if err := m.validateFrequency(); err != nil {
-		return err
-	}
+	args = append(args, strconv.Itoa(rampMs))
 
-	if err := m.validateSampleRate(); err != nil {
-		return err
-	}
+	return args
+}
 
-	if err := m.validateModulation(); err != nil {
-		return err
+// sourceType returns the kind of socket to read from, "unix" by default.
+func (m *AudioSockBroadcast) sourceType() AudioSourceType {
+	if m.SourceType == "" {
+		return AudioSourceUnix
 	}
 
-	if err := m.validateGain(); err != nil {
-		return err
-	}
+	return m.SourceType
+}
 
-	return nil
+// validate validates all AudioSock parameters.
+func (m *AudioSockBroadcast) validate() error {
+	return validateFields(
+		fieldCheck{"socketPath", m.validateSocketPath},
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"sampleRate", m.validateSampleRate},
+		fieldCheck{"modulation", m.validateModulation},
+		fieldCheck{"gain", m.validateGain},
+		fieldCheck{"rampMs", m.validateRampMs},
+	)
 }
 
-// validateSocketPath validates the socket path parameter.
+// validateSocketPath validates the socket path parameter against the
+// source type.
 func (m *AudioSockBroadcast) validateSocketPath() error {
 	if m.SocketPath == "" {
 		return ctxerrors.Wrap(
 			commonerrors.ErrRequiredFieldNotSet, "socketPath")
 	}
 
+	switch m.sourceType() {
+	case AudioSourceUnix:
+		return nil
+	case AudioSourceTCP:
+		return validateTCPAddress(m.SocketPath)
+	default:
+		return fieldError("sourceType", ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"source type must be 'unix' or 'tcp', got: %s",
+			m.SourceType,
+		))
+	}
+}
+
+// validateTCPAddress checks that address is a host:port with a valid port.
+func validateTCPAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"tcp socket must be host:port, got: %s",
+			address,
+		)
+	}
+
+	portNum, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || portNum == 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"tcp port must be between 1 and 65535, got: %s",
+			port,
+		)
+	}
+
 	return nil
 }
 
+// frequencyHz returns the carrier frequency in Hz.
+func (m *AudioSockBroadcast) frequencyHz() float64 {
+	return m.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (m *AudioSockBroadcast) validateFrequency() error {
 	if m.Frequency <= 0 {
@@ -154,13 +223,31 @@ func (m *AudioSockBroadcast) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
 	return nil
 }
 
+// applyConfig takes the default sample rate from the config.
+func (m *AudioSockBroadcast) applyConfig(config Config) {
+	m.defaultSampleRate = config.AudioSockBroadcastSampleRate
+}
+
+// sampleRate returns the sample rate, falling back to the configured
+// default and then the built-in one.
+func (m *AudioSockBroadcast) sampleRate() int {
+	switch {
+	case m.SampleRate != nil:
+		return *m.SampleRate
+	case m.defaultSampleRate > 0:
+		return m.defaultSampleRate
+	default:
+		return defaultAudioSockBroadcastSampleRate
+	}
+}
+
 // validateSampleRate validates the sample rate parameter.
 func (m *AudioSockBroadcast) validateSampleRate() error {
 	if m.SampleRate != nil && *m.SampleRate <= 0 {
@@ -180,7 +267,21 @@ func (m *AudioSockBroadcast) validateModulation() error {
 		return nil // Optional parameter
 	}
 
-	validModulations := []ModulationType{
+	if IsValidModulation(*m.Modulation) {
+		return nil
+	}
+
+	return ctxerrors.Wrapf(
+		commonerrors.ErrInvalidValue,
+		"invalid modulation: %s, valid modulations: %v",
+		*m.Modulation, ValidModulations(),
+	)
+}
+
+// ValidModulations returns the modulation types modulation.sh supports, for
+// UIs and modules that feed audio through it.
+func ValidModulations() []ModulationType {
+	return []ModulationType{
 		ModulationAM,
 		ModulationDSB,
 		ModulationUSB,
@@ -188,17 +289,12 @@ func (m *AudioSockBroadcast) validateModulation() error {
 		ModulationFM,
 		ModulationRAW,
 	}
+}
 
-	modulation := *m.Modulation
-	if slices.Contains(validModulations, modulation) {
-		return nil
-	}
-
-	return ctxerrors.Wrapf(
-		commonerrors.ErrInvalidValue,
-		"invalid modulation: %s, valid modulations: %v",
-		modulation, validModulations,
-	)
+// IsValidModulation reports whether modulation is one of ValidModulations.
+// Names are case-sensitive.
+func IsValidModulation(modulation string) bool {
+	return slices.Contains(ValidModulations(), modulation)
 }
 
 // validateGain validates the gain parameter.
@@ -213,3 +309,16 @@ func (m *AudioSockBroadcast) validateGain() error {
 
 	return nil
 }
+
+// validateRampMs validates the audio fade-in parameter.
+func (m *AudioSockBroadcast) validateRampMs() error {
+	if m.RampMs != nil && *m.RampMs < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"rampMs must not be negative, got: %d",
+			*m.RampMs,
+		)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +22,7 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				Frequency:  144500000.0,
 			},
 			expectedArgs: []string{
-				"144500000", "/tmp/audio_socket", "48000", "FM", "1",
+				"144500000", "/tmp/audio_socket", "48000", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -32,7 +33,7 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				SampleRate: intPtr(96000),
 			},
 			expectedArgs: []string{
-				"434000000", "/tmp/custom_socket", "96000", "FM", "1",
+				"434000000", "/tmp/custom_socket", "96000", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -43,7 +44,7 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				SampleRate: intPtr(22050),
 			},
 			expectedArgs: []string{
-				"1296000000", "/var/tmp/voice_socket", "22050", "FM", "1",
+				"1296000000", "/var/tmp/voice_socket", "22050", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -54,7 +55,7 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				Modulation: stringPtr("FM"),
 			},
 			expectedArgs: []string{
-				"144500000", "/tmp/audio_socket", "48000", "FM", "1",
+				"144500000", "/tmp/audio_socket", "48000", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -65,7 +66,32 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				Gain:       floatPtr(2.5),
 			},
 			expectedArgs: []string{
-				"144500000", "/tmp/audio_socket", "48000", "FM", "2.5",
+				"144500000", "/tmp/audio_socket", "48000", "FM", "2.5", "unix", "0",
+			},
+		},
+		{
+			name: "tcp source",
+			input: AudioSockBroadcast{
+				SocketPath: "voip-bridge.lan:7355",
+				SourceType: AudioSourceTCP,
+				Frequency:  144500000.0,
+			},
+			expectedArgs: []string{
+				"144500000", "voip-bridge.lan:7355", "48000", "FM", "1", "tcp",
+				"0",
+			},
+		},
+		{
+			name: "audio fade-in",
+			input: AudioSockBroadcast{
+				SocketPath: "/tmp/audio_socket",
+				Frequency:  144500000.0,
+				Modulation: stringPtr("AM"),
+				RampMs:     intPtr(250),
+			},
+			expectedArgs: []string{
+				"144500000", "/tmp/audio_socket", "48000", "AM", "1", "unix",
+				"250",
 			},
 		},
 		{
@@ -78,7 +104,7 @@ func TestAudioSockBroadcast_ParseArgs_Success(t *testing.T) {
 				Gain:       floatPtr(3.0),
 			},
 			expectedArgs: []string{
-				"434000000", "/tmp/custom_socket", "96000", "USB", "3",
+				"434000000", "/tmp/custom_socket", "96000", "USB", "3", "unix", "0",
 			},
 		},
 	}
@@ -214,6 +240,7 @@ func TestAudioSockBroadcast_validateSocketPath(t *testing.T) {
 	tests := []struct {
 		name        string
 		socketPath  string
+		sourceType  AudioSourceType
 		expectError bool
 		errorMsg    string
 	}{
@@ -233,11 +260,59 @@ func TestAudioSockBroadcast_validateSocketPath(t *testing.T) {
 			expectError: true,
 			errorMsg:    "socketPath",
 		},
+		{
+			name:       "valid tcp address",
+			socketPath: "192.168.1.20:7355",
+			sourceType: AudioSourceTCP,
+		},
+		{
+			name:       "valid tcp ipv6 address",
+			socketPath: "[::1]:7355",
+			sourceType: AudioSourceTCP,
+		},
+		{
+			name:        "tcp address without port",
+			socketPath:  "192.168.1.20",
+			sourceType:  AudioSourceTCP,
+			expectError: true,
+			errorMsg:    "host:port",
+		},
+		{
+			name:        "tcp address without host",
+			socketPath:  ":7355",
+			sourceType:  AudioSourceTCP,
+			expectError: true,
+			errorMsg:    "host:port",
+		},
+		{
+			name:        "tcp port out of range",
+			socketPath:  "localhost:70000",
+			sourceType:  AudioSourceTCP,
+			expectError: true,
+			errorMsg:    "between 1 and 65535",
+		},
+		{
+			name:        "tcp port zero",
+			socketPath:  "localhost:0",
+			sourceType:  AudioSourceTCP,
+			expectError: true,
+			errorMsg:    "between 1 and 65535",
+		},
+		{
+			name:        "unknown source type",
+			socketPath:  "/tmp/audio_socket",
+			sourceType:  "udp",
+			expectError: true,
+			errorMsg:    "source type",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			usb := &AudioSockBroadcast{SocketPath: tt.socketPath}
+			usb := &AudioSockBroadcast{
+				SocketPath: tt.socketPath,
+				SourceType: tt.sourceType,
+			}
 			err := usb.validateSocketPath()
 
 			if tt.expectError {
@@ -335,7 +410,7 @@ func TestAudioSockBroadcast_buildArgs(t *testing.T) {
 				Frequency:  144500000.0,
 			},
 			expectedArgs: []string{
-				"144500000", "/tmp/audio_socket", "48000", "FM", "1",
+				"144500000", "/tmp/audio_socket", "48000", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -346,7 +421,7 @@ func TestAudioSockBroadcast_buildArgs(t *testing.T) {
 				SampleRate: intPtr(96000),
 			},
 			expectedArgs: []string{
-				"434000000", "/var/tmp/voice_socket", "96000", "FM", "1",
+				"434000000", "/var/tmp/voice_socket", "96000", "FM", "1", "unix", "0",
 			},
 		},
 		{
@@ -357,7 +432,7 @@ func TestAudioSockBroadcast_buildArgs(t *testing.T) {
 				SampleRate: intPtr(16000),
 			},
 			expectedArgs: []string{
-				"1296000000", "/tmp/narrowband_socket", "16000", "FM", "1",
+				"1296000000", "/tmp/narrowband_socket", "16000", "FM", "1", "unix", "0",
 			},
 		},
 	}
@@ -513,3 +588,86 @@ func TestAudioSockBroadcast_validateGain(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidModulation(t *testing.T) {
+	for _, modulation := range ValidModulations() {
+		assert.True(t, IsValidModulation(modulation), modulation)
+	}
+
+	assert.False(t, IsValidModulation("fm"))
+	assert.False(t, IsValidModulation("WFM"))
+	assert.False(t, IsValidModulation(""))
+
+	// Callers get their own copy
+	modulations := ValidModulations()
+	modulations[0] = "WFM"
+	assert.Equal(t, ModulationAM, ValidModulations()[0])
+}
+
+func TestAudioSockBroadcast_validateRampMs(t *testing.T) {
+	tests := []struct {
+		name        string
+		rampMs      *int
+		expectError bool
+	}{
+		{name: "not set"},
+		{name: "zero", rampMs: intPtr(0)},
+		{name: "positive", rampMs: intPtr(500)},
+		{name: "negative", rampMs: intPtr(-1), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&AudioSockBroadcast{RampMs: tt.rampMs}).validateRampMs()
+			if tt.expectError {
+				require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAudioSockBroadcast_ConfigSampleRate(t *testing.T) {
+	rpitx := &RPITX{config: Config{AudioSockBroadcastSampleRate: 22050}}
+
+	tests := []struct {
+		name     string
+		args     string
+		expected string
+	}{
+		{
+			name:     "config default",
+			args:     `{"socketPath": "/tmp/audio_socket", "frequency": 144500000}`,
+			expected: "22050",
+		},
+		{
+			name: "sample rate set",
+			args: `{"socketPath": "/tmp/audio_socket", "frequency": 144500000, ` +
+				`"sampleRate": 44100}`,
+			expected: "44100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, _, err := rpitx.parseModuleArgs(
+				&AudioSockBroadcast{}, json.RawMessage(tt.args),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args[2])
+		})
+	}
+
+	// Without a config default the built-in one is used
+	args, _, err := (&RPITX{}).parseModuleArgs(
+		&AudioSockBroadcast{},
+		json.RawMessage(
+			`{"socketPath": "/tmp/audio_socket", "frequency": 144500000}`,
+		),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "48000", args[2])
+}
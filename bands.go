@@ -0,0 +1,206 @@
+package gorpitx
+
+// Region is an IARU region, used to pick the amateur band plan.
+type Region int
+
+const (
+	// RegionIARU1 covers Europe, Africa, the Middle East and northern Asia.
+	RegionIARU1 Region = 1
+	// RegionIARU2 covers the Americas.
+	RegionIARU2 Region = 2
+	// RegionIARU3 covers the rest of Asia and the Pacific.
+	RegionIARU3 Region = 3
+)
+
+// hamBand is an amateur allocation, bounds inclusive.
+type hamBand struct {
+	name   string
+	lowHz  float64
+	highHz float64
+}
+
+// hamBandPlans holds the amateur allocations of each IARU region within the
+// RPiTX hardware range.
+var hamBandPlans = map[Region][]hamBand{ //nolint:gochecknoglobals
+	RegionIARU1: {
+		{name: "2200m", lowHz: 135700, highHz: 137800},
+		{name: "630m", lowHz: 472000, highHz: 479000},
+		{name: "160m", lowHz: 1810000, highHz: 2000000},
+		{name: "80m", lowHz: 3500000, highHz: 3800000},
+		{name: "60m", lowHz: 5351500, highHz: 5366500},
+		{name: "40m", lowHz: 7000000, highHz: 7200000},
+		{name: "30m", lowHz: 10100000, highHz: 10150000},
+		{name: "20m", lowHz: 14000000, highHz: 14350000},
+		{name: "17m", lowHz: 18068000, highHz: 18168000},
+		{name: "15m", lowHz: 21000000, highHz: 21450000},
+		{name: "12m", lowHz: 24890000, highHz: 24990000},
+		{name: "10m", lowHz: 28000000, highHz: 29700000},
+		{name: "6m", lowHz: 50000000, highHz: 52000000},
+		{name: "4m", lowHz: 70000000, highHz: 70500000},
+		{name: "2m", lowHz: 144000000, highHz: 146000000},
+		{name: "70cm", lowHz: 430000000, highHz: 440000000},
+		{name: "23cm", lowHz: 1240000000, highHz: 1300000000},
+	},
+	RegionIARU2: {
+		{name: "2200m", lowHz: 135700, highHz: 137800},
+		{name: "630m", lowHz: 472000, highHz: 479000},
+		{name: "160m", lowHz: 1800000, highHz: 2000000},
+		{name: "80m", lowHz: 3500000, highHz: 4000000},
+		{name: "60m", lowHz: 5351500, highHz: 5366500},
+		{name: "40m", lowHz: 7000000, highHz: 7300000},
+		{name: "30m", lowHz: 10100000, highHz: 10150000},
+		{name: "20m", lowHz: 14000000, highHz: 14350000},
+		{name: "17m", lowHz: 18068000, highHz: 18168000},
+		{name: "15m", lowHz: 21000000, highHz: 21450000},
+		{name: "12m", lowHz: 24890000, highHz: 24990000},
+		{name: "10m", lowHz: 28000000, highHz: 29700000},
+		{name: "6m", lowHz: 50000000, highHz: 54000000},
+		{name: "2m", lowHz: 144000000, highHz: 148000000},
+		{name: "1.25m", lowHz: 222000000, highHz: 225000000},
+		{name: "70cm", lowHz: 420000000, highHz: 450000000},
+		{name: "33cm", lowHz: 902000000, highHz: 928000000},
+		{name: "23cm", lowHz: 1240000000, highHz: 1300000000},
+	},
+	RegionIARU3: {
+		{name: "2200m", lowHz: 135700, highHz: 137800},
+		{name: "630m", lowHz: 472000, highHz: 479000},
+		{name: "160m", lowHz: 1800000, highHz: 2000000},
+		{name: "80m", lowHz: 3500000, highHz: 3900000},
+		{name: "60m", lowHz: 5351500, highHz: 5366500},
+		{name: "40m", lowHz: 7000000, highHz: 7200000},
+		{name: "30m", lowHz: 10100000, highHz: 10150000},
+		{name: "20m", lowHz: 14000000, highHz: 14350000},
+		{name: "17m", lowHz: 18068000, highHz: 18168000},
+		{name: "15m", lowHz: 21000000, highHz: 21450000},
+		{name: "12m", lowHz: 24890000, highHz: 24990000},
+		{name: "10m", lowHz: 28000000, highHz: 29700000},
+		{name: "6m", lowHz: 50000000, highHz: 54000000},
+		{name: "2m", lowHz: 144000000, highHz: 148000000},
+		{name: "70cm", lowHz: 430000000, highHz: 440000000},
+		{name: "23cm", lowHz: 1240000000, highHz: 1300000000},
+	},
+}
+
+// IsInHamBand reports whether freqHz lies inside an amateur band of the
+// region's IARU band plan and, if so, returns the band name (e.g. "20m").
+// National allocations can be narrower than the regional plan.
+func IsInHamBand(freqHz float64, region Region) (string, bool) {
+	for _, band := range hamBandPlans[region] {
+		if freqHz >= band.lowHz && freqHz <= band.highHz {
+			return band.name, true
+		}
+	}
+
+	return "", false
+}
+
+const (
+	// harmonicWarningLimitHz is the highest harmonic checked by the
+	// harmonic warnings, enough to cover the cellular bands.
+	harmonicWarningLimitHz = 3000000000
+
+	// harmonicWarningOrder is the highest harmonic order checked by the
+	// harmonic warnings. Higher orders are too weak to matter.
+	harmonicWarningOrder = 10
+)
+
+// sensitiveBand is a band that must not receive spurious emissions.
+type sensitiveBand struct {
+	name string
+	FrequencyRange
+}
+
+// sensitiveBands lists the bands harmonic warnings look for.
+var sensitiveBands = []sensitiveBand{ //nolint:gochecknoglobals
+	{"aviation VHF", FrequencyRange{MinHz: 108000000, MaxHz: 137000000}},
+	{"emergency 243 MHz", FrequencyRange{MinHz: 242950000, MaxHz: 243050000}},
+	{"COSPAS-SARSAT", FrequencyRange{MinHz: 406000000, MaxHz: 406100000}},
+	{"cellular", FrequencyRange{MinHz: 703000000, MaxHz: 960000000}},
+	{"aviation DME", FrequencyRange{MinHz: 960000000, MaxHz: 1215000000}},
+	{"GNSS L2/L5", FrequencyRange{MinHz: 1164000000, MaxHz: 1300000000}},
+	{"GNSS L1", FrequencyRange{MinHz: 1559000000, MaxHz: 1610000000}},
+	{"cellular", FrequencyRange{MinHz: 1710000000, MaxHz: 2170000000}},
+	{"cellular", FrequencyRange{MinHz: 2500000000, MaxHz: 2690000000}},
+}
+
+// CheckHarmonics returns the harmonics of freqHz (2f, 3f, ...) up to and
+// including upTo Hz. The RPiTX square-wave output radiates on all of them,
+// so they are worth checking before transmitting.
+func CheckHarmonics(freqHz float64, upTo float64) []float64 {
+	if freqHz <= 0 {
+		return nil
+	}
+
+	var harmonics []float64
+
+	for n := 2.0; n*freqHz <= upTo; n++ {
+		harmonics = append(harmonics, n*freqHz)
+	}
+
+	return harmonics
+}
+
+// frequencyReporter is implemented by modules that transmit on a single
+// carrier frequency.
+type frequencyReporter interface {
+	frequencyHz() float64
+}
+
+// warnOutsideHamBand logs a warning when ham band warnings are enabled and
+// the module is about to transmit outside the amateur bands. It never blocks
+// the transmission, so deliberate ISM-band use keeps working.
+func (r *RPITX) warnOutsideHamBand(name ModuleName, module Module) {
+	if r.hamBandRegion == 0 {
+		return
+	}
+
+	reporter, ok := module.(frequencyReporter)
+	if !ok {
+		return
+	}
+
+	freqHz := reporter.frequencyHz()
+	if _, ok := IsInHamBand(freqHz, r.hamBandRegion); ok {
+		return
+	}
+
+	r.log().Warnf(
+		"module %s transmits on %.0f Hz, outside the IARU region %d ham bands",
+		name, freqHz, r.hamBandRegion,
+	)
+}
+
+// warnHarmonics logs a warning for each sensitive band hit by a harmonic of
+// the module frequency, when harmonic warnings are enabled. Only the
+// strongest harmonics, up to harmonicWarningOrder, are considered and each
+// band is reported once. It's advisory only and never blocks the
+// transmission.
+func (r *RPITX) warnHarmonics(name ModuleName, module Module) {
+	if !r.harmonicWarnings {
+		return
+	}
+
+	reporter, ok := module.(frequencyReporter)
+	if !ok {
+		return
+	}
+
+	freqHz := reporter.frequencyHz()
+	upTo := min(freqHz*harmonicWarningOrder, harmonicWarningLimitHz)
+	warned := map[string]bool{}
+
+	for _, harmonicHz := range CheckHarmonics(freqHz, upTo) {
+		for _, band := range sensitiveBands {
+			if warned[band.name] || !band.Contains(harmonicHz) {
+				continue
+			}
+
+			warned[band.name] = true
+
+			r.log().Warnf(
+				"module %s on %.0f Hz has a harmonic on %.0f Hz in the %s band",
+				name, freqHz, harmonicHz, band.name,
+			)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInHamBand(t *testing.T) {
+	tests := []struct {
+		name         string
+		freqHz       float64
+		region       Region
+		expectedBand string
+		expectedOK   bool
+	}{
+		{"20m FT8 region 1", 14074000, RegionIARU1, "20m", true},
+		{"band lower edge", 14000000, RegionIARU2, "20m", true},
+		{"band upper edge", 14350000, RegionIARU3, "20m", true},
+		{"just above band", 14350001, RegionIARU1, "", false},
+		{"2m region 2", 147000000, RegionIARU2, "2m", true},
+		{"2m upper part not in region 1", 147000000, RegionIARU1, "", false},
+		{"4m only in region 1", 70200000, RegionIARU1, "4m", true},
+		{"4m not in region 2", 70200000, RegionIARU2, "", false},
+		{"33cm region 2", 915000000, RegionIARU2, "33cm", true},
+		{"433.92 MHz ISM is 70cm", 433920000, RegionIARU1, "70cm", true},
+		{"FM broadcast", 107900000, RegionIARU1, "", false},
+		{"unknown region", 14074000, Region(4), "", false},
+		{"zero region", 14074000, 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			band, ok := IsInHamBand(tt.freqHz, tt.region)
+			assert.Equal(t, tt.expectedBand, band)
+			assert.Equal(t, tt.expectedOK, ok)
+		})
+	}
+}
+
+func TestRPITX_HamBandWarnings(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		moduleName  ModuleName
+		args        map[string]any
+		expectWarns bool
+	}{
+		{
+			name:       "outside ham band warns",
+			opts:       []Option{WithHamBandWarnings(RegionIARU1)},
+			moduleName: ModuleNamePIFMRDS,
+			args: map[string]any{
+				"freq":  107.9,
+				"audio": ".fixtures/test.wav",
+			},
+			expectWarns: true,
+		},
+		{
+			name:       "inside ham band stays quiet",
+			opts:       []Option{WithHamBandWarnings(RegionIARU1)},
+			moduleName: ModuleNameTUNE,
+			args:       map[string]any{"frequency": 14074000.0},
+		},
+		{
+			name:       "disabled by default",
+			moduleName: ModuleNamePIFMRDS,
+			args: map[string]any{
+				"freq":  107.9,
+				"audio": ".fixtures/test.wav",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{messages: make(chan string, 100)}
+
+			r, err := New(append(tt.opts, WithLogger(logger))...)
+			require.NoError(t, err)
+
+			args, err := json.Marshal(tt.args)
+			require.NoError(t, err)
+
+			_, _, _, err = r.PreviewCommand(tt.moduleName, args)
+			require.NoError(t, err)
+
+			close(logger.messages)
+
+			warned := false
+
+			for msg := range logger.messages {
+				if msg == "warn: module %s transmits on %.0f Hz, "+
+					"outside the IARU region %d ham bands" {
+					warned = true
+				}
+			}
+
+			assert.Equal(t, tt.expectWarns, warned)
+		})
+	}
+}
+
+func TestCheckHarmonics(t *testing.T) {
+	tests := []struct {
+		name     string
+		freqHz   float64
+		upTo     float64
+		expected []float64
+	}{
+		{
+			name:     "2m up to 500 MHz",
+			freqHz:   144000000,
+			upTo:     500000000,
+			expected: []float64{288000000, 432000000},
+		},
+		{
+			name:     "limit is inclusive",
+			freqHz:   100,
+			upTo:     300,
+			expected: []float64{200, 300},
+		},
+		{
+			name:   "limit below second harmonic",
+			freqHz: 434000000,
+			upTo:   500000000,
+		},
+		{
+			name:   "non-positive frequency",
+			freqHz: 0,
+			upTo:   500000000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CheckHarmonics(tt.freqHz, tt.upTo))
+		})
+	}
+}
+
+func TestRPITX_HarmonicWarnings(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		freqHz        float64
+		expectedWarns int
+	}{
+		{
+			// 3rd harmonic 120.6 MHz in the airband, the rest clear.
+			name:          "harmonic in aviation band",
+			opts:          []Option{WithHarmonicWarnings()},
+			freqHz:        40200000,
+			expectedWarns: 1,
+		},
+		{
+			// 868, 1736 and 2604 MHz are all cellular, reported once.
+			name:          "each band reported once",
+			opts:          []Option{WithHarmonicWarnings()},
+			freqHz:        434000000,
+			expectedWarns: 1,
+		},
+		{
+			// 730 MHz cellular, 1022 MHz DME, 1168 MHz GNSS.
+			name:          "several bands",
+			opts:          []Option{WithHarmonicWarnings()},
+			freqHz:        146000000,
+			expectedWarns: 3,
+		},
+		{
+			// Only harmonics up to the 10th (140 MHz) are checked.
+			name:          "high order harmonics ignored",
+			opts:          []Option{WithHarmonicWarnings()},
+			freqHz:        14000000,
+			expectedWarns: 1,
+		},
+		{
+			name:   "disabled by default",
+			freqHz: 40200000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{messages: make(chan string, 100)}
+
+			r, err := New(append(tt.opts, WithLogger(logger))...)
+			require.NoError(t, err)
+
+			r.warnHarmonics(ModuleNameTUNE, &TUNE{Frequency: tt.freqHz})
+
+			close(logger.messages)
+
+			warns := 0
+			for range logger.messages {
+				warns++
+			}
+
+			assert.Equal(t, tt.expectedWarns, warns)
+		})
+	}
+}
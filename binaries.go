@@ -0,0 +1,91 @@
+package gorpitx
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// sendiqBinaryName is the rpitx binary the embedded scripts pipe their IQ
+// samples into.
+const sendiqBinaryName = "sendiq"
+
+// execBits are the permission bits allowing anyone to execute a file.
+const execBits = 0o111
+
+// CheckBinaries stats the binary every registered module runs and returns
+// one entry per module: nil when the binary is there and executable,
+// ErrFileNotFound when it is missing and ErrNotExecutable otherwise. Script
+// modules are checked for the sendiq binary their scripts feed. It's an
+// upfront inventory for installs where only some rpitx tools are built.
+func (r *RPITX) CheckBinaries() map[ModuleName]error {
+	results := map[ModuleName]error{}
+
+	for _, name := range r.GetSupportedModules() {
+		path, err := r.moduleBinaryPath(name)
+		if err == nil {
+			err = checkBinary(path)
+		}
+
+		if err != nil {
+			err = ctxerrors.Wrapf(err, "module %s", name)
+		}
+
+		results[name] = err
+	}
+
+	return results
+}
+
+// moduleBinaryPath returns the path of the binary the module runs.
+func (r *RPITX) moduleBinaryPath(name ModuleName) (string, error) {
+	if r.IsScriptModule(name) {
+		return filepath.Join(r.rpitxPath(), sendiqBinaryName), nil
+	}
+
+	return r.binaryPath(name)
+}
+
+// checkBinary checks that the file at path exists and is executable.
+func checkBinary(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"binary not found at %s",
+			path,
+		)
+	}
+
+	if err != nil {
+		return ctxerrors.Wrapf(err, "failed to stat binary %s", path)
+	}
+
+	if info.IsDir() || info.Mode().Perm()&execBits == 0 {
+		return ctxerrors.Wrapf(ErrNotExecutable, "%s", path)
+	}
+
+	return nil
+}
+
+// warnMissingBinaries logs a warning for every module whose binary fails
+// CheckBinaries.
+func (r *RPITX) warnMissingBinaries() {
+	results := r.CheckBinaries()
+
+	names := make([]ModuleName, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := results[name]; err != nil {
+			r.log().Warnf("module unavailable: %v", err)
+		}
+	}
+}
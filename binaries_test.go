@@ -0,0 +1,85 @@
+package gorpitx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_CheckBinaries(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tune"), nil, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pocsag"), nil, 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "morse"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sendiq"), nil, 0o755))
+
+	custom := filepath.Join(t.TempDir(), "custom")
+	require.NoError(t, os.WriteFile(custom, nil, 0o700))
+
+	rpitx := &RPITX{
+		config: Config{
+			Path:        dir,
+			BinaryPaths: map[ModuleName]string{ModuleNameFT8: custom},
+		},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:    &TUNE{},
+			ModuleNamePOCSAG:  &POCSAG{},
+			ModuleNameMORSE:   &MORSE{},
+			ModuleNamePIRTTY:  &PIRTTY{},
+			ModuleNameFT8:     &FT8{},
+			ModuleNameFSK:     &FSK{},
+			ModuleNamePIFMRDS: &PIFMRDS{},
+		},
+	}
+
+	results := rpitx.CheckBinaries()
+	require.Len(t, results, 7)
+
+	require.NoError(t, results[ModuleNameTUNE])
+	require.NoError(t, results[ModuleNameFT8], "binary path override")
+	require.NoError(t, results[ModuleNameFSK], "script feeding sendiq")
+	require.ErrorIs(t, results[ModuleNamePOCSAG], ErrNotExecutable)
+	require.ErrorIs(t, results[ModuleNameMORSE], ErrNotExecutable)
+	require.ErrorIs(t, results[ModuleNamePIRTTY], commonerrors.ErrFileNotFound)
+	assert.Contains(t, results[ModuleNamePIRTTY].Error(), "module pirtty")
+	require.ErrorIs(t, results[ModuleNamePIFMRDS], commonerrors.ErrFileNotFound)
+}
+
+func TestNew_WithBinaryCheck(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tune"), nil, 0o700))
+
+	logger := &recordingLogger{messages: make(chan string, 10)}
+
+	_, err := New(
+		WithPath(dir),
+		WithModules(map[ModuleName]Module{
+			ModuleNameTUNE:  &TUNE{},
+			ModuleNameMORSE: &MORSE{},
+		}),
+		WithLogger(logger),
+		WithBinaryCheck(),
+	)
+	require.NoError(t, err)
+
+	// Only the missing morse binary is reported
+	require.Len(t, logger.messages, 1)
+	assert.Equal(t, "warn: module unavailable: %v", <-logger.messages)
+
+	// Without the option nothing is checked
+	_, err = New(
+		WithPath(dir),
+		WithModules(map[ModuleName]Module{ModuleNameMORSE: &MORSE{}}),
+		WithLogger(logger),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, logger.messages)
+}
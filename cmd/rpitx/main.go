@@ -0,0 +1,56 @@
+// Command rpitx runs the gorpitx modules from the command line.
+//
+// Every module is a subcommand taking its args as flags named after the JSON
+// fields, or as a JSON document on stdin with --stdin:
+//
+//	rpitx morse --frequency 14.070MHz --rate 20 --message "CQ CQ"
+//	echo '{"frequency":434000000}' | rpitx tune --stdin --timeout 10s
+//	rpitx status
+//	rpitx stop
+//
+// ENV=dev runs the mock execution instead of transmitting, like the library.
+// GORPITX_CONFIG points to a JSON or YAML config file, see gorpitx.LoadConfig.
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/psyb0t/gorpitx"
+)
+
+// envVarNameConfig names the environment variable holding the config file.
+const envVarNameConfig = "GORPITX_CONFIG"
+
+func main() {
+	var opts []gorpitx.Option
+	if path := os.Getenv(envVarNameConfig); path != "" {
+		opts = append(opts, gorpitx.WithConfigFile(path))
+	}
+
+	// The catalog only describes the modules for their flags and help, so it
+	// skips the root check that would keep non-root users from status, stop
+	// and --help. The module commands build the RPITX that transmits.
+	catalog, err := gorpitx.New(
+		append(slices.Clip(opts), gorpitx.WithRequireRoot(false))...,
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	newRPITX := func() (*gorpitx.RPITX, error) {
+		return gorpitx.New(opts...)
+	}
+
+	root, err := newRootCmd(catalog, newRPITX)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
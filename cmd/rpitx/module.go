@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gorpitx"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// outputBuffer is the buffer size of the channels receiving the module
+	// output.
+	outputBuffer = 100
+
+	// stopGracePeriod is how long the module gets to exit after SIGTERM
+	// when the command is interrupted.
+	stopGracePeriod = 3 * time.Second
+)
+
+// moduleSchema is the part of a module JSON schema used to build its flags.
+type moduleSchema struct {
+	Properties map[string]schemaProperty `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+// schemaProperty is a property of a module JSON schema.
+type schemaProperty struct {
+	Type  string          `json:"type"`
+	Items *schemaProperty `json:"items"`
+}
+
+// flagKind tells how a module flag value is turned into JSON.
+type flagKind int
+
+const (
+	flagKindString      flagKind = iota // JSON string
+	flagKindNumber                      // JSON number, or string like "14MHz"
+	flagKindInteger                     // JSON integer
+	flagKindBool                        // JSON boolean
+	flagKindStringSlice                 // JSON array of strings
+	flagKindJSON                        // raw JSON value
+)
+
+// moduleFlag maps a command flag to a module arg.
+type moduleFlag struct {
+	name string
+	kind flagKind
+}
+
+// moduleCmdOptions are the flags of a module command besides its args.
+type moduleCmdOptions struct {
+	timeout time.Duration
+	stdin   bool
+}
+
+// newModuleCmd returns the command running the module, with a flag for each
+// field of its JSON args taken from the catalog.
+func newModuleCmd(
+	catalog *gorpitx.RPITX,
+	newRPITX rpitxFactory,
+	opts *cliOptions,
+	name gorpitx.ModuleName,
+) (*cobra.Command, error) {
+	schemaJSON, err := catalog.ModuleSchema(name)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to get %s schema", name)
+	}
+
+	var schema moduleSchema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to decode %s schema", name)
+	}
+
+	cmdOpts := &moduleCmdOptions{}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: "Run the " + name + " module",
+		Long: "Run the " + name + " module until it exits, the timeout " +
+			"elapses or the command is interrupted. The args are given as " +
+			"flags or, with --stdin, as a JSON document on stdin.",
+		Args: cobra.NoArgs,
+	}
+
+	flags := addModuleFlags(cmd, schema)
+
+	cmd.Flags().DurationVar(
+		&cmdOpts.timeout,
+		"timeout",
+		catalog.ModuleDefaultTimeout(name),
+		"stop the module after this long, 0 runs until it exits",
+	)
+	cmd.Flags().BoolVar(
+		&cmdOpts.stdin,
+		"stdin",
+		false,
+		"read the module args as JSON from stdin instead of flags",
+	)
+
+	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
+		args, err := moduleArgs(cmd, flags, cmdOpts.stdin)
+		if err != nil {
+			return err
+		}
+
+		rpitx, err := newRPITX()
+		if err != nil {
+			return err
+		}
+
+		// The flag default can't know the args, e.g. an FT8 sequence needs
+		// a slot cycle per message
+		timeout := cmdOpts.timeout
+		if !cmd.Flags().Changed("timeout") {
+			timeout = rpitx.ArgsDefaultTimeout(name, args)
+		}
+
+		return runModule(cmd, rpitx, opts, name, args, timeout)
+	}
+
+	return cmd, nil
+}
+
+// addModuleFlags registers a flag for each schema property and returns them
+// sorted by name.
+func addModuleFlags(cmd *cobra.Command, schema moduleSchema) []moduleFlag {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	flags := make([]moduleFlag, 0, len(names))
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		kind := propertyFlagKind(prop)
+
+		usage := prop.Type
+		if usage == "" {
+			usage = "JSON"
+		}
+
+		if slices.Contains(schema.Required, name) {
+			usage += ", required"
+		}
+
+		switch kind {
+		case flagKindBool:
+			cmd.Flags().Bool(name, false, usage)
+		case flagKindInteger:
+			cmd.Flags().Int(name, 0, usage)
+		case flagKindStringSlice:
+			cmd.Flags().StringSlice(name, nil, usage)
+		case flagKindNumber:
+			cmd.Flags().String(name, "", usage+", units like MHz allowed")
+		case flagKindString, flagKindJSON:
+			cmd.Flags().String(name, "", usage)
+		}
+
+		flags = append(flags, moduleFlag{name: name, kind: kind})
+	}
+
+	return flags
+}
+
+// propertyFlagKind returns the flag kind for a schema property.
+func propertyFlagKind(prop schemaProperty) flagKind {
+	switch prop.Type {
+	case "string":
+		return flagKindString
+	case "number":
+		return flagKindNumber
+	case "integer":
+		return flagKindInteger
+	case "boolean":
+		return flagKindBool
+	case "array":
+		if prop.Items != nil && prop.Items.Type == "string" {
+			return flagKindStringSlice
+		}
+
+		return flagKindJSON
+	default:
+		return flagKindJSON
+	}
+}
+
+// moduleArgs builds the module JSON args from the flags that were set, or
+// reads them from stdin.
+func moduleArgs(
+	cmd *cobra.Command,
+	flags []moduleFlag,
+	fromStdin bool,
+) (json.RawMessage, error) {
+	if fromStdin {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, ctxerrors.Wrap(err, "failed to read stdin")
+		}
+
+		if !json.Valid(data) {
+			return nil, ctxerrors.Wrap(
+				commonerrors.ErrInvalidValue,
+				"stdin is not valid JSON",
+			)
+		}
+
+		return data, nil
+	}
+
+	args := map[string]any{}
+
+	for _, flag := range flags {
+		if !cmd.Flags().Changed(flag.name) {
+			continue
+		}
+
+		value, err := flagValue(cmd, flag)
+		if err != nil {
+			return nil, err
+		}
+
+		args[flag.name] = value
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to marshal args")
+	}
+
+	return data, nil
+}
+
+// flagValue returns the JSON value of a module flag.
+func flagValue(cmd *cobra.Command, flag moduleFlag) (any, error) {
+	flags := cmd.Flags()
+
+	switch flag.kind {
+	case flagKindBool:
+		return flags.GetBool(flag.name) //nolint:wrapcheck
+	case flagKindInteger:
+		return flags.GetInt(flag.name) //nolint:wrapcheck
+	case flagKindStringSlice:
+		return flags.GetStringSlice(flag.name) //nolint:wrapcheck
+	}
+
+	value, err := flags.GetString(flag.name)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "flag %s", flag.name)
+	}
+
+	switch flag.kind {
+	case flagKindNumber:
+		// Anything that isn't a plain number is passed on as a string for
+		// the frequency parsing, e.g. "14.070MHz"
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number, nil
+		}
+
+		return value, nil
+	case flagKindJSON:
+		if !json.Valid([]byte(value)) {
+			return nil, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"flag %s must be valid JSON",
+				flag.name,
+			)
+		}
+
+		return json.RawMessage(value), nil
+	default:
+		return value, nil
+	}
+}
+
+// runModule runs the module in the foreground, printing its output, until
+// it exits, the timeout elapses or the command gets SIGINT/SIGTERM.
+func runModule(
+	cmd *cobra.Command,
+	rpitx *gorpitx.RPITX,
+	opts *cliOptions,
+	name gorpitx.ModuleName,
+	args json.RawMessage,
+	timeout time.Duration,
+) error {
+	ctx, stopSignals := signal.NotifyContext(
+		cmd.Context(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stopSignals()
+
+	execution, err := rpitx.ExecAsync(
+		context.WithoutCancel(ctx),
+		name,
+		args,
+		timeout,
+	)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "failed to run %s", name)
+	}
+
+	if err := writeState(opts.stateFile, rpitx.Status()); err != nil {
+		_ = execution.Stop(context.WithoutCancel(ctx))
+
+		return err
+	}
+
+	defer func() { _ = os.Remove(opts.stateFile) }()
+
+	printed := printOutput(cmd, execution)
+
+	select {
+	case <-execution.Done():
+	case <-ctx.Done():
+		stopCtx, cancel := context.WithTimeout(
+			context.WithoutCancel(ctx),
+			stopGracePeriod,
+		)
+		defer cancel()
+
+		_ = execution.Stop(stopCtx)
+	}
+
+	err = execution.Wait()
+
+	<-printed
+
+	// Reaching the timeout or being interrupted is how the user asked the
+	// module to end
+	if err != nil && ctx.Err() == nil &&
+		!errors.Is(err, commonerrors.ErrTimeout) {
+		return ctxerrors.Wrapf(err, "%s failed", name)
+	}
+
+	return nil
+}
+
+// printOutput prints the execution output to the command stdout and stderr
+// and returns a channel closed once all of it has been printed.
+func printOutput(
+	cmd *cobra.Command,
+	execution *gorpitx.Execution,
+) <-chan struct{} {
+	stdout := make(chan string, outputBuffer)
+	stderr := make(chan string, outputBuffer)
+	printed := make(chan struct{})
+
+	execution.Stream(stdout, stderr)
+
+	go func() {
+		defer close(printed)
+
+		for stdout != nil || stderr != nil {
+			select {
+			case line, ok := <-stdout:
+				if !ok {
+					stdout = nil
+
+					continue
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout(), line)
+			case line, ok := <-stderr:
+				if !ok {
+					stderr = nil
+
+					continue
+				}
+
+				fmt.Fprintln(cmd.ErrOrStderr(), line)
+			}
+		}
+	}()
+
+	return printed
+}
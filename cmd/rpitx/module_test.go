@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/gorpitx"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRootCmd(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	// Set ENV=dev to get mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx, err := gorpitx.New()
+	require.NoError(t, err)
+
+	root, err := newRootCmd(rpitx, func() (*gorpitx.RPITX, error) {
+		return rpitx, nil
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	root.SetOut(&out)
+	root.SetErr(&out)
+
+	return root, &out
+}
+
+func TestNewRootCmd(t *testing.T) {
+	root, _ := newTestRootCmd(t)
+
+	for _, name := range []string{
+		gorpitx.ModuleNameMORSE,
+		gorpitx.ModuleNameTUNE,
+		gorpitx.ModuleNamePOCSAG,
+		"status",
+		"stop",
+	} {
+		cmd, _, err := root.Find([]string{name})
+		require.NoError(t, err, name)
+		assert.Equal(t, name, cmd.Name())
+	}
+
+	morse, _, err := root.Find([]string{gorpitx.ModuleNameMORSE})
+	require.NoError(t, err)
+
+	for _, flag := range []string{
+		"frequency",
+		"rate",
+		"message",
+		"timeout",
+		"stdin",
+	} {
+		assert.NotNil(t, morse.Flags().Lookup(flag), flag)
+	}
+}
+
+func TestNewRootCmd_LazyRPITX(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	catalog, err := gorpitx.New()
+	require.NoError(t, err)
+
+	// Like gorpitx.New for a non-root user in production mode
+	calls := 0
+	root, err := newRootCmd(catalog, func() (*gorpitx.RPITX, error) {
+		calls++
+
+		return nil, gorpitx.ErrRootRequired
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	root.SetOut(&out)
+	root.SetErr(&out)
+
+	stateFile := filepath.Join(t.TempDir(), stateFileName)
+
+	for _, args := range [][]string{
+		{"--help"},
+		{gorpitx.ModuleNameMORSE, "--help"},
+		{"status", "--state-file", stateFile},
+	} {
+		root.SetArgs(args)
+		require.NoError(t, root.Execute(), args)
+	}
+
+	assert.Zero(t, calls)
+
+	root.SetArgs([]string{
+		gorpitx.ModuleNameTUNE,
+		"--frequency", "434000000",
+		"--state-file", stateFile,
+	})
+	require.ErrorIs(t, root.Execute(), gorpitx.ErrRootRequired)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPropertyFlagKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		prop     schemaProperty
+		expected flagKind
+	}{
+		{"string", schemaProperty{Type: "string"}, flagKindString},
+		{"number", schemaProperty{Type: "number"}, flagKindNumber},
+		{"integer", schemaProperty{Type: "integer"}, flagKindInteger},
+		{"boolean", schemaProperty{Type: "boolean"}, flagKindBool},
+		{
+			"string array",
+			schemaProperty{
+				Type:  "array",
+				Items: &schemaProperty{Type: "string"},
+			},
+			flagKindStringSlice,
+		},
+		{
+			"object array",
+			schemaProperty{
+				Type:  "array",
+				Items: &schemaProperty{Type: "object"},
+			},
+			flagKindJSON,
+		},
+		{"object", schemaProperty{Type: "object"}, flagKindJSON},
+		{"untyped", schemaProperty{}, flagKindJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, propertyFlagKind(tt.prop))
+		})
+	}
+}
+
+func TestModuleArgs(t *testing.T) {
+	schema := moduleSchema{
+		Properties: map[string]schemaProperty{
+			"frequency": {Type: "number"},
+			"rate":      {Type: "integer"},
+			"message":   {Type: "string"},
+			"debug":     {Type: "boolean"},
+			"messages": {
+				Type:  "array",
+				Items: &schemaProperty{Type: "string"},
+			},
+			"rt": {Type: "object"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		flags     []string
+		stdin     string
+		fromStdin bool
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "only set flags are passed",
+			flags:    []string{"--frequency", "434000000", "--rate", "20"},
+			expected: `{"frequency":434000000,"rate":20}`,
+		},
+		{
+			name:     "frequency with unit passed as string",
+			flags:    []string{"--frequency", "14.070MHz"},
+			expected: `{"frequency":"14.070MHz"}`,
+		},
+		{
+			name: "all kinds",
+			flags: []string{
+				"--message", "CQ",
+				"--debug",
+				"--messages", "A,B",
+				"--rt", `{"text":"hi"}`,
+			},
+			expected: `{"debug":true,"message":"CQ",` +
+				`"messages":["A","B"],"rt":{"text":"hi"}}`,
+		},
+		{
+			name:      "invalid JSON flag",
+			flags:     []string{"--rt", "{"},
+			expectErr: true,
+		},
+		{
+			name:      "stdin",
+			stdin:     `{"frequency":"433.92 MHz"}`,
+			fromStdin: true,
+			expected:  `{"frequency":"433.92 MHz"}`,
+		},
+		{
+			name:      "invalid stdin",
+			stdin:     `nope`,
+			fromStdin: true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			flags := addModuleFlags(cmd, schema)
+
+			require.NoError(t, cmd.ParseFlags(tt.flags))
+			cmd.SetIn(strings.NewReader(tt.stdin))
+
+			args, err := moduleArgs(cmd, flags, tt.fromStdin)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(args))
+		})
+	}
+}
+
+func TestModuleCmd_Run(t *testing.T) {
+	root, out := newTestRootCmd(t)
+	stateFile := filepath.Join(t.TempDir(), stateFileName)
+
+	// The mock echoes every second and streaming may miss the first line,
+	// so run long enough for the second one
+	root.SetArgs([]string{
+		gorpitx.ModuleNameMORSE,
+		"--state-file", stateFile,
+		"--frequency", "14.070MHz",
+		"--rate", "20",
+		"--message", "CQ",
+		"--timeout", "1500ms",
+	})
+
+	require.NoError(t, root.Execute())
+	assert.Contains(t, out.String(), "mocking execution of morse 14070000")
+
+	// The state file is removed once the module is done
+	assert.NoFileExists(t, stateFile)
+}
+
+func TestModuleCmd_InvalidArgs(t *testing.T) {
+	root, _ := newTestRootCmd(t)
+
+	root.SetArgs([]string{
+		gorpitx.ModuleNameMORSE,
+		"--state-file", filepath.Join(t.TempDir(), stateFileName),
+		"--rate", "20",
+	})
+
+	assert.Error(t, root.Execute())
+}
+
+func TestModuleCmd_Stdin(t *testing.T) {
+	root, out := newTestRootCmd(t)
+
+	args, err := json.Marshal(map[string]any{"frequency": 434000000})
+	require.NoError(t, err)
+
+	root.SetIn(bytes.NewReader(args))
+	root.SetArgs([]string{
+		gorpitx.ModuleNameTUNE,
+		"--state-file", filepath.Join(t.TempDir(), stateFileName),
+		"--stdin",
+		"--timeout", "1500ms",
+	})
+
+	require.NoError(t, root.Execute())
+	assert.Contains(t, out.String(), "mocking execution of tune -f 434000000")
+}
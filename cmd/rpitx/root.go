@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/psyb0t/gorpitx"
+	"github.com/spf13/cobra"
+)
+
+// stateFileName is the default name of the file describing the running
+// execution, shared by the module commands and stop/status.
+const stateFileName = "rpitx-cli.json"
+
+// cliOptions are the flags shared by every command.
+type cliOptions struct {
+	stateFile string
+}
+
+// rpitxFactory builds the RPITX a module command transmits with.
+type rpitxFactory func() (*gorpitx.RPITX, error)
+
+// newRootCmd returns the rpitx command with a subcommand for each module
+// supported by rpitx plus stop and status. The catalog describes the modules
+// and newRPITX is only called by the module commands, once they run.
+func newRootCmd(
+	catalog *gorpitx.RPITX,
+	newRPITX rpitxFactory,
+) (*cobra.Command, error) {
+	opts := &cliOptions{}
+
+	root := &cobra.Command{
+		Use:          "rpitx",
+		Short:        "Transmit with rpitx modules",
+		SilenceUsage: true,
+	}
+
+	root.PersistentFlags().StringVar(
+		&opts.stateFile,
+		"state-file",
+		filepath.Join(defaultStateDir(), stateFileName),
+		"file describing the running execution, used by stop and status",
+	)
+
+	for _, name := range catalog.GetSupportedModules() {
+		cmd, err := newModuleCmd(catalog, newRPITX, opts, name)
+		if err != nil {
+			return nil, err
+		}
+
+		root.AddCommand(cmd)
+	}
+
+	root.AddCommand(newStatusCmd(opts), newStopCmd(opts))
+
+	return root, nil
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gorpitx"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// stateFilePerm keeps the state file private to the user running rpitx.
+	stateFilePerm = 0o600
+
+	// stateDirPerm keeps the state dir private to the user running rpitx.
+	stateDirPerm = 0o700
+
+	// stateDirSharedPerm are the permission bits that let other users
+	// replace files in the state dir.
+	stateDirSharedPerm = 0o022
+
+	// rootStateDir holds the state file when running as root, which is how
+	// the modules transmit.
+	rootStateDir = "/run/rpitx"
+
+	// procStartTimeField is the index of the process start time among the
+	// /proc/<pid>/stat fields following the command name.
+	procStartTimeField = 19
+
+	// envVarNameRuntimeDir names the per-user runtime dir used for the state
+	// file of non-root users.
+	envVarNameRuntimeDir = "XDG_RUNTIME_DIR"
+)
+
+// cliState describes the execution run by a module command, so other rpitx
+// invocations can report on it and stop it. StartTime is the process start
+// time from /proc, telling the process apart from a later one reusing PID.
+type cliState struct {
+	PID       int                `json:"pid"`
+	StartTime uint64             `json:"startTime"`
+	Module    gorpitx.ModuleName `json:"module"`
+	StartedAt time.Time          `json:"startedAt"`
+	DevMode   bool               `json:"devMode"`
+}
+
+// defaultStateDir returns the private runtime dir holding the state file:
+// /run/rpitx for root and the user's runtime dir, or a per-user dir in the
+// temp dir, otherwise.
+func defaultStateDir() string {
+	uid := os.Geteuid()
+	if uid == 0 {
+		return rootStateDir
+	}
+
+	if dir := os.Getenv(envVarNameRuntimeDir); dir != "" {
+		return filepath.Join(dir, "rpitx")
+	}
+
+	return filepath.Join(os.TempDir(), "rpitx-"+strconv.Itoa(uid))
+}
+
+// writeState records the running execution in the state file. The file is
+// created in a dir no other user can write to and never through a symlink,
+// so the state can't be redirected to overwrite another file.
+func writeState(path string, status gorpitx.ExecutionStatus) error {
+	startTime, err := processStartTime(os.Getpid())
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cliState{
+		PID:       os.Getpid(),
+		StartTime: startTime,
+		Module:    status.Module,
+		StartedAt: status.StartedAt,
+		DevMode:   status.DevMode,
+	})
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to marshal state")
+	}
+
+	if err := ensureStateDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	file, err := createStateFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return ctxerrors.Wrapf(err, "failed to write state file %s", path)
+	}
+
+	if err := file.Close(); err != nil {
+		return ctxerrors.Wrapf(err, "failed to write state file %s", path)
+	}
+
+	return nil
+}
+
+// ensureStateDir creates the state dir if needed and checks it is a real
+// dir owned by the user or root that no other user can write to.
+func ensureStateDir(dir string) error {
+	err := os.Mkdir(dir, stateDirPerm)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return ctxerrors.Wrapf(err, "failed to create state dir %s", dir)
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "failed to stat state dir %s", dir)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !info.IsDir() || !ok ||
+		(int(stat.Uid) != os.Geteuid() && stat.Uid != 0) ||
+		info.Mode().Perm()&stateDirSharedPerm != 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"state dir %s must be a dir owned by you or root that other "+
+				"users can't write to",
+			dir,
+		)
+	}
+
+	return nil
+}
+
+// createStateFile creates the state file, failing on symlinks and on a
+// state file of an execution that is still running. A state file left by
+// a process that is gone is replaced.
+func createStateFile(path string) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL | syscall.O_NOFOLLOW
+
+	file, err := os.OpenFile(path, flags, stateFilePerm)
+	if !errors.Is(err, os.ErrExist) {
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "failed to create state file %s", path)
+		}
+
+		return file, nil
+	}
+
+	state, running, err := readState(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if running {
+		return nil, ctxerrors.Wrapf(
+			gorpitx.ErrExecuting,
+			"pid %d is running %s",
+			state.PID, state.Module,
+		)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to remove stale state file %s", path)
+	}
+
+	file, err = os.OpenFile(path, flags, stateFilePerm)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to create state file %s", path)
+	}
+
+	return file, nil
+}
+
+// readState returns the execution recorded in the state file. It reports
+// false when there is none or the process that wrote it is gone.
+func readState(path string) (cliState, bool, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return cliState{}, false, nil
+	}
+
+	if err != nil {
+		return cliState{}, false, ctxerrors.Wrapf(
+			err,
+			"failed to open state file %s",
+			path,
+		)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return cliState{}, false, ctxerrors.Wrapf(
+			err,
+			"failed to read state file %s",
+			path,
+		)
+	}
+
+	var state cliState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return cliState{}, false, ctxerrors.Wrapf(
+			err,
+			"failed to decode state file %s",
+			path,
+		)
+	}
+
+	return state, processRunning(state), nil
+}
+
+// processRunning reports whether the process that wrote the state is still
+// running: a process with its pid exists and started at the same time.
+func processRunning(state cliState) bool {
+	if state.PID <= 0 {
+		return false
+	}
+
+	startTime, err := processStartTime(state.PID)
+
+	return err == nil && startTime == state.StartTime
+}
+
+// processStartTime returns the start time of the process, in clock ticks
+// since boot, from /proc/<pid>/stat.
+func processStartTime(pid int) (uint64, error) {
+	path := filepath.Join("/proc", strconv.Itoa(pid), "stat")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, ctxerrors.Wrapf(err, "failed to read %s", path)
+	}
+
+	// The command name in parentheses may contain spaces, the fields start
+	// after its closing one
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, ctxerrors.Wrapf(commonerrors.ErrInvalidValue, "%s", path)
+	}
+
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) <= procStartTimeField {
+		return 0, ctxerrors.Wrapf(commonerrors.ErrInvalidValue, "%s", path)
+	}
+
+	startTime, err := strconv.ParseUint(fields[procStartTimeField], 10, 64)
+	if err != nil {
+		return 0, ctxerrors.Wrapf(err, "failed to parse start time in %s", path)
+	}
+
+	return startTime, nil
+}
+
+// newStatusCmd returns the command printing the status of the execution
+// run by another rpitx invocation, as JSON.
+func newStatusCmd(opts *cliOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Print the status of the running module as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			state, running, err := readState(opts.stateFile)
+			if err != nil {
+				return err
+			}
+
+			status := gorpitx.ExecutionStatus{}
+			if running {
+				status = gorpitx.ExecutionStatus{
+					Executing: true,
+					Module:    state.Module,
+					StartedAt: state.StartedAt,
+					Elapsed:   time.Since(state.StartedAt),
+					DevMode:   state.DevMode,
+				}
+			}
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(status); err != nil {
+				return ctxerrors.Wrap(err, "failed to encode status")
+			}
+
+			return nil
+		},
+	}
+}
+
+// newStopCmd returns the command asking the rpitx invocation running a
+// module to stop it gracefully.
+func newStopCmd(opts *cliOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Gracefully stop the running module",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			state, running, err := readState(opts.stateFile)
+			if err != nil {
+				return err
+			}
+
+			if !running {
+				return gorpitx.ErrNotExecuting
+			}
+
+			// readState matched the start time, so the pid wasn't reused
+			// by another process since the state was written
+			if err := syscall.Kill(state.PID, syscall.SIGTERM); err != nil {
+				return ctxerrors.Wrapf(err, "failed to signal pid %d", state.PID)
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"stopping %s (pid %d)\n",
+				state.Module, state.PID,
+			)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/gorpitx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), stateFileName)
+
+	_, running, err := readState(path)
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	startedAt := time.Now().Truncate(time.Second)
+
+	require.NoError(t, writeState(path, gorpitx.ExecutionStatus{
+		Executing: true,
+		Module:    gorpitx.ModuleNameTUNE,
+		StartedAt: startedAt,
+		DevMode:   true,
+	}))
+
+	state, running, err := readState(path)
+	require.NoError(t, err)
+	assert.True(t, running)
+	assert.Equal(t, os.Getpid(), state.PID)
+	assert.NotZero(t, state.StartTime)
+	assert.Equal(t, gorpitx.ModuleNameTUNE, state.Module)
+	assert.True(t, startedAt.Equal(state.StartedAt))
+	assert.True(t, state.DevMode)
+
+	// A state file left by a dead process is not running
+	data, err := json.Marshal(cliState{PID: -1})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, stateFilePerm))
+
+	_, running, err = readState(path)
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	// Nor is one whose pid now belongs to a process started at another time
+	data, err = json.Marshal(cliState{PID: os.Getpid(), StartTime: 1})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, stateFilePerm))
+
+	_, running, err = readState(path)
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	require.NoError(t, os.WriteFile(path, []byte("nope"), stateFilePerm))
+
+	_, _, err = readState(path)
+	assert.Error(t, err)
+}
+
+func TestStatusAndStopCmd(t *testing.T) {
+	root, out := newTestRootCmd(t)
+	stateFile := filepath.Join(t.TempDir(), stateFileName)
+
+	root.SetArgs([]string{"status", "--state-file", stateFile})
+	require.NoError(t, root.Execute())
+
+	var status gorpitx.ExecutionStatus
+	require.NoError(t, json.Unmarshal(out.Bytes(), &status))
+	assert.False(t, status.Executing)
+
+	root.SetArgs([]string{"stop", "--state-file", stateFile})
+	assert.ErrorIs(t, root.Execute(), gorpitx.ErrNotExecuting)
+
+	require.NoError(t, writeState(stateFile, gorpitx.ExecutionStatus{
+		Module:    gorpitx.ModuleNameMORSE,
+		StartedAt: time.Now(),
+	}))
+
+	out.Reset()
+	root.SetArgs([]string{"status", "--state-file", stateFile})
+	require.NoError(t, root.Execute())
+
+	require.NoError(t, json.Unmarshal(out.Bytes(), &status))
+	assert.True(t, status.Executing)
+	assert.Equal(t, gorpitx.ModuleNameMORSE, status.Module)
+}
+
+func TestWriteState_StateFile(t *testing.T) {
+	status := gorpitx.ExecutionStatus{
+		Module:    gorpitx.ModuleNameTUNE,
+		StartedAt: time.Now(),
+	}
+
+	t.Run("creates private state dir", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "rpitx")
+
+		require.NoError(t, writeState(
+			filepath.Join(dir, stateFileName),
+			status,
+		))
+
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(stateDirPerm), info.Mode().Perm())
+	})
+
+	t.Run("replaces stale state", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), stateFileName)
+
+		data, err := json.Marshal(cliState{PID: -1})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, stateFilePerm))
+
+		require.NoError(t, writeState(path, status))
+
+		state, running, err := readState(path)
+		require.NoError(t, err)
+		assert.True(t, running)
+		assert.Equal(t, os.Getpid(), state.PID)
+	})
+
+	t.Run("running execution", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), stateFileName)
+
+		require.NoError(t, writeState(path, status))
+		assert.ErrorIs(t, writeState(path, status), gorpitx.ErrExecuting)
+	})
+
+	t.Run("symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target")
+		path := filepath.Join(dir, stateFileName)
+
+		require.NoError(t, os.WriteFile(target, []byte("keep"), stateFilePerm))
+		require.NoError(t, os.Symlink(target, path))
+
+		require.Error(t, writeState(path, status))
+
+		data, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, "keep", string(data))
+	})
+
+	t.Run("dir writable by others", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Chmod(dir, 0o777)) //nolint:gosec
+
+		err := writeState(filepath.Join(dir, stateFileName), status)
+		assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	})
+}
@@ -1,24 +1,281 @@
 package gorpitx
 
 import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
 	"github.com/psyb0t/gonfiguration"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	envVarNameGorpitxPath = "GORPITX_PATH"
+	envVarNameRpitxPath   = "RPITX_PATH"
 	defaultPath           = "$HOME/rpitx"
 )
 
+// Config holds the RPITX settings. Path comes from the environment by
+// default, and every field can be loaded from a file with LoadConfig.
 type Config struct {
-	Path string `env:"GORPITX_PATH"`
+	// Path is the directory holding the rpitx binaries. A leading ~ and
+	// $HOME are expanded.
+	Path string `env:"GORPITX_PATH" json:"path"`
+
+	// BinaryPaths overrides the binary run for a module, e.g. a
+	// system-installed pocsag, instead of Path/<module>. The paths are
+	// expanded like Path and must exist. Script modules ignore it.
+	BinaryPaths map[ModuleName]string `json:"binaryPaths,omitempty"`
+
+	// ScriptDir is where the embedded scripts are deployed, expanded like
+	// Path. Empty uses /tmp.
+	ScriptDir string `json:"scriptDir"`
+
+	// DefaultTimeout is the execution timeout used by ExecDefault, the CLI
+	// and the HTTP API for modules without a natural runtime when a request
+	// doesn't set one. Zero runs until the module exits.
+	DefaultTimeout time.Duration `json:"defaultTimeout"`
+
+	// FrequencyPolicy restricts the allowed frequencies like
+	// WithFrequencyPolicy, which takes precedence over it.
+	FrequencyPolicy *FrequencyPolicy `json:"frequencyPolicy,omitempty"`
+
+	// AudioSockBroadcastSampleRate is the sample rate AudioSockBroadcast
+	// uses when a request doesn't set SampleRate, e.g. 44100 for a site
+	// standardized on it. Zero keeps the built-in 48000 Hz.
+	AudioSockBroadcastSampleRate int `json:"audioSockBroadcastSampleRate,omitempty"` //nolint:lll
+
+	// LogLevel sets the level of the default logger, e.g. "debug" or
+	// "warn". It has no effect with WithLogger.
+	LogLevel string `json:"logLevel"`
+
+	// Mock controls the process run instead of the binaries in dev mode.
+	Mock MockConfig `json:"-"`
 }
 
+// unsupportedConfigFields maps the settings rpitx has no way to honor to
+// the reason they are rejected. rpitx drives GPCLK0 on GPIO 4 in every tool
+// and none of its binaries or scripts reads a pin or filter setting, so a
+// GPIO or filter option would be silently ignored on the air.
+//
+//nolint:gochecknoglobals
+var unsupportedConfigFields = map[string]string{
+	"gpio": "rpitx always transmits on GPIO 4, none of its tools " +
+		"selects another pin",
+	"enableFilter": "rpitx has no filter setting, wire a low-pass filter " +
+		"to GPIO 4",
+}
+
+// UnmarshalJSON decodes a Config, taking DefaultTimeout as a Go duration
+// string such as "30s". Settings rpitx can't honor, like gpio, are rejected
+// with the reason.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plainConfig Config
+
+	if err := rejectUnsupportedConfigFields(data); err != nil {
+		return err
+	}
+
+	aux := struct {
+		*plainConfig
+
+		DefaultTimeout string `json:"defaultTimeout"`
+	}{plainConfig: (*plainConfig)(c)}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&aux); err != nil {
+		return ctxerrors.Wrap(commonerrors.ErrInvalidValue, err.Error())
+	}
+
+	if aux.DefaultTimeout == "" {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(aux.DefaultTimeout)
+	if err != nil || timeout < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid default timeout %q",
+			aux.DefaultTimeout,
+		)
+	}
+
+	c.DefaultTimeout = timeout
+
+	return nil
+}
+
+// rejectUnsupportedConfigFields fails when the config object sets one of
+// the unsupportedConfigFields. Anything else is left to the decoder.
+func rejectUnsupportedConfigFields(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil //nolint:nilerr // reported by the decoder
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(unsupportedConfigFields)) {
+		if _, ok := fields[name]; ok {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"%s is not supported: %s",
+				name, unsupportedConfigFields[name],
+			)
+		}
+	}
+
+	return nil
+}
+
+// LoadConfig reads a JSON (.json) or YAML (.yaml, .yml) config file. Fields
+// missing from the file keep their value from the environment, e.g.
+//
+//	path: /opt/rpitx
+//	scriptDir: /var/lib/gorpitx
+//	defaultTimeout: 10m
+//	logLevel: warn
+//	frequencyPolicy:
+//	  minHz: 144000000
+//	  maxHz: 148000000
+func LoadConfig(path string) (Config, error) {
+	cfg, err := parseConfig()
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.loadFile(path); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile decodes the config file onto c, so the fields missing from the
+// file keep their current value. c may be partly updated on failure.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // reading it is the point
+	if err != nil {
+		return ctxerrors.Wrapf(err, "could not read config %s", path)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		// YAML goes through JSON so both formats share the field names
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return ctxerrors.Wrapf(err, "could not parse config %s", path)
+		}
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unsupported config format %q, want .json, .yaml or .yml",
+			filepath.Ext(path),
+		)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return ctxerrors.Wrapf(err, "could not parse config %s", path)
+	}
+
+	if err := c.validate(); err != nil {
+		return ctxerrors.Wrapf(err, "invalid config %s", path)
+	}
+
+	return nil
+}
+
+// clone returns a copy of c that shares no maps, slices or pointers with
+// it, so decoding onto the copy leaves c untouched.
+func (c Config) clone() Config {
+	c.BinaryPaths = maps.Clone(c.BinaryPaths)
+
+	if c.FrequencyPolicy != nil {
+		policy := *c.FrequencyPolicy
+		policy.Blocked = slices.Clone(policy.Blocked)
+		c.FrequencyPolicy = &policy
+	}
+
+	return c
+}
+
+// yamlToJSON converts a YAML document to JSON.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, ctxerrors.Wrap(commonerrors.ErrInvalidValue, err.Error())
+	}
+
+	if doc == nil {
+		return []byte("{}"), nil
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, ctxerrors.Wrap(commonerrors.ErrInvalidValue, err.Error())
+	}
+
+	return out, nil
+}
+
+// validate checks the values that can't be checked while decoding.
+func (c Config) validate() error {
+	if c.LogLevel != "" {
+		if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"invalid log level %q",
+				c.LogLevel,
+			)
+		}
+	}
+
+	if c.DefaultTimeout < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"default timeout must not be negative, got %s",
+			c.DefaultTimeout,
+		)
+	}
+
+	if c.AudioSockBroadcastSampleRate < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"audiosock broadcast sample rate must not be negative, got %d",
+			c.AudioSockBroadcastSampleRate,
+		)
+	}
+
+	if c.FrequencyPolicy != nil {
+		if err := c.FrequencyPolicy.validate(); err != nil {
+			return ctxerrors.Wrap(err, "invalid frequency policy")
+		}
+	}
+
+	return nil
+}
+
+// parseConfig reads the config from the environment. The rpitx path comes
+// from GORPITX_PATH, then RPITX_PATH, then defaults to $HOME/rpitx.
 func parseConfig() (Config, error) {
 	cfg := Config{}
 
+	defaultRpitxPath := defaultPath
+	if path := os.Getenv(envVarNameRpitxPath); path != "" {
+		defaultRpitxPath = path
+	}
+
 	gonfiguration.SetDefaults(map[string]any{
-		envVarNameGorpitxPath: defaultPath,
+		envVarNameGorpitxPath: defaultRpitxPath,
 	})
 
 	if err := gonfiguration.Parse(&cfg); err != nil {
@@ -27,3 +284,25 @@ func parseConfig() (Config, error) {
 
 	return cfg, nil
 }
+
+// expandPath expands a leading ~ and $HOME in path to the home directory of
+// the current user. Other variables are left as they are. The path is
+// returned unchanged when the home directory is unknown.
+func expandPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = home + path[1:]
+	}
+
+	return os.Expand(path, func(name string) string {
+		if name == "HOME" {
+			return home
+		}
+
+		return "${" + name + "}"
+	})
+}
@@ -0,0 +1,400 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a config file with the given name and content to a
+// temp dir and returns its path.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// setOrUnsetEnv sets the environment variable for the test, or unsets it
+// when value is empty.
+func setOrUnsetEnv(t *testing.T, name, value string) {
+	t.Helper()
+	t.Setenv(name, value)
+
+	if value == "" {
+		require.NoError(t, os.Unsetenv(name))
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv(envVarNameGorpitxPath, "/env/rpitx")
+
+	policy := &FrequencyPolicy{
+		MinHz:   144000000,
+		MaxHz:   148000000,
+		Blocked: []FrequencyRange{{MinHz: 145800000, MaxHz: 146000000}},
+	}
+
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		expectedErr error
+		expected    Config
+	}{
+		{
+			name: "json",
+			file: "gorpitx.json",
+			content: `{
+				"path": "/opt/rpitx",
+				"binaryPaths": {"pocsag": "/usr/bin/pocsag"},
+				"scriptDir": "/var/lib/gorpitx",
+				"defaultTimeout": "10m",
+				"audioSockBroadcastSampleRate": 44100,
+				"logLevel": "warn",
+				"frequencyPolicy": {
+					"minHz": 144000000,
+					"maxHz": 148000000,
+					"blocked": [{"minHz": 145800000, "maxHz": 146000000}]
+				}
+			}`,
+			expected: Config{
+				Path: "/opt/rpitx",
+				BinaryPaths: map[ModuleName]string{
+					ModuleNamePOCSAG: "/usr/bin/pocsag",
+				},
+				ScriptDir:                    "/var/lib/gorpitx",
+				DefaultTimeout:               10 * time.Minute,
+				AudioSockBroadcastSampleRate: 44100,
+				LogLevel:                     "warn",
+				FrequencyPolicy:              policy,
+			},
+		},
+		{
+			name: "yaml",
+			file: "gorpitx.yaml",
+			content: "path: /opt/rpitx\n" +
+				"scriptDir: /var/lib/gorpitx\n" +
+				"defaultTimeout: 10m\n" +
+				"logLevel: warn\n" +
+				"frequencyPolicy:\n" +
+				"  minHz: 144000000\n" +
+				"  maxHz: 148000000\n" +
+				"  blocked:\n" +
+				"    - minHz: 145800000\n" +
+				"      maxHz: 146000000\n",
+			expected: Config{
+				Path:            "/opt/rpitx",
+				ScriptDir:       "/var/lib/gorpitx",
+				DefaultTimeout:  10 * time.Minute,
+				LogLevel:        "warn",
+				FrequencyPolicy: policy,
+			},
+		},
+		{
+			name:     "missing fields keep the environment",
+			file:     "gorpitx.yml",
+			content:  "scriptDir: /srv\n",
+			expected: Config{Path: "/env/rpitx", ScriptDir: "/srv"},
+		},
+		{
+			name:     "empty yaml",
+			file:     "gorpitx.yml",
+			content:  "",
+			expected: Config{Path: "/env/rpitx"},
+		},
+		{
+			name:        "unknown field",
+			file:        "gorpitx.json",
+			content:     `{"pth": "/opt/rpitx"}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "invalid timeout",
+			file:        "gorpitx.yaml",
+			content:     "defaultTimeout: soon\n",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "negative timeout",
+			file:        "gorpitx.json",
+			content:     `{"defaultTimeout": "-1s"}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "invalid log level",
+			file:        "gorpitx.json",
+			content:     `{"logLevel": "loud"}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "negative audiosock broadcast sample rate",
+			file:        "gorpitx.json",
+			content:     `{"audioSockBroadcastSampleRate": -1}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "invalid frequency policy",
+			file:        "gorpitx.json",
+			content:     `{"frequencyPolicy": {"minHz": 2, "maxHz": 1}}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "gpio is not supported",
+			file:        "gorpitx.json",
+			content:     `{"gpio": 20}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "enableFilter is not supported",
+			file:        "gorpitx.yaml",
+			content:     "enableFilter: true\n",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "invalid yaml",
+			file:        "gorpitx.yaml",
+			content:     "path: [\n",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "unsupported format",
+			file:        "gorpitx.toml",
+			content:     `path = "/opt/rpitx"`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(writeConfigFile(t, tt.file, tt.content))
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestConfig_UnmarshalJSON_Unsupported(t *testing.T) {
+	var cfg Config
+
+	err := json.Unmarshal([]byte(`{"gpio": 20}`), &cfg)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.Contains(t, err.Error(), "gpio is not supported")
+	assert.Contains(t, err.Error(), "GPIO 4")
+
+	err = json.Unmarshal([]byte(`{"enableFilter": true}`), &cfg)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.Contains(t, err.Error(), "enableFilter is not supported")
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseConfig_Path(t *testing.T) {
+	tests := []struct {
+		name        string
+		gorpitxPath string
+		rpitxPath   string
+		expected    string
+	}{
+		{"default", "", "", defaultPath},
+		{"RPITX_PATH", "", "/opt/rpitx", "/opt/rpitx"},
+		{"GORPITX_PATH wins", "/srv/rpitx", "/opt/rpitx", "/srv/rpitx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setOrUnsetEnv(t, envVarNameGorpitxPath, tt.gorpitxPath)
+			setOrUnsetEnv(t, envVarNameRpitxPath, tt.rpitxPath)
+
+			cfg, err := parseConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Path)
+		})
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	t.Setenv("HOME", "/home/pi")
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"$HOME/rpitx", "/home/pi/rpitx"},
+		{"${HOME}/rpitx", "/home/pi/rpitx"},
+		{"~/rpitx", "/home/pi/rpitx"},
+		{"~", "/home/pi"},
+		{"/opt/rpitx", "/opt/rpitx"},
+		{"/opt/~rpitx", "/opt/~rpitx"},
+		{"$OTHER/rpitx", "${OTHER}/rpitx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, expandPath(tt.path))
+		})
+	}
+}
+
+func TestWithConfig(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+	t.Setenv(envVarNameGorpitxPath, "/env/rpitx")
+
+	policy := FrequencyPolicy{MaxHz: 148000000}
+
+	r, err := New(WithConfig(Config{
+		ScriptDir:       "/srv/scripts",
+		DefaultTimeout:  time.Minute,
+		FrequencyPolicy: &policy,
+		LogLevel:        "debug",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/env/rpitx", r.config.Path)
+	assert.Equal(t, "/srv/scripts", r.scriptDir())
+	assert.Equal(t, time.Minute, r.DefaultTimeout())
+	assert.Equal(t, &policy, r.frequencyPolicy)
+
+	logger, ok := r.log().(*logrus.Logger)
+	require.True(t, ok)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.NotSame(t, logrus.StandardLogger(), logger)
+
+	// Explicit options win over the config
+	explicit := FrequencyPolicy{MaxHz: 1000000}
+
+	r, err = New(
+		WithConfig(Config{FrequencyPolicy: &policy, LogLevel: "debug"}),
+		WithFrequencyPolicy(explicit),
+		WithLogger(NopLogger()),
+		WithPath("/opt/rpitx"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, &explicit, r.frequencyPolicy)
+	assert.Equal(t, NopLogger(), r.log())
+	assert.Equal(t, "/opt/rpitx", r.config.Path)
+	assert.Equal(t, defaultScriptDir, r.scriptDir())
+
+	_, err = New(WithConfig(Config{LogLevel: "loud"}))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestWithConfigFile(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	path := writeConfigFile(t, "gorpitx.yaml",
+		"path: /opt/rpitx\ndefaultTimeout: 30s\n")
+
+	r, err := New(WithConfigFile(path))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/opt/rpitx", r.config.Path)
+	assert.Equal(t, 30*time.Second, r.DefaultTimeout())
+
+	_, err = New(WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	bad := writeConfigFile(t, "gorpitx.json", `{"logLevel": "loud"}`)
+
+	_, err = New(WithConfigFile(bad))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestWithConfigFile_KeepsEarlierOptions(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	mock := MockConfig{Line: "mocked", Iterations: 2}
+	policy := FrequencyPolicy{MinHz: 144000000, MaxHz: 148000000}
+	path := writeConfigFile(t, "gorpitx.yaml", "defaultTimeout: 30s\n")
+
+	r, err := New(WithMock(mock), WithConfigFile(path))
+	require.NoError(t, err)
+
+	assert.Equal(t, mock, r.config.Mock)
+	assert.Equal(t, 30*time.Second, r.DefaultTimeout())
+
+	r, err = New(
+		WithConfig(Config{
+			BinaryPaths:     map[ModuleName]string{ModuleNamePOCSAG: "/usr/bin/pocsag"},
+			FrequencyPolicy: &policy,
+		}),
+		WithMock(mock),
+		WithPath("/opt/rpitx"),
+		WithConfigFile(path),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, mock, r.config.Mock)
+	assert.Equal(t, "/opt/rpitx", r.config.Path)
+	assert.Equal(t, "/usr/bin/pocsag", r.config.BinaryPaths[ModuleNamePOCSAG])
+	assert.Equal(t, &policy, r.config.FrequencyPolicy)
+	assert.Equal(t, 30*time.Second, r.DefaultTimeout())
+
+	// Fields set in the file win, merged into the existing maps
+	path = writeConfigFile(t, "gorpitx.json", `{
+		"path": "/srv/rpitx",
+		"binaryPaths": {"tune": "/usr/bin/tune"},
+		"frequencyPolicy": {"maxHz": 146000000}
+	}`)
+
+	binaryPaths := map[ModuleName]string{ModuleNamePOCSAG: "/usr/bin/pocsag"}
+
+	r, err = New(
+		WithConfig(Config{BinaryPaths: binaryPaths, FrequencyPolicy: &policy}),
+		WithMock(mock),
+		WithConfigFile(path),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, mock, r.config.Mock)
+	assert.Equal(t, "/srv/rpitx", r.config.Path)
+	assert.Equal(t, map[ModuleName]string{
+		ModuleNamePOCSAG: "/usr/bin/pocsag",
+		ModuleNameTUNE:   "/usr/bin/tune",
+	}, r.config.BinaryPaths)
+	assert.Equal(t, 146000000.0, r.config.FrequencyPolicy.MaxHz)
+	assert.Equal(t, 144000000.0, r.config.FrequencyPolicy.MinHz)
+
+	// The values handed to earlier options aren't modified
+	assert.Len(t, binaryPaths, 1)
+	assert.Equal(t, 148000000.0, policy.MaxHz)
+}
+
+func TestRPITX_ScriptDir(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	dir := t.TempDir()
+	rpitx := &RPITX{
+		config:  Config{Path: "/opt/rpitx", ScriptDir: dir},
+		modules: defaultModules(),
+	}
+
+	_, cmdArgs, _, err := rpitx.prepareCommand(
+		ModuleNameAudioSockBroadcast,
+		[]byte(`{"frequency": 434000000, "socketPath": "/tmp/audio.sock"}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, audioSockBroadcastName), cmdArgs[1])
+	assert.FileExists(t, filepath.Join(dir, audioSockBroadcastName))
+	assert.FileExists(t, filepath.Join(dir, modulationName))
+}
@@ -0,0 +1,48 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// durationEstimator is implemented by modules whose transmission length can
+// be worked out from their args, e.g. FT8 sending a fixed 12.64s frame. ok
+// is false when it can't, like for a repeating transmission.
+type durationEstimator interface {
+	estimatedDuration() (d time.Duration, ok bool)
+}
+
+// EstimateTransmitDuration parses the args for the module like ValidateArgs
+// and returns how long the transmission takes once its process has
+// started, e.g. for a scheduler to know how long a job ties up the
+// transmitter. The math is known for SSTV (picture height and mode line
+// time), FT8 (12.64s per frame) and MORSE (message length and rate). ok is
+// false for unknown modules, invalid args and modules without a fixed
+// length, like TUNE or AudioSockBroadcast. Like ValidateArgs, it is safe to
+// call while a module is executing.
+func (r *RPITX) EstimateTransmitDuration(
+	name ModuleName,
+	args json.RawMessage,
+) (time.Duration, bool) {
+	module, err := r.newModule(name)
+	if err != nil {
+		return 0, false
+	}
+
+	if _, _, err := r.parseModuleArgs(module, args); err != nil {
+		return 0, false
+	}
+
+	return moduleDuration(module)
+}
+
+// moduleDuration returns the expected length of the transmission of the
+// parsed module.
+func moduleDuration(module Module) (time.Duration, bool) {
+	estimator, ok := module.(durationEstimator)
+	if !ok {
+		return 0, false
+	}
+
+	return estimator.estimatedDuration()
+}
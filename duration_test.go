@@ -0,0 +1,94 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPITX_EstimateTransmitDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		moduleName ModuleName
+		args       string
+		expected   time.Duration
+		expectedOK bool
+	}{
+		{
+			name:       "ft8",
+			moduleName: ModuleNameFT8,
+			args:       `{"frequency": 14074000, "message": "CQ W1AW FN31"}`,
+			expected:   ft8TxDuration,
+			expectedOK: true,
+		},
+		{
+			name:       "sstv",
+			moduleName: ModuleNamePISSSTV,
+			args: `{"pictureFile": ".fixtures/martin1.rgb", ` +
+				`"frequency": 144500000, "mode": "martin1"}`,
+			expected:   910*time.Millisecond + 256*446446*time.Microsecond,
+			expectedOK: true,
+		},
+		{
+			name:       "morse",
+			moduleName: ModuleNameMORSE,
+			args:       `{"frequency": 14070000, "rate": 60, "message": "PARIS"}`,
+			expected:   43 * time.Second,
+			expectedOK: true,
+		},
+		{
+			name:       "repeating ft8",
+			moduleName: ModuleNameFT8,
+			args: `{"frequency": 14074000, "message": "CQ W1AW FN31", ` +
+				`"repeat": true}`,
+		},
+		{
+			name:       "tune runs until stopped",
+			moduleName: ModuleNameTUNE,
+			args:       `{"frequency": 434000000}`,
+		},
+		{
+			name:       "invalid args",
+			moduleName: ModuleNameMORSE,
+			args:       `{"rate": 60, "message": "PARIS"}`,
+		},
+		{
+			name:       "unknown module",
+			moduleName: "nonexistent",
+			args:       `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{modules: defaultModules()}
+
+			d, ok := rpitx.EstimateTransmitDuration(
+				tt.moduleName, json.RawMessage(tt.args),
+			)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestRPITX_EstimateTransmitDuration_KeepsRegisteredModule(t *testing.T) {
+	morse := &MORSE{}
+	rpitx := &RPITX{modules: map[ModuleName]Module{ModuleNameMORSE: morse}}
+
+	args := json.RawMessage(
+		`{"frequency": 434000000, "rate": 20, "message": "CQ"}`,
+	)
+
+	_, ok := rpitx.EstimateTransmitDuration(ModuleNameMORSE, args)
+	assert.True(t, ok)
+
+	errs := rpitx.ValidateBatch([]BatchItem{
+		{Module: ModuleNameMORSE, Args: args},
+	})
+	assert.Equal(t, []error{nil}, errs)
+
+	assert.Equal(t, &MORSE{}, morse)
+}
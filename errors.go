@@ -7,14 +7,38 @@ import (
 // Module execution errors.
 var (
 	ErrUnknownModule = errors.New("unknown module")
+	ErrModuleExists  = errors.New("module already registered")
 	ErrExecuting     = errors.New("RPITX is busy executing another command")
 	ErrNotExecuting  = errors.New("RPITX is not executing a command")
 )
 
+// Transmit limit errors.
+var (
+	ErrMaxDurationExceeded = errors.New("max transmit duration exceeded")
+	ErrCoolingDown         = errors.New("transmitter is cooling down")
+)
+
+// Privilege errors.
+var (
+	ErrRootRequired = errors.New("root is required in production mode")
+)
+
+// Binary check errors.
+var (
+	ErrNotExecutable = errors.New("binary is not executable")
+)
+
+// Execution queue errors.
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job already finished")
+)
+
 // Frequency validation errors (still used by utils.go).
 var (
 	ErrFreqOutOfRange = errors.New("frequency out of RPiTX range")
 	ErrFreqPrecision  = errors.New("frequency precision too high")
+	ErrFreqNotAllowed = errors.New("frequency not allowed by policy")
 )
 
 // PI code validation errors (still used by pifmrds.go).
@@ -22,6 +46,11 @@ var (
 	ErrPIInvalidHex = errors.New("PI code must be valid hex")
 )
 
+// Audio conversion errors.
+var (
+	ErrNoResampler = errors.New("no audio resampler found")
+)
+
 // PS validation errors (still used by pifmrds.go).
 var (
 	ErrPSTooLong = errors.New("PS text must be 8 characters or less")
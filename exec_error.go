@@ -0,0 +1,111 @@
+package gorpitx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"syscall"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+)
+
+// exitCodePattern matches the exit status commander puts in the message of
+// the error returned for a non-zero exit, e.g. "(exit 1): ...".
+var exitCodePattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`\(exit (\d+)\)`,
+)
+
+// ExecError is returned by Exec, ExecOutput and Execution.Wait when the
+// module process doesn't finish successfully. It tells how the process
+// ended and keeps the last lines it wrote to stderr, the same ones
+// LastOutput returns. Timeouts are reported
+// as commonerrors.ErrTimeout instead, and cancelled contexts as the error
+// of the context.
+type ExecError struct {
+	// Module is the name of the module that was executing.
+	Module ModuleName
+	// ExitCode is the exit code of the process, or -1 when it was ended by
+	// a signal or the exit status is unknown.
+	ExitCode int
+	// Signal is the signal that ended the process, or 0 when it exited on
+	// its own.
+	Signal syscall.Signal
+	// Stderr holds the last lines the process wrote to stderr, up to the
+	// size set with WithLastOutputSize.
+	Stderr []string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ExecError) Error() string {
+	switch {
+	case e.Signal != 0:
+		return fmt.Sprintf(
+			"module %s ended by signal %d (%s): %v",
+			e.Module, int(e.Signal), e.Signal, e.Err,
+		)
+	case e.ExitCode >= 0:
+		return fmt.Sprintf(
+			"module %s exited with code %d: %v", e.Module, e.ExitCode, e.Err,
+		)
+	default:
+		return fmt.Sprintf("module %s failed: %v", e.Module, e.Err)
+	}
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// newExecError wraps the error the module process ended with in an
+// ExecError. nil, commonerrors.ErrTimeout and context errors are returned
+// as they are.
+func newExecError(name ModuleName, err error, stderr []string) error {
+	if err == nil || errors.Is(err, commonerrors.ErrTimeout) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	execErr := &ExecError{
+		Module:   name,
+		ExitCode: -1,
+		Stderr:   slices.Clone(stderr),
+		Err:      err,
+	}
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case errors.As(err, &exitErr):
+		status, ok := exitErr.Sys().(syscall.WaitStatus)
+		if ok && status.Signaled() {
+			execErr.Signal = status.Signal()
+
+			break
+		}
+
+		execErr.ExitCode = exitErr.ExitCode()
+	case errors.Is(err, commonerrors.ErrTerminated):
+		execErr.Signal = syscall.SIGTERM
+	case errors.Is(err, commonerrors.ErrKilled):
+		execErr.Signal = syscall.SIGKILL
+	default:
+		// commander doesn't keep the *exec.ExitError for non-zero exits,
+		// only the exit status in the message
+		match := exitCodePattern.FindStringSubmatch(err.Error())
+		if match == nil {
+			break
+		}
+
+		if code, convErr := strconv.Atoi(match[1]); convErr == nil {
+			execErr.ExitCode = code
+		}
+	}
+
+	return execErr
+}
@@ -0,0 +1,162 @@
+package gorpitx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runShell(t *testing.T, script string) error {
+	t.Helper()
+
+	err := exec.Command("sh", "-c", script).Run()
+	require.Error(t, err)
+
+	return err
+}
+
+func TestNewExecError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name         string
+		err          error
+		expectNil    bool
+		expectPlain  bool
+		expectCode   int
+		expectSignal syscall.Signal
+		expectMsg    string
+	}{
+		{
+			name:      "nil",
+			err:       nil,
+			expectNil: true,
+		},
+		{
+			name:        "timeout",
+			err:         commonerrors.ErrTimeout,
+			expectPlain: true,
+		},
+		{
+			name:         "terminated",
+			err:          ctxerrors.Wrap(commonerrors.ErrTerminated, "wait"),
+			expectCode:   -1,
+			expectSignal: syscall.SIGTERM,
+			expectMsg:    "module tune ended by signal 15 (terminated)",
+		},
+		{
+			name:         "killed",
+			err:          commonerrors.ErrKilled,
+			expectCode:   -1,
+			expectSignal: syscall.SIGKILL,
+			expectMsg:    "module tune ended by signal 9 (killed)",
+		},
+		{
+			name:       "exit error",
+			err:        runShell(t, "exit 3"),
+			expectCode: 3,
+			expectMsg:  "module tune exited with code 3",
+		},
+		{
+			name:         "signal exit error",
+			err:          runShell(t, "kill -INT $$"),
+			expectCode:   -1,
+			expectSignal: syscall.SIGINT,
+			expectMsg:    "module tune ended by signal 2 (interrupt)",
+		},
+		{
+			name: "commander exit message",
+			err: ctxerrors.Wrap(
+				commonerrors.ErrFailed, "(exit 2): oops",
+			),
+			expectCode: 2,
+			expectMsg:  "module tune exited with code 2",
+		},
+		{
+			name:       "unknown",
+			err:        errBoom,
+			expectCode: -1,
+			expectMsg:  "module tune failed: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newExecError(ModuleNameTUNE, tt.err, []string{"oops"})
+
+			switch {
+			case tt.expectNil:
+				assert.NoError(t, err)
+
+				return
+			case tt.expectPlain:
+				assert.Equal(t, tt.err, err)
+
+				return
+			}
+
+			var execErr *ExecError
+			require.ErrorAs(t, err, &execErr)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, ModuleNameTUNE, execErr.Module)
+			assert.Equal(t, tt.expectCode, execErr.ExitCode)
+			assert.Equal(t, tt.expectSignal, execErr.Signal)
+			assert.Equal(t, []string{"oops"}, execErr.Stderr)
+			assert.Contains(t, err.Error(), tt.expectMsg)
+		})
+	}
+}
+
+func TestRPITX_Exec_ExecError(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	dir := t.TempDir()
+	// Sleep around the stderr output so it isn't written before the
+	// capture is set up or lost to the process exiting right away
+	script := "#!/bin/sh\nsleep 0.2\necho 'no DMA channel' >&2\n" +
+		"sleep 0.2\nexit 3\n"
+	//nolint:gosec // the fake binary must be executable
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "failing"), []byte(script), 0o755,
+	))
+
+	rpitx := &RPITX{
+		config:    Config{Path: dir},
+		commander: commander.New(),
+		modules: map[ModuleName]Module{
+			"failing": &TUNE{},
+		},
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+
+	err := rpitx.Exec(context.Background(), "failing", args, 5*time.Second)
+
+	var execErr *ExecError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ModuleName("failing"), execErr.Module)
+	assert.Equal(t, 3, execErr.ExitCode)
+	assert.Equal(t, syscall.Signal(0), execErr.Signal)
+	assert.Equal(t, []string{"no DMA channel"}, execErr.Stderr)
+	assert.ErrorIs(t, err, commonerrors.ErrFailed)
+
+	_, stderr, err := rpitx.ExecOutput(
+		context.Background(), "failing", args, 5*time.Second,
+	)
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, 3, execErr.ExitCode)
+	assert.Equal(t, []string{"no DMA channel"}, execErr.Stderr)
+	assert.Equal(t, "no DMA channel\n", string(stderr))
+}
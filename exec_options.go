@@ -0,0 +1,120 @@
+package gorpitx
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// ExecOption configures a single execution started with Exec, ExecAsync,
+// ExecDefault or ExecOutput.
+type ExecOption func(*execOptions)
+
+type execOptions struct {
+	env     []string
+	dir     string
+	reader  io.Reader
+	capture *outputCapture
+}
+
+// WithExecEnv adds environment variables in KEY=value form to the module
+// process, on top of the environment of the current process, e.g.
+// LD_LIBRARY_PATH for a locally built rpitx. Later values win over earlier
+// ones with the same key.
+func WithExecEnv(vars ...string) ExecOption {
+	return func(o *execOptions) {
+		o.env = append(o.env, vars...)
+	}
+}
+
+// WithExecDir runs the module process in dir, which must be an existing
+// directory.
+func WithExecDir(dir string) ExecOption {
+	return func(o *execOptions) {
+		o.dir = dir
+	}
+}
+
+// WithExecReader streams the reader to a module taking reader input, e.g. FSK
+// with InputTypeReader transmitting a live telemetry feed as it is read. The
+// reader belongs to this execution only, auto restarts carrying on where it
+// was left. Modules that take no reader input reject it with
+// commonerrors.ErrInvalidValue.
+func WithExecReader(reader io.Reader) ExecOption {
+	return func(o *execOptions) {
+		o.reader = reader
+	}
+}
+
+// newExecOptions applies the options and validates the result.
+func newExecOptions(opts []ExecOption) (execOptions, error) {
+	var options execOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for _, v := range options.env {
+		if key, _, ok := strings.Cut(v, "="); !ok || key == "" {
+			return execOptions{}, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"environment variable must be KEY=value, got: %q",
+				v,
+			)
+		}
+	}
+
+	if options.dir == "" {
+		return options, nil
+	}
+
+	info, err := os.Stat(options.dir)
+	if os.IsNotExist(err) {
+		return execOptions{}, ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"working directory does not exist: %s",
+			options.dir,
+		)
+	}
+
+	if err != nil {
+		return execOptions{}, ctxerrors.Wrapf(
+			err, "failed to stat working directory %s", options.dir,
+		)
+	}
+
+	if !info.IsDir() {
+		return execOptions{}, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"working directory is not a directory: %s",
+			options.dir,
+		)
+	}
+
+	return options, nil
+}
+
+// commanderOptions returns the commander options for the environment and
+// working directory. moduleEnv holds the variables the module itself needs,
+// which replace the whole environment when no extra ones were given.
+func (o execOptions) commanderOptions(moduleEnv []string) []commander.Option {
+	var opts []commander.Option
+
+	switch {
+	case len(o.env) > 0:
+		env := append(os.Environ(), moduleEnv...)
+		opts = append(opts, commander.WithEnv(append(env, o.env...)))
+	case len(moduleEnv) > 0:
+		opts = append(opts, commander.WithEnv(moduleEnv))
+	}
+
+	if o.dir != "" {
+		opts = append(opts, commander.WithDir(o.dir))
+	}
+
+	return opts
+}
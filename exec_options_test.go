@@ -0,0 +1,226 @@
+package gorpitx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExecOptions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	tests := []struct {
+		name        string
+		opts        []ExecOption
+		expected    execOptions
+		expectedErr error
+	}{
+		{
+			name: "no options",
+		},
+		{
+			name: "env and dir",
+			opts: []ExecOption{
+				WithExecEnv("LD_LIBRARY_PATH=/opt/rpitx/lib"),
+				WithExecEnv("A=1", "B="),
+				WithExecDir(dir),
+			},
+			expected: execOptions{
+				env: []string{"LD_LIBRARY_PATH=/opt/rpitx/lib", "A=1", "B="},
+				dir: dir,
+			},
+		},
+		{
+			name:        "env without value",
+			opts:        []ExecOption{WithExecEnv("LD_LIBRARY_PATH")},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "env without key",
+			opts:        []ExecOption{WithExecEnv("=1")},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "missing dir",
+			opts:        []ExecOption{WithExecDir(filepath.Join(dir, "nope"))},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+		{
+			name:        "dir is a file",
+			opts:        []ExecOption{WithExecDir(file)},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := newExecOptions(tt.opts)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, options)
+		})
+	}
+}
+
+func TestRPITX_CommandOptions_ExecOptions(t *testing.T) {
+	t.Setenv("GORPITX_TEST_INHERITED", "yes")
+
+	rpitx := &RPITX{
+		config:  Config{Path: "/opt/rpitx"},
+		modules: defaultModules(),
+	}
+
+	tests := []struct {
+		name        string
+		module      ModuleName
+		options     execOptions
+		expectedEnv []string
+		inherits    bool
+		expectedDir string
+	}{
+		{
+			name:   "binary module without options",
+			module: ModuleNameTUNE,
+		},
+		{
+			name:        "script module without options",
+			module:      ModuleNamePIDTMF,
+			expectedEnv: []string{"RPITX_PATH=/opt/rpitx"},
+		},
+		{
+			name:   "binary module with env and dir",
+			module: ModuleNameTUNE,
+			options: execOptions{
+				env: []string{"LD_LIBRARY_PATH=/opt/rpitx/lib"},
+				dir: "/tmp",
+			},
+			expectedEnv: []string{"LD_LIBRARY_PATH=/opt/rpitx/lib"},
+			inherits:    true,
+			expectedDir: "/tmp",
+		},
+		{
+			name:    "script module with env",
+			module:  ModuleNamePIDTMF,
+			options: execOptions{env: []string{"A=1"}},
+			expectedEnv: []string{
+				"RPITX_PATH=/opt/rpitx", "A=1",
+			},
+			inherits: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := &commander.Options{}
+			for _, opt := range rpitx.commandOptions(
+				tt.module, nil, tt.options,
+			) {
+				opt(options)
+			}
+
+			assert.Equal(t, tt.expectedDir, options.Dir)
+
+			if !tt.inherits {
+				assert.Equal(t, tt.expectedEnv, options.Env)
+
+				return
+			}
+
+			assert.Contains(t, options.Env, "GORPITX_TEST_INHERITED=yes")
+			assert.Equal(
+				t, tt.expectedEnv,
+				options.Env[len(options.Env)-len(tt.expectedEnv):],
+			)
+		})
+	}
+}
+
+func TestRPITX_Exec_ExecOptions(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "tune")
+	require.NoError(t, os.WriteFile(
+		script, []byte("#!/bin/sh\necho \"$PWD $GORPITX_TEST_VAR\"\n"), 0o700,
+	))
+
+	rpitx := &RPITX{
+		config: Config{Path: dir},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: commander.New(),
+	}
+
+	stdout, _, err := rpitx.ExecOutput(
+		context.Background(),
+		ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000, "exitImmediate": true}`),
+		5*time.Second,
+		WithExecEnv("GORPITX_TEST_VAR=set"),
+		WithExecDir(dir),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, dir+" set\n", string(stdout))
+
+	err = rpitx.Exec(
+		context.Background(),
+		ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`),
+		time.Second,
+		WithExecDir(filepath.Join(dir, "missing")),
+	)
+	require.ErrorIs(t, err, commonerrors.ErrFileNotFound)
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_Exec_WithExecReader(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+			ModuleNameFSK:  &FSK{},
+		},
+		commander: commander.New(),
+	}
+
+	args := []byte(`{"inputType": "reader", "frequency": 144500000}`)
+
+	// Each execution streams its own reader
+	for _, feed := range []string{"T=21.5C", "T=22.0C"} {
+		stdout, _, err := rpitx.ExecOutput(
+			context.Background(), ModuleNameFSK, args, 500*time.Millisecond,
+			WithExecReader(strings.NewReader(feed)),
+		)
+		require.ErrorIs(t, err, commonerrors.ErrTimeout)
+		assert.Contains(t, string(stdout), "mocking stdin: "+feed)
+	}
+
+	err := rpitx.Exec(context.Background(), ModuleNameFSK, args, time.Second)
+	require.ErrorIs(t, err, commonerrors.ErrRequiredFieldNotSet)
+
+	err = rpitx.Exec(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), time.Second,
+		WithExecReader(strings.NewReader("T=21.5C")),
+	)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.False(t, rpitx.isExecuting.Load())
+}
@@ -0,0 +1,102 @@
+package gorpitx
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// ExecOutput runs the module to completion and returns everything it
+// printed on stdout and stderr, one line per output line. It is meant for
+// one-shot runs and runs like Exec: the process is the current one for
+// Stop, Kill, Pause and Status while it runs, a timeout greater than zero
+// stops its whole process group once it elapses and returns
+// commonerrors.ErrTimeout together with the output captured so far, while
+// NoTimeout waits for the module to exit. A process that fails is reported
+// as an *ExecError.
+func (r *RPITX) ExecOutput(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	timeout time.Duration,
+	opts ...ExecOption,
+) ([]byte, []byte, error) {
+	capture := &outputCapture{}
+
+	opts = append(opts, withOutputCapture(capture))
+
+	execution, err := r.ExecAsync(ctx, name, args, timeout, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = execution.Wait()
+	stdout, stderr := capture.output()
+
+	return stdout, stderr, err
+}
+
+// withOutputCapture has every process of the execution, restarts
+// included, send its output to the capture from the first line.
+func withOutputCapture(capture *outputCapture) ExecOption {
+	return func(o *execOptions) {
+		o.capture = capture
+	}
+}
+
+// outputCapture collects the output of the processes of an execution for
+// ExecOutput.
+type outputCapture struct {
+	mu     sync.Mutex
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	wg     sync.WaitGroup
+}
+
+// stream returns a new stream collecting into the capture, for one
+// process. Its channels are closed once the output of the process ends.
+func (c *outputCapture) stream() hubStream {
+	stdout := make(chan string, streamInternalBuffer)
+	stderr := make(chan string, streamInternalBuffer)
+
+	c.wg.Add(2)
+
+	go c.collect(stdout, &c.stdout)
+	go c.collect(stderr, &c.stderr)
+
+	return hubStream{stdout: stdout, stderr: stderr}
+}
+
+// collect appends the lines to the buffer until the channel is closed.
+func (c *outputCapture) collect(lines <-chan string, buf *bytes.Buffer) {
+	defer c.wg.Done()
+
+	for line := range lines {
+		c.mu.Lock()
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		c.mu.Unlock()
+	}
+}
+
+// output returns the captured stdout and stderr once the output has ended,
+// or whatever was captured so far if it doesn't end shortly.
+func (c *outputCapture) output() ([]byte, []byte) {
+	done := make(chan struct{})
+
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(stderrTailGrace):
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return bytes.Clone(c.stdout.Bytes()), bytes.Clone(c.stderr.Bytes())
+}
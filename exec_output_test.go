@@ -0,0 +1,191 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_ExecOutput_Production(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		config: Config{Path: "/opt/rpitx"},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: mockCommander,
+	}
+
+	mockCommander.Expect(
+		"stdbuf", "-oL", "/opt/rpitx/tune", "-f", "434000000", "-e",
+	).ReturnOutput([]byte("tuned\n"))
+
+	args, err := json.Marshal(map[string]any{
+		"frequency":     434000000.0,
+		"exitImmediate": true,
+	})
+	require.NoError(t, err)
+
+	stdout, stderr, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameTUNE, args, time.Second,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "tuned\n", string(stdout))
+	assert.Empty(t, stderr)
+	assert.False(t, rpitx.isExecuting.Load())
+	assert.NoError(t, mockCommander.VerifyExpectations())
+}
+
+func TestRPITX_ExecOutput_Errors(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: mockCommander,
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+
+	// Unknown module
+	_, _, err := rpitx.ExecOutput(
+		context.Background(), "nonexistent", args, 0,
+	)
+	require.ErrorIs(t, err, ErrUnknownModule)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	// Process failure
+	mockCommander.ExpectWithMatchers(
+		"stdbuf", commander.Any(), commander.Any(), commander.Any(),
+		commander.Any(),
+	).ReturnError(commonerrors.ErrFailed)
+
+	_, _, err = rpitx.ExecOutput(context.Background(), ModuleNameTUNE, args, 0)
+	require.ErrorIs(t, err, commonerrors.ErrFailed)
+	assert.Contains(t, err.Error(), "failed to start process")
+
+	// Busy
+	rpitx.isExecuting.Store(true)
+
+	_, _, err = rpitx.ExecOutput(context.Background(), ModuleNameTUNE, args, 0)
+	require.ErrorIs(t, err, ErrExecuting)
+}
+
+func TestRPITX_ExecOutput_DevTimeout(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: commander.New(),
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+	timeout := 300 * time.Millisecond
+
+	// The sleep of the mock holds the output pipes, it must be killed with
+	// the shell instead of being waited for
+	start := time.Now()
+	stdout, _, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameTUNE, args, timeout,
+	)
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, timeout+400*time.Millisecond)
+	assert.Contains(t, string(stdout), "mocking execution of tune")
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecOutput_DevCancel(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: commander.New(),
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 300*time.Millisecond,
+	)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := rpitx.ExecOutput(ctx, ModuleNameTUNE, args, NoTimeout)
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, elapsed, 700*time.Millisecond)
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecOutput_Stop(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: commander.New(),
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+
+	type result struct {
+		stdout []byte
+		err    error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		stdout, _, err := rpitx.ExecOutput(
+			context.Background(), ModuleNameTUNE, args, NoTimeout,
+		)
+		done <- result{stdout: stdout, err: err}
+	}()
+
+	require.Eventually(t, func() bool {
+		return rpitx.Status().Executing
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, ModuleNameTUNE, rpitx.Status().Module)
+
+	// Give the mock time to print its first line
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, rpitx.Stop(context.Background()))
+
+	select {
+	case res := <-done:
+		var execErr *ExecError
+		require.ErrorAs(t, res.err, &execErr)
+		assert.Equal(t, syscall.SIGTERM, execErr.Signal)
+		assert.Contains(t, string(res.stdout), "mocking execution of tune")
+	case <-time.After(2 * time.Second):
+		t.Fatal("ExecOutput didn't return after Stop")
+	}
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, rpitx.Status().Executing)
+	require.ErrorIs(t, rpitx.Stop(context.Background()), ErrNotExecuting)
+}
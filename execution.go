@@ -0,0 +1,367 @@
+package gorpitx
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Execution is a handle to a module execution started with ExecAsync.
+type Execution struct {
+	rpitx        *RPITX
+	module       ModuleName
+	args         []byte
+	options      execOptions
+	sequenceStep bool
+	process      commander.Process
+	stderr       *stderrTail
+	estimate     time.Duration
+	processMu    sync.RWMutex
+	hooks        *hookDispatcher
+	record       TransmissionRecord
+	done         chan struct{}
+	err          error
+}
+
+// ExecAsync starts the module and returns a handle to the running execution
+// without waiting for it to finish. Only one execution can run at a time,
+// ErrExecuting wrapped with the running module is returned otherwise. A
+// timeout greater than zero stops the process once it elapses and makes
+// Wait return commonerrors.ErrTimeout, while NoTimeout runs it until it
+// exits or is stopped. ExecOptions are validated before anything starts,
+// a missing working directory returning commonerrors.ErrFileNotFound.
+func (r *RPITX) ExecAsync(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	timeout time.Duration,
+	opts ...ExecOption,
+) (*Execution, error) {
+	if err := checkTimeout(timeout); err != nil {
+		r.metrics().ExecutionFailed(name, err)
+
+		return nil, err
+	}
+
+	options, err := newExecOptions(opts)
+	if err != nil {
+		r.metrics().ExecutionFailed(name, err)
+
+		return nil, err
+	}
+
+	if !r.isExecuting.CompareAndSwap(false, true) {
+		err := r.busyError()
+		r.metrics().ExecutionFailed(name, err)
+
+		return nil, err
+	}
+
+	if err := r.checkCooldown(); err != nil {
+		r.isExecuting.Store(false)
+		r.metrics().ExecutionFailed(name, err)
+
+		return nil, err
+	}
+
+	r.processMu.Lock()
+	r.stopCh = make(chan struct{})
+	r.processMu.Unlock()
+
+	return r.startExecution(ctx, name, args, timeout, options, false)
+}
+
+// startExecution launches the module in the execution slot already taken
+// by the caller. A sequence step keeps the slot once it finishes, for the
+// next step.
+func (r *RPITX) startExecution(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	timeout time.Duration,
+	options execOptions,
+	sequenceStep bool,
+) (*Execution, error) {
+	r.log().Debugf("executing module %s with args %s", name, args)
+
+	execution := &Execution{
+		rpitx:        r,
+		module:       name,
+		args:         args,
+		options:      options,
+		sequenceStep: sequenceStep,
+		hooks:        r.newHookDispatcher(),
+		done:         make(chan struct{}),
+	}
+
+	r.processMu.Lock()
+	r.execution = execution
+	r.processMu.Unlock()
+
+	process, module, err := r.launch(ctx, name, args, options)
+	if err != nil {
+		r.endExecution(ctx, execution)
+		execution.hooks.failed(err)
+		r.metrics().ExecutionFailed(name, err)
+
+		execution.err = err
+		close(execution.done)
+
+		return nil, err
+	}
+
+	execution.setProcess(process, r.captureStderr(process))
+	execution.record = r.startTransmissionRecord(name, module)
+	execution.estimate, _ = moduleDuration(module)
+	execution.hooks.started(name)
+	r.metrics().ExecutionStarted(name)
+
+	go r.superviseExecution(ctx, execution, timeout)
+
+	return execution, nil
+}
+
+// WaitForExit blocks until the current execution finishes, or the latest
+// one when nothing is running, and returns its result like Execution.Wait.
+// It returns ErrNotExecuting if nothing was ever executed and the context
+// error if ctx is done first.
+func (r *RPITX) WaitForExit(ctx context.Context) error {
+	r.processMu.RLock()
+	execution := r.execution
+	r.processMu.RUnlock()
+
+	if execution == nil {
+		return ErrNotExecuting
+	}
+
+	select {
+	case <-execution.done:
+		return execution.err
+	case <-ctx.Done():
+		return ctxerrors.Wrap(ctx.Err(), "cancelled while waiting for exit")
+	}
+}
+
+// launch parses the args into a fresh instance of the module, prepares its
+// command and starts its process. The instance is returned with the
+// process, holding the args of this launch.
+//
+//nolint:ireturn // commander.Process is an interface by design
+func (r *RPITX) launch(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	options execOptions,
+) (commander.Process, Module, error) {
+	module, err := r.newModule(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := setExecReader(name, module, options.reader); err != nil {
+		return nil, nil, err
+	}
+
+	cmdName, cmdArgs, stdin, err := r.prepareModuleCommand(name, module, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if input, ok := module.(readerInput); ok && input.readsReader() &&
+		options.reader == nil {
+		return nil, nil, ctxerrors.Wrap(
+			commonerrors.ErrRequiredFieldNotSet,
+			"reader, pass it with WithExecReader",
+		)
+	}
+
+	cmdArgs, cleanup, err := r.prepareInput(ctx, module, cmdArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Removed once the execution is over, by cleanupExecution
+	r.setInputCleanup(cleanup)
+
+	if err := r.waitForStart(ctx, name, module); err != nil {
+		return nil, nil, err
+	}
+
+	process, err := r.startProcess(
+		ctx, name, cmdName, cmdArgs, stdin, options,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return process, module, nil
+}
+
+// setExecReader gives the reader of the execution to the module instance,
+// which must take reader input when one is given.
+func setExecReader(name ModuleName, module Module, reader io.Reader) error {
+	input, ok := module.(readerInput)
+	if !ok {
+		if reader != nil {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"module %s takes no reader input",
+				name,
+			)
+		}
+
+		return nil
+	}
+
+	input.setReader(reader)
+
+	return nil
+}
+
+// setInputCleanup records the cleanup of the converted input of the
+// execution, running the one of a previous launch when restarting.
+func (r *RPITX) setInputCleanup(cleanup func()) {
+	r.processMu.Lock()
+	previous := r.inputCleanup
+	r.inputCleanup = cleanup
+	r.processMu.Unlock()
+
+	if previous != nil {
+		previous()
+	}
+}
+
+// endExecution cleans up after the execution, keeping the execution slot
+// when it is a sequence step.
+func (r *RPITX) endExecution(ctx context.Context, execution *Execution) {
+	if execution.sequenceStep {
+		r.releaseProcess(ctx)
+
+		return
+	}
+
+	r.cleanupExecution(ctx)
+}
+
+// superviseExecution waits for the execution to finish, releases the
+// executing state and then marks the execution as done.
+func (r *RPITX) superviseExecution(
+	ctx context.Context,
+	execution *Execution,
+	timeout time.Duration,
+) {
+	defer close(execution.done)
+	defer func() { execution.hooks.stopped(execution.err) }()
+	defer r.endExecution(ctx, execution)
+	defer r.log().Debugf("finished executing module %s", execution.module)
+
+	start := time.Now()
+	timeout, capped := r.transmitTimeout(timeout)
+	stopProgress := execution.hooks.trackProgress(execution.estimate)
+
+	err := r.runExecution(ctx, execution, timeout)
+
+	// No tick may come after OnStop
+	stopProgress()
+
+	execution.err = r.transmitLimitError(execution.module, err, capped)
+
+	r.metrics().ExecutionFinished(
+		execution.module, time.Since(start), execution.err,
+	)
+	r.logTransmission(execution.record, execution.err, r.stopRequested())
+}
+
+// Module returns the name of the executing module.
+func (e *Execution) Module() ModuleName {
+	return e.module
+}
+
+// Done returns a channel that is closed once the execution has finished and
+// the RPITX instance is ready for the next execution.
+func (e *Execution) Done() <-chan struct{} {
+	return e.done
+}
+
+// Wait blocks until the execution finishes and returns its result. A
+// process that doesn't finish successfully is reported as an *ExecError.
+func (e *Execution) Wait() error {
+	<-e.done
+
+	return e.err
+}
+
+// Stop gracefully stops the execution, returning nil when it ends by the
+// stop signal. Returns ErrNotExecuting if the execution has already
+// finished.
+func (e *Execution) Stop(ctx context.Context) error {
+	select {
+	case <-e.done:
+		return ErrNotExecuting
+	default:
+	}
+
+	e.rpitx.requestStop()
+	e.rpitx.metrics().StopRequested(e.module)
+
+	process, _ := e.current()
+
+	err := e.rpitx.stopProcess(ctx, process)
+	if err != nil && !isStopSignal(err) {
+		return ctxerrors.Wrap(err, "failed to stop process")
+	}
+
+	return nil
+}
+
+// Stream sends the process output lines to the given channels from now on.
+// The channels are closed when the output ends. Pass nil for channels you
+// don't want to listen to.
+func (e *Execution) Stream(stdout, stderr chan<- string) {
+	select {
+	case <-e.done:
+		// Nothing more will be produced, let the caller stop ranging
+		if stdout != nil {
+			close(stdout)
+		}
+
+		if stderr != nil {
+			close(stderr)
+		}
+
+		return
+	default:
+	}
+
+	process, _ := e.current()
+
+	e.rpitx.forwardStreamAsync(context.Background(), process, stdout, stderr)
+}
+
+// current returns the running process of the execution, which changes when
+// it is restarted, and its stderr capture.
+//
+//nolint:ireturn // commander.Process is an interface by design
+func (e *Execution) current() (commander.Process, *stderrTail) {
+	e.processMu.RLock()
+	defer e.processMu.RUnlock()
+
+	return e.process, e.stderr
+}
+
+// setProcess replaces the process of a restarted execution.
+func (e *Execution) setProcess(
+	process commander.Process,
+	stderr *stderrTail,
+) {
+	e.processMu.Lock()
+	defer e.processMu.Unlock()
+
+	e.process = process
+	e.stderr = stderr
+}
@@ -0,0 +1,280 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExecutionTestRPITX(t *testing.T) (*RPITX, []byte) {
+	t.Helper()
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	// Echo often so streams don't wait on the mock
+	rpitx := &RPITX{
+		config:    Config{Mock: MockConfig{Interval: 50 * time.Millisecond}},
+		commander: commander.New(),
+		modules: map[ModuleName]Module{
+			ModuleNameMORSE: &MORSE{},
+		},
+	}
+
+	args, err := json.Marshal(map[string]any{
+		"frequency": 434000000.0,
+		"rate":      20,
+		"message":   "TEST ASYNC",
+	})
+	require.NoError(t, err)
+
+	return rpitx, args
+}
+
+func TestRPITX_ExecAsync_StreamAndStop(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ModuleNameMORSE, execution.Module())
+	assert.True(t, rpitx.isExecuting.Load())
+
+	stdout := make(chan string, 10)
+	execution.Stream(stdout, nil)
+
+	select {
+	case line := <-stdout:
+		assert.Contains(t, line, "mocking execution of morse")
+	case <-time.After(3 * time.Second):
+		t.Fatal("no output received from execution")
+	}
+
+	// Stop succeeds while Wait reports how the process ended, which is a
+	// termination here
+	require.NoError(t, execution.Stop(ctx))
+
+	select {
+	case <-execution.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution did not finish after stop")
+	}
+
+	assert.ErrorIs(t, execution.Wait(), commonerrors.ErrTerminated)
+	assert.False(t, rpitx.isExecuting.Load())
+	assert.ErrorIs(t, execution.Stop(ctx), ErrNotExecuting)
+
+	// Streaming a finished execution closes the channels right away
+	lateStdout := make(chan string)
+	lateStderr := make(chan string)
+	execution.Stream(lateStdout, lateStderr)
+
+	_, ok := <-lateStdout
+	assert.False(t, ok)
+
+	_, ok = <-lateStderr
+	assert.False(t, ok)
+}
+
+func TestRPITX_ExecAsync_Timeout(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), ModuleNameMORSE, args, 200*time.Millisecond,
+	)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, execution.Wait(), commonerrors.ErrTimeout)
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecAsync_AlreadyExecuting(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	second, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.ErrorIs(t, err, ErrExecuting)
+	assert.Contains(t, err.Error(), ModuleNameMORSE+" running since ")
+	assert.Nil(t, second)
+
+	_ = execution.Stop(ctx)
+	_ = execution.Wait()
+
+	// The instance can be reused once the execution is done
+	third, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+	_ = third.Stop(ctx)
+	_ = third.Wait()
+}
+
+func TestRPITX_ExecAsync_PrepareError(t *testing.T) {
+	rpitx, _ := newExecutionTestRPITX(t)
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), "nonexistent", []byte(`{}`), 0,
+	)
+	require.ErrorIs(t, err, ErrUnknownModule)
+	assert.Nil(t, execution)
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_WaitForExit(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	require.ErrorIs(t, rpitx.WaitForExit(ctx), ErrNotExecuting)
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	// The context ends the wait, not the execution
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, rpitx.WaitForExit(waitCtx), context.DeadlineExceeded)
+	assert.True(t, rpitx.isExecuting.Load())
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+
+		_ = rpitx.Stop(ctx)
+	}()
+
+	err = rpitx.WaitForExit(ctx)
+	require.ErrorIs(t, err, commonerrors.ErrTerminated)
+	assert.Equal(t, execution.Wait(), err)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	// The latest execution keeps its result
+	require.ErrorIs(t, rpitx.WaitForExit(ctx), commonerrors.ErrTerminated)
+
+	// Executions failing to start are reported too
+	_, err = rpitx.ExecAsync(
+		ctx, ModuleNameMORSE, []byte(`{"frequency": 1}`), 0,
+	)
+	require.Error(t, err)
+	assert.Equal(t, err, rpitx.WaitForExit(ctx))
+}
+
+func TestRPITX_WaitForExit_Timeout(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	go func() {
+		_ = rpitx.Exec(ctx, ModuleNameMORSE, args, 200*time.Millisecond)
+	}()
+
+	require.Eventually(t, rpitx.isExecuting.Load,
+		2*time.Second, 10*time.Millisecond)
+
+	require.ErrorIs(t, rpitx.WaitForExit(ctx), commonerrors.ErrTimeout)
+}
+
+func TestRPITX_Exec_ContextCancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+	}{
+		{name: "without timeout"},
+		{name: "with longer timeout", timeout: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx, args := newExecutionTestRPITX(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			time.AfterFunc(200*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := rpitx.Exec(ctx, ModuleNameMORSE, args, tt.timeout)
+
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), 2*time.Second)
+			assert.False(t, rpitx.isExecuting.Load())
+		})
+	}
+}
+
+func TestRPITX_Exec_ContextDeadlineBeforeTimeout(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 200*time.Millisecond,
+	)
+	defer cancel()
+
+	err := rpitx.Exec(ctx, ModuleNameMORSE, args, time.Minute)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, commonerrors.ErrTimeout)
+}
+
+func TestRPITX_WaitProcess_CancelKillsGroup(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "ticks")
+
+	// The child keeps writing like the sendiq of a script module would
+	// keep transmitting after its shell is gone
+	process, err := commander.New().Start(
+		context.Background(),
+		"sh",
+		[]string{
+			"-c",
+			`(while true; do echo >> "$0"; sleep 0.02; done) & wait`,
+			out,
+		},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = process.Kill(context.Background()) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	err = (&RPITX{}).waitProcess(ctx, process, 0)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// Let a write in flight land before checking nothing is written anymore
+	time.Sleep(100 * time.Millisecond)
+
+	before, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+
+	after, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Len(t, after, len(before))
+}
+
+func TestRPITX_ExecAsync_EstimateFromOwnArgs(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	expected, ok := rpitx.EstimateTransmitDuration(ModuleNameMORSE, args)
+	require.True(t, ok)
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	// Validating other args meanwhile doesn't change the running execution
+	require.NoError(t, rpitx.ValidateArgs(ModuleNameMORSE, []byte(
+		`{"frequency": 434000000, "rate": 5, "message": "MUCH LONGER"}`,
+	)))
+
+	assert.Equal(t, expected, execution.estimate)
+
+	require.NoError(t, execution.Stop(ctx))
+	<-execution.Done()
+}
@@ -0,0 +1,258 @@
+package gorpitx
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// feedChunkDuration is how much audio FeedSocket writes at a time.
+	feedChunkDuration = 20 * time.Millisecond
+
+	// pcmBytesPerSample is the size of a mono S16LE sample.
+	pcmBytesPerSample = 2
+
+	wavHeaderSize      = 12
+	wavChunkHeaderSize = 8
+	wavFmtMinSize      = 16
+	wavFormatPCM       = 1
+	wavBitsPerSample   = 16
+)
+
+// FeedSocket serves audio to the AudioSockBroadcast module on a Unix socket
+// at socketPath. It waits for the module to connect and then writes the
+// audio in real time at sampleRate until EOF or until ctx is done.
+//
+// The audio is raw mono S16LE PCM, or a WAV file in that format whose header
+// is skipped. FeedSocket must run concurrently with the module and be
+// started first, since the module expects the socket to exist:
+//
+//	go func() { errCh <- gorpitx.FeedSocket(ctx, socket, file, 48000) }()
+//	err := rpitx.Exec(ctx, gorpitx.ModuleNameAudioSockBroadcast, args, 0)
+func FeedSocket(
+	ctx context.Context,
+	socketPath string,
+	audio io.Reader,
+	sampleRate int,
+) error {
+	if socketPath == "" {
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "socketPath")
+	}
+
+	if sampleRate <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"sample rate must be positive, got: %d",
+			sampleRate,
+		)
+	}
+
+	pcm, err := skipWAVHeader(bufio.NewReader(audio), sampleRate)
+	if err != nil {
+		return err
+	}
+
+	listener, err := listenUnixSocket(socketPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = listener.Close() }()
+
+	conn, err := acceptContext(ctx, listener)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	return pumpPCM(ctx, conn, pcm, sampleRate)
+}
+
+// listenUnixSocket listens on the Unix socket at path, replacing a stale
+// socket left behind by a previous run.
+func listenUnixSocket(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil &&
+		info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, ctxerrors.Wrapf(err, "failed to remove stale socket %s", path)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to listen on %s", path)
+	}
+
+	return listener, nil
+}
+
+// acceptContext waits for a connection on the listener until ctx is done.
+func acceptContext(
+	ctx context.Context,
+	listener net.Listener,
+) (net.Conn, error) {
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctxerrors.Wrap(ctx.Err(), "cancelled while waiting for module")
+		}
+
+		return nil, ctxerrors.Wrap(err, "failed to accept connection")
+	}
+
+	return conn, nil
+}
+
+// pumpPCM writes the PCM audio to conn in chunks, pacing them so the audio
+// is sent in real time.
+func pumpPCM(
+	ctx context.Context,
+	conn net.Conn,
+	pcm io.Reader,
+	sampleRate int,
+) error {
+	// Unblock a write stuck on a module that stopped reading
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	bytesPerSecond := int64(sampleRate * pcmBytesPerSample)
+	chunk := make([]byte, max(
+		bytesPerSecond*int64(feedChunkDuration)/int64(time.Second),
+		pcmBytesPerSample,
+	))
+
+	start := time.Now()
+
+	var sent int64
+
+	for {
+		n, readErr := io.ReadFull(pcm, chunk)
+		if n > 0 {
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				if ctx.Err() != nil {
+					return ctxerrors.Wrap(ctx.Err(), "cancelled while feeding audio")
+				}
+
+				return ctxerrors.Wrap(err, "failed to write audio")
+			}
+
+			sent += int64(n)
+		}
+
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return nil
+		}
+
+		if readErr != nil {
+			return ctxerrors.Wrap(readErr, "failed to read audio")
+		}
+
+		played := time.Duration(sent * int64(time.Second) / bytesPerSecond)
+
+		timer := time.NewTimer(time.Until(start.Add(played)))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctxerrors.Wrap(ctx.Err(), "cancelled while feeding audio")
+		}
+	}
+}
+
+// skipWAVHeader returns the PCM data of the audio. A WAV header is skipped
+// after checking the format is mono 16-bit PCM at sampleRate, anything else
+// is taken as raw PCM.
+func skipWAVHeader(audio *bufio.Reader, sampleRate int) (io.Reader, error) {
+	header, err := audio.Peek(wavHeaderSize)
+	if err != nil || string(header[0:4]) != "RIFF" ||
+		string(header[8:12]) != "WAVE" {
+		return audio, nil //nolint:nilerr // short audio is raw PCM
+	}
+
+	if _, err := audio.Discard(wavHeaderSize); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to read WAV header")
+	}
+
+	for {
+		chunkHeader := make([]byte, wavChunkHeaderSize)
+		if _, err := io.ReadFull(audio, chunkHeader); err != nil {
+			return nil, ctxerrors.Wrap(
+				commonerrors.ErrInvalidValue,
+				"WAV file has no data chunk",
+			)
+		}
+
+		id := string(chunkHeader[0:4])
+		size := int(binary.LittleEndian.Uint32(chunkHeader[4:8]))
+
+		switch id {
+		case "data":
+			return audio, nil
+		case "fmt ":
+			if err := checkWAVFormat(audio, size, sampleRate); err != nil {
+				return nil, err
+			}
+		default:
+			// Chunks are padded to an even size
+			if _, err := audio.Discard(size + size%2); err != nil {
+				return nil, ctxerrors.Wrapf(err, "failed to skip WAV chunk %q", id)
+			}
+		}
+	}
+}
+
+// checkWAVFormat reads the WAV fmt chunk and checks it describes mono
+// 16-bit PCM at sampleRate.
+func checkWAVFormat(audio *bufio.Reader, size int, sampleRate int) error {
+	if size < wavFmtMinSize {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"WAV fmt chunk too short: %d bytes",
+			size,
+		)
+	}
+
+	fmtChunk := make([]byte, size+size%2)
+	if _, err := io.ReadFull(audio, fmtChunk); err != nil {
+		return ctxerrors.Wrap(err, "failed to read WAV fmt chunk")
+	}
+
+	format := binary.LittleEndian.Uint16(fmtChunk[0:2])
+	channels := binary.LittleEndian.Uint16(fmtChunk[2:4])
+	rate := binary.LittleEndian.Uint32(fmtChunk[4:8])
+	bits := binary.LittleEndian.Uint16(fmtChunk[14:16])
+
+	if format != wavFormatPCM || channels != 1 || bits != wavBitsPerSample {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"WAV must be mono 16-bit PCM, got format %d with %d channels "+
+				"of %d bits",
+			format, channels, bits,
+		)
+	}
+
+	if int(rate) != sampleRate {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"WAV sample rate is %d Hz, expected %d Hz",
+			rate, sampleRate,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package gorpitx
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWAV returns a WAV file holding the PCM data in the given format.
+func newWAV(channels, bits uint16, sampleRate uint32, pcm []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36+len(pcm)))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+
+	for _, field := range []any{
+		uint32(16), uint16(1), channels, sampleRate,
+		sampleRate * uint32(channels*bits/8), channels * bits / 8, bits,
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, field)
+	}
+
+	// A chunk FeedSocket skips
+	buf.WriteString("LIST")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.WriteString("abc\x00")
+
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+
+	return buf.Bytes()
+}
+
+// connectToFeed connects to the socket served by FeedSocket once it exists.
+func connectToFeed(t *testing.T, socketPath string) net.Conn {
+	t.Helper()
+
+	var conn net.Conn
+
+	require.Eventually(t, func() bool {
+		var err error
+
+		conn, err = net.Dial("unix", socketPath)
+
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return conn
+}
+
+func TestFeedSocket(t *testing.T) {
+	pcm := bytes.Repeat([]byte{0x01, 0x02}, 8000*2/10) // 0.2s at 8 kHz
+
+	tests := []struct {
+		name  string
+		audio []byte
+	}{
+		{
+			name:  "raw pcm",
+			audio: pcm,
+		},
+		{
+			name:  "wav file",
+			audio: newWAV(1, 16, 8000, pcm),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			socketPath := filepath.Join(t.TempDir(), "audio.sock")
+			errCh := make(chan error, 1)
+
+			go func() {
+				errCh <- FeedSocket(
+					context.Background(), socketPath,
+					bytes.NewReader(tt.audio), 8000,
+				)
+			}()
+
+			conn := connectToFeed(t, socketPath)
+			defer func() { _ = conn.Close() }()
+
+			start := time.Now()
+
+			received, err := io.ReadAll(conn)
+			require.NoError(t, err)
+			assert.Equal(t, pcm, received)
+
+			// Sent in real time rather than all at once
+			assert.GreaterOrEqual(
+				t, time.Since(start), 200*time.Millisecond-feedChunkDuration,
+			)
+
+			require.NoError(t, <-errCh)
+
+			// The socket is gone once the audio has been fed
+			_, err = os.Stat(socketPath)
+			assert.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestFeedSocket_ContextCanceled(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "audio.sock")
+
+	// Waiting for the module to connect
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := FeedSocket(ctx, socketPath, bytes.NewReader(nil), 8000)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Feeding audio that lasts far longer than the context
+	ctx, cancel = context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- FeedSocket(
+			ctx, socketPath, bytes.NewReader(make([]byte, 8000*2*60)), 8000,
+		)
+	}()
+
+	conn := connectToFeed(t, socketPath)
+	defer func() { _ = conn.Close() }()
+
+	go func() { _, _ = io.Copy(io.Discard, conn) }()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("FeedSocket did not return after cancel")
+	}
+}
+
+func TestFeedSocket_StaleSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "audio.sock")
+
+	// A socket file left behind by a crashed run
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- FeedSocket(
+			context.Background(), socketPath, strings.NewReader("ab"), 8000,
+		)
+	}()
+
+	conn := connectToFeed(t, socketPath)
+	defer func() { _ = conn.Close() }()
+
+	received, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", string(received))
+	require.NoError(t, <-errCh)
+}
+
+func TestFeedSocket_InvalidInput(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "audio.sock")
+	pcm := make([]byte, 16)
+
+	tests := []struct {
+		name        string
+		socketPath  string
+		audio       []byte
+		sampleRate  int
+		expectedErr error
+		errorMsg    string
+	}{
+		{
+			name:        "missing socket path",
+			audio:       pcm,
+			sampleRate:  8000,
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name:        "zero sample rate",
+			socketPath:  socketPath,
+			audio:       pcm,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "stereo wav",
+			socketPath:  socketPath,
+			audio:       newWAV(2, 16, 8000, pcm),
+			sampleRate:  8000,
+			expectedErr: commonerrors.ErrInvalidValue,
+			errorMsg:    "mono 16-bit PCM",
+		},
+		{
+			name:        "8-bit wav",
+			socketPath:  socketPath,
+			audio:       newWAV(1, 8, 8000, pcm),
+			sampleRate:  8000,
+			expectedErr: commonerrors.ErrInvalidValue,
+			errorMsg:    "mono 16-bit PCM",
+		},
+		{
+			name:        "wav sample rate mismatch",
+			socketPath:  socketPath,
+			audio:       newWAV(1, 16, 44100, pcm),
+			sampleRate:  48000,
+			expectedErr: commonerrors.ErrInvalidValue,
+			errorMsg:    "44100 Hz, expected 48000 Hz",
+		},
+		{
+			name:        "wav without data",
+			socketPath:  socketPath,
+			audio:       []byte("RIFF\x04\x00\x00\x00WAVE"),
+			sampleRate:  8000,
+			expectedErr: commonerrors.ErrInvalidValue,
+			errorMsg:    "no data chunk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FeedSocket(
+				context.Background(), tt.socketPath,
+				bytes.NewReader(tt.audio), tt.sampleRate,
+			)
+			require.ErrorIs(t, err, tt.expectedErr)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
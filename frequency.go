@@ -0,0 +1,243 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// gHzToMHzMultiplier is the conversion factor from GHz to MHz.
+const gHzToMHzMultiplier = 1000.0
+
+// frequencyUnits maps the lower-cased unit suffixes accepted by
+// ParseFrequency to the conversion of a value in that unit to Hz. "FM" is
+// broadcast shorthand for MHz, e.g. "107.9 FM".
+var frequencyUnits = map[string]func(float64) float64{ //nolint:gochecknoglobals
+	"":    func(hz float64) float64 { return hz },
+	"hz":  func(hz float64) float64 { return hz },
+	"khz": func(kHz float64) float64 { return mHzToHz(kHzToMHz(kHz)) },
+	"mhz": mHzToHz,
+	"fm":  mHzToHz,
+	"ghz": func(gHz float64) float64 { return mHzToHz(gHz * gHzToMHzMultiplier) },
+}
+
+// ParseFrequency parses a human-friendly frequency string and returns it in
+// Hz, rounded to the nearest Hz. A number without a unit is taken as Hz and
+// the units are case-insensitive, e.g. "14.070MHz", "107.9 FM", "466230000"
+// and "433.92 kHz" are all accepted.
+func ParseFrequency(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	unitStart := strings.IndexFunc(s, unicode.IsLetter)
+	if unitStart < 0 {
+		unitStart = len(s)
+	}
+
+	number := strings.TrimSpace(s[:unitStart])
+	unit := strings.ToLower(strings.TrimSpace(s[unitStart:]))
+
+	toHz, ok := frequencyUnits[unit]
+	if !ok {
+		return 0, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unknown frequency unit %q in %q",
+			unit, s,
+		)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid frequency %q",
+			s,
+		)
+	}
+
+	if value <= 0 {
+		return 0, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency must be positive, got: %q",
+			s,
+		)
+	}
+
+	return math.Round(toHz(value)), nil
+}
+
+// Frequency is a frequency in Hz. It marshals to JSON as a string with an
+// explicit unit, e.g. "14074000 Hz", so it can be used for any module
+// frequency field, whether the module expects Hz or MHz. It unmarshals from
+// a number of Hz or from any string ParseFrequency accepts.
+type Frequency float64
+
+// FrequencyFromHz returns the Frequency for a value in Hz.
+func FrequencyFromHz(hz float64) Frequency {
+	return Frequency(hz)
+}
+
+// FrequencyFromKHz returns the Frequency for a value in kHz.
+func FrequencyFromKHz(kHz float64) Frequency {
+	return Frequency(mHzToHz(kHzToMHz(kHz)))
+}
+
+// FrequencyFromMHz returns the Frequency for a value in MHz.
+func FrequencyFromMHz(mHz float64) Frequency {
+	return Frequency(mHzToHz(mHz))
+}
+
+// Hz returns the frequency in Hz.
+func (f Frequency) Hz() float64 {
+	return float64(f)
+}
+
+// KHz returns the frequency in kHz.
+func (f Frequency) KHz() float64 {
+	return mHzToKHz(f.MHz())
+}
+
+// MHz returns the frequency in MHz.
+func (f Frequency) MHz() float64 {
+	return hzToMHz(float64(f))
+}
+
+// String returns the frequency in the largest unit that keeps it at or
+// above 1, e.g. "14.074 MHz" or "472.5 kHz".
+func (f Frequency) String() string {
+	switch {
+	case math.Abs(f.MHz()) >= 1:
+		return strconv.FormatFloat(f.MHz(), 'f', -1, 64) + " MHz"
+	case math.Abs(f.KHz()) >= 1:
+		return strconv.FormatFloat(f.KHz(), 'f', -1, 64) + " kHz"
+	default:
+		return strconv.FormatFloat(f.Hz(), 'f', -1, 64) + " Hz"
+	}
+}
+
+// MarshalJSON encodes the frequency as a string in Hz with its unit.
+func (f Frequency) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(strconv.FormatFloat(f.Hz(), 'f', -1, 64) + " Hz")
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to marshal frequency")
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON decodes a number of Hz or a frequency string.
+func (f *Frequency) UnmarshalJSON(data []byte) error {
+	var hz float64
+	if err := json.Unmarshal(data, &hz); err == nil {
+		*f = Frequency(hz)
+
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency must be a number or a string, got: %s",
+			data,
+		)
+	}
+
+	hz, err := ParseFrequency(value)
+	if err != nil {
+		return err
+	}
+
+	*f = Frequency(hz)
+
+	return nil
+}
+
+// normalizeFreqArgs replaces frequency strings in the args with numbers in
+// the unit the module field expects, so every module accepts e.g.
+// "14.070MHz" wherever it takes a frequency. The fields are the top-level
+// ones carrying a freq schema tag. Args that aren't a JSON object are
+// returned unchanged for ParseArgs to report.
+func normalizeFreqArgs(
+	module Module,
+	args json.RawMessage,
+) (json.RawMessage, error) {
+	freqFields := moduleFreqFields(module)
+	if len(freqFields) == 0 {
+		return args, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(args, &fields); err != nil {
+		return args, nil //nolint:nilerr // ParseArgs reports invalid JSON
+	}
+
+	changed := false
+
+	for name, unit := range freqFields {
+		var value string
+		if err := json.Unmarshal(fields[name], &value); err != nil {
+			continue // not a string, leave it to ParseArgs
+		}
+
+		freqHz, err := ParseFrequency(value)
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "field %s", name)
+		}
+
+		if unit == schemaFreqMHz {
+			freqHz = hzToMHz(freqHz)
+		}
+
+		fields[name] = json.RawMessage(strconv.FormatFloat(freqHz, 'f', -1, 64))
+		changed = true
+	}
+
+	if !changed {
+		return args, nil
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to marshal args")
+	}
+
+	return normalized, nil
+}
+
+// moduleFreqFields returns the JSON names of the module fields tagged as
+// frequencies, mapped to their unit.
+func moduleFreqFields(module Module) map[string]string {
+	t := reflect.TypeOf(module)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	freqFields := map[string]string{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		for entry := range strings.SplitSeq(field.Tag.Get(schemaTagName), ";") {
+			if unit, ok := strings.CutPrefix(entry, "freq="); ok {
+				freqFields[name] = unit
+			}
+		}
+	}
+
+	return freqFields
+}
@@ -0,0 +1,229 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrequency(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  float64
+		expectErr bool
+	}{
+		{"MHz without space", "14.070MHz", 14070000, false},
+		{"FM shorthand", "107.9 FM", 107900000, false},
+		{"bare Hz", "466230000", 466230000, false},
+		{"kHz with space", "433.92 kHz", 433920, false},
+		{"Hz unit", "7040000 Hz", 7040000, false},
+		{"GHz", "1.2GHz", 1200000000, false},
+		{"lower case", "144.39mhz", 144390000, false},
+		{"upper case", "1500 KHZ", 1500000, false},
+		{"surrounding spaces", "  28.074 MHz  ", 28074000, false},
+		{"rounds to Hz", "14.0700004 MHz", 14070000, false},
+		{"empty", "", 0, true},
+		{"unit only", "MHz", 0, true},
+		{"unknown unit", "14 parsecs", 0, true},
+		{"garbage number", "14.0.7 MHz", 0, true},
+		{"zero", "0 MHz", 0, true},
+		{"negative", "-14 MHz", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			freqHz, err := ParseFrequency(tt.input)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, freqHz)
+		})
+	}
+}
+
+func TestNormalizeFreqArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		module    Module
+		args      string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "hz field string",
+			module:   &TUNE{},
+			args:     `{"frequency":"434 MHz"}`,
+			expected: `{"frequency":434000000}`,
+		},
+		{
+			name:     "mhz field string",
+			module:   &PIFMRDS{},
+			args:     `{"freq":"107.9 FM","audio":"a.wav"}`,
+			expected: `{"audio":"a.wav","freq":107.9}`,
+		},
+		{
+			name:     "number left alone",
+			module:   &TUNE{},
+			args:     `{"frequency":434000000}`,
+			expected: `{"frequency":434000000}`,
+		},
+		{
+			name:     "invalid json left to ParseArgs",
+			module:   &TUNE{},
+			args:     `not json`,
+			expected: `not json`,
+		},
+		{
+			name:      "invalid frequency string",
+			module:    &TUNE{},
+			args:      `{"frequency":"fast"}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := normalizeFreqArgs(tt.module, json.RawMessage(tt.args))
+			if tt.expectErr {
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(args))
+		})
+	}
+}
+
+func TestRPITX_FrequencyStrings(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New()
+	require.NoError(t, err)
+
+	require.NoError(t, r.ValidateArgs(
+		ModuleNameMORSE,
+		json.RawMessage(`{"frequency":"14.070MHz","rate":20,"message":"CQ"}`),
+	))
+
+	_, cmdArgs, _, err := r.PreviewCommand(
+		ModuleNameTUNE,
+		json.RawMessage(`{"frequency":"433.92 MHz"}`),
+	)
+	require.NoError(t, err)
+	assert.Contains(t, cmdArgs[len(cmdArgs)-1], "433920000")
+}
+
+func TestFrequency_Units(t *testing.T) {
+	tests := []struct {
+		name        string
+		freq        Frequency
+		expectedHz  float64
+		expectedKHz float64
+		expectedMHz float64
+		expectedStr string
+	}{
+		{
+			name:        "from MHz",
+			freq:        FrequencyFromMHz(14.074),
+			expectedHz:  14074000,
+			expectedKHz: 14074,
+			expectedMHz: 14.074,
+			expectedStr: "14.074 MHz",
+		},
+		{
+			name:        "from kHz",
+			freq:        FrequencyFromKHz(472.5),
+			expectedHz:  472500,
+			expectedKHz: 472.5,
+			expectedMHz: 0.4725,
+			expectedStr: "472.5 kHz",
+		},
+		{
+			name:        "from Hz",
+			freq:        FrequencyFromHz(500),
+			expectedHz:  500,
+			expectedKHz: 0.5,
+			expectedMHz: 0.0005,
+			expectedStr: "500 Hz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expectedHz, tt.freq.Hz(), 1e-6)
+			assert.InDelta(t, tt.expectedKHz, tt.freq.KHz(), 1e-9)
+			assert.InDelta(t, tt.expectedMHz, tt.freq.MHz(), 1e-12)
+			assert.Equal(t, tt.expectedStr, tt.freq.String())
+		})
+	}
+}
+
+func TestFrequency_JSON(t *testing.T) {
+	data, err := json.Marshal(FrequencyFromMHz(107.9))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"107900000 Hz"`, string(data))
+
+	tests := []struct {
+		name      string
+		input     string
+		expected  Frequency
+		expectErr bool
+	}{
+		{"number of Hz", `14074000`, 14074000, false},
+		{"string", `"14.074 MHz"`, 14074000, false},
+		{"marshaled form", `"107900000 Hz"`, 107900000, false},
+		{"invalid string", `"fast"`, 0, true},
+		{"wrong type", `true`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var freq Frequency
+
+			err := json.Unmarshal([]byte(tt.input), &freq)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, freq)
+		})
+	}
+}
+
+func TestFrequency_ModuleArgs(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New()
+	require.NoError(t, err)
+
+	// The same Frequency works for Hz and MHz module fields.
+	freq := FrequencyFromMHz(107.9)
+
+	pifmrdsArgs, err := json.Marshal(map[string]any{
+		"freq":  freq,
+		"audio": ".fixtures/test.wav",
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.ValidateArgs(ModuleNamePIFMRDS, pifmrdsArgs))
+
+	tuneArgs, err := json.Marshal(map[string]any{"frequency": freq})
+	require.NoError(t, err)
+
+	_, cmdArgs, _, err := r.PreviewCommand(ModuleNameTUNE, tuneArgs)
+	require.NoError(t, err)
+	assert.Contains(t, cmdArgs[len(cmdArgs)-1], "107900000")
+}
@@ -1,8 +1,10 @@
 package gorpitx
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
+	"math/bits"
 	"os"
 	"strconv"
 	"strings"
@@ -19,18 +21,50 @@ const (
 	defaultFSKBaudRate = 50
 )
 
+// FSKPreset selects a predefined FSK configuration.
+type FSKPreset = string
+
+const (
+	// FSKPresetRTTY45 is classic 45.45 baud Baudot RTTY.
+	FSKPresetRTTY45 FSKPreset = "rtty45"
+	// FSKPresetRTTY75 is 75 baud Baudot RTTY.
+	FSKPresetRTTY75 FSKPreset = "rtty75"
+)
+
+// fskPresetBaudRates maps presets to the baud rate passed to the FSK script.
+//
+//nolint:gochecknoglobals
+var fskPresetBaudRates = map[FSKPreset]string{
+	FSKPresetRTTY45: "45.45",
+	FSKPresetRTTY75: "75",
+}
+
+// FSKParity selects the parity bit added to each character.
+type FSKParity = string
+
+const (
+	FSKParityNone FSKParity = "none"
+	FSKParityEven FSKParity = "even"
+	FSKParityOdd  FSKParity = "odd"
+)
+
+// asciiMask keeps the 7 data bits of a character sent with parity.
+const asciiMask = 0x7f
+
 // InputType defines the type of input for FSK transmission.
 type InputType = string
 
 const (
-	InputTypeFile InputType = "file"
-	InputTypeText InputType = "text"
+	InputTypeFile   InputType = "file"
+	InputTypeText   InputType = "text"
+	InputTypeReader InputType = "reader"
 )
 
 type FSK struct {
-	// InputType specifies whether input is from file or text. Required parameter.
-	// Must be either "file" or "text".
-	InputType InputType `json:"inputType"`
+	// InputType specifies whether input is from file, text or the reader
+	// passed to the execution with WithExecReader. Required parameter. Must
+	// be "file", "text" or "reader".
+	InputType InputType `json:"inputType" schema:"enum=file|text|reader"`
 
 	// File specifies the path to input file. Required when InputType is "file".
 	// Cannot be specified when InputType is "text".
@@ -42,11 +76,39 @@ type FSK struct {
 
 	// BaudRate specifies the transmission baud rate. Optional parameter.
 	// Default: 50 baud (cleanest in testing with rpitx FSK transmission)
-	BaudRate *int `json:"baudRate,omitempty"`
+	// Cannot be specified together with Preset.
+	BaudRate *int `json:"baudRate,omitempty" schema:"exclusiveMinimum=0"`
+
+	// Preset selects a predefined RTTY configuration ("rtty45" or "rtty75")
+	// with Baudot encoding and 1.5 stop bits. Optional parameter.
+	Preset FSKPreset `json:"preset,omitempty" schema:"enum=rtty45|rtty75"`
+
+	// Invert swaps the mark and space tones, for receivers expecting the
+	// opposite polarity. Optional parameter. Default: false
+	Invert *bool `json:"invert,omitempty"`
+
+	// StopBits sets the number of stop bits, 1 or 2. Optional parameter.
+	// Default: 1, or 1.5 with a preset.
+	StopBits *int `json:"stopBits,omitempty" schema:"enum=1|2"`
+
+	// Parity adds a parity bit to each character: "none", "even" or "odd".
+	// The parity bit takes the place of the 8th data bit, so the input must
+	// be 7-bit ASCII. Cannot be used with a preset. Optional parameter.
+	// Default: none
+	Parity *FSKParity `json:"parity,omitempty" schema:"enum=none|even|odd"`
 
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
+
+	// reader is the input of the execution for InputTypeReader, given with
+	// WithExecReader and set on the instance of that execution only.
+	reader io.Reader
+}
+
+// setReader sets the reader given to the execution with WithExecReader.
+func (m *FSK) setReader(r io.Reader) {
+	m.reader = r
 }
 
 func (m *FSK) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -66,26 +128,74 @@ func (m *FSK) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
 	return m.buildArgs(), stdin, nil
 }
 
+// usesStdin reports that the text is fed to the binary through stdin.
+func (m *FSK) usesStdin() bool {
+	return true
+}
+
+// readsReader reports whether the input is the reader of the execution.
+func (m *FSK) readsReader() bool {
+	return m.InputType == InputTypeReader
+}
+
 // buildArgs converts the struct fields into command-line arguments for FSK
 // script.
 func (m *FSK) buildArgs() []string {
 	var args []string
 
-	// Add baud rate argument (default if not specified)
-	baudRate := defaultFSKBaudRate
-	if m.BaudRate != nil {
-		baudRate = *m.BaudRate
-	}
-
-	args = append(args, strconv.Itoa(baudRate))
+	// Add baud rate argument (preset, explicit or default)
+	args = append(args, m.baudRate())
 
 	// Add frequency argument (required)
 	args = append(args, strconv.FormatFloat(m.Frequency, 'f', 0, 64))
 
+	// Remaining arguments are passed through to minimodem by the script
+	if m.Preset != "" {
+		args = append(args, "--baudot")
+	}
+
+	if stopBits := m.stopBits(); stopBits != "" {
+		args = append(args, "--stopbits", stopBits)
+	}
+
+	if m.Invert != nil && *m.Invert {
+		args = append(args, "--inverted")
+	}
+
 	return args
 }
 
-// prepareStdin prepares the stdin reader based on input type.
+// baudRate returns the baud rate argument for the FSK script.
+func (m *FSK) baudRate() string {
+	if m.Preset != "" {
+		return fskPresetBaudRates[m.Preset]
+	}
+
+	if m.BaudRate != nil {
+		return strconv.Itoa(*m.BaudRate)
+	}
+
+	return strconv.Itoa(defaultFSKBaudRate)
+}
+
+// stopBits returns the stop bits argument for minimodem, empty for its
+// default of 1.
+func (m *FSK) stopBits() string {
+	if m.StopBits != nil {
+		return strconv.Itoa(*m.StopBits)
+	}
+
+	if m.Preset != "" {
+		return "1.5"
+	}
+
+	return ""
+}
+
+// prepareStdin prepares the stdin reader based on input type. A file is
+// only opened once the reader is read, when the module is launched, so args
+// that are only parsed, e.g. by ValidateArgs or PreviewCommand, leave no
+// file open.
 func (m *FSK) prepareStdin() (io.Reader, error) {
 	var baseReader io.Reader
 
@@ -93,16 +203,15 @@ func (m *FSK) prepareStdin() (io.Reader, error) {
 	case InputTypeText:
 		baseReader = strings.NewReader(m.Text)
 	case InputTypeFile:
-		file, err := os.Open(m.File)
-		if err != nil {
-			return nil, ctxerrors.Wrapf(
-				err,
-				"failed to open file: %s",
-				m.File,
-			)
+		baseReader = &lazyFile{path: m.File}
+	case InputTypeReader:
+		baseReader = m.reader
+
+		// Only an execution gets the reader, parsing to validate or
+		// preview the args goes without
+		if baseReader == nil {
+			baseReader = strings.NewReader("")
 		}
-
-		baseReader = file
 	default:
 		return nil, ctxerrors.Wrapf(
 			commonerrors.ErrInvalidValue,
@@ -111,31 +220,108 @@ func (m *FSK) prepareStdin() (io.Reader, error) {
 		)
 	}
 
-	return io.MultiReader(
+	reader := io.MultiReader(
 		baseReader,
 		strings.NewReader("\n"),
-	), nil
+	)
+
+	if m.Parity == nil || *m.Parity == FSKParityNone {
+		return reader, nil
+	}
+
+	parity := &parityReader{reader: reader, even: *m.Parity == FSKParityEven}
+
+	// A stream is converted as it is read, so a character that isn't 7-bit
+	// ASCII ends it. The whole text or file is checked up front instead,
+	// the file being closed once read.
+	if m.InputType == InputTypeReader {
+		return parity, nil
+	}
+
+	data, err := io.ReadAll(parity)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
 }
 
-// validate validates all FSK parameters.
-func (m *FSK) validate() error {
-	if err := m.validateInputType(); err != nil {
-		return err
+// lazyFile opens the file on its first Read and closes it once it has been
+// read to the end or fails.
+type lazyFile struct {
+	path string
+	file *os.File
+	err  error
+}
+
+func (f *lazyFile) Read(p []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
 	}
 
-	if err := m.validateInputFields(); err != nil {
-		return err
+	if f.file == nil {
+		file, err := os.Open(f.path)
+		if err != nil {
+			f.err = ctxerrors.Wrapf(err, "failed to open file: %s", f.path)
+
+			return 0, f.err
+		}
+
+		f.file = file
 	}
 
-	if err := m.validateBaudRate(); err != nil {
-		return err
+	n, err := f.file.Read(p)
+	if err != nil {
+		_ = f.file.Close()
+		f.err = err
 	}
 
-	if err := m.validateFrequency(); err != nil {
-		return err
+	return n, err //nolint:wrapcheck // passed through like the file's
+}
+
+// parityReader sets the 8th bit of every character it reads to its parity,
+// so minimodem sends 7 data bits and a parity bit.
+type parityReader struct {
+	reader io.Reader
+	even   bool
+	offset int
+}
+
+func (r *parityReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	for i, c := range p[:n] {
+		if c > asciiMask {
+			return i, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"parity needs 7-bit ASCII input, got byte 0x%02x at %d",
+				c, r.offset+i,
+			)
+		}
+
+		// Even parity makes the number of set bits even, counting the
+		// parity bit
+		odd := bits.OnesCount8(c)%2 == 1
+		if odd == r.even {
+			p[i] = c | (asciiMask + 1)
+		}
 	}
 
-	return nil
+	r.offset += n
+
+	return n, err //nolint:wrapcheck // passed through like the reader's
+}
+
+// validate validates all FSK parameters.
+func (m *FSK) validate() error {
+	return validateFields(
+		fieldCheck{"inputType", m.validateInputType},
+		fieldCheck{"inputType", m.validateInputFields},
+		fieldCheck{"baudRate", m.validateBaudRate},
+		fieldCheck{"preset", m.validatePreset},
+		fieldCheck{"parity", m.validateFraming},
+		fieldCheck{"frequency", m.validateFrequency},
+	)
 }
 
 // validateInputType validates the input type parameter.
@@ -144,10 +330,12 @@ func (m *FSK) validateInputType() error {
 		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "inputType")
 	}
 
-	if m.InputType != InputTypeFile && m.InputType != InputTypeText {
+	switch m.InputType {
+	case InputTypeFile, InputTypeText, InputTypeReader:
+	default:
 		return ctxerrors.Wrapf(
 			commonerrors.ErrInvalidValue,
-			"inputType must be 'file' or 'text', got: %s",
+			"inputType must be 'file', 'text' or 'reader', got: %s",
 			m.InputType,
 		)
 	}
@@ -160,20 +348,24 @@ func (m *FSK) validateInputFields() error {
 	switch m.InputType {
 	case InputTypeFile:
 		if strings.TrimSpace(m.File) == "" {
-			return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "file")
+			return fieldError("file", ctxerrors.Wrap(
+				commonerrors.ErrRequiredFieldNotSet, "file",
+			))
 		}
 
 		// Check if file exists
 		if _, err := os.Stat(m.File); os.IsNotExist(err) {
-			return ctxerrors.Wrapf(
+			return fieldError("file", ctxerrors.Wrapf(
 				commonerrors.ErrFileNotFound,
 				"input file: %s",
 				m.File,
-			)
+			))
 		}
 	case InputTypeText:
 		if strings.TrimSpace(m.Text) == "" {
-			return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "text")
+			return fieldError("text", ctxerrors.Wrap(
+				commonerrors.ErrRequiredFieldNotSet, "text",
+			))
 		}
 	}
 
@@ -193,6 +385,71 @@ func (m *FSK) validateBaudRate() error {
 	return nil
 }
 
+// validatePreset validates the preset parameter.
+func (m *FSK) validatePreset() error {
+	if m.Preset == "" {
+		return nil
+	}
+
+	if _, ok := fskPresetBaudRates[m.Preset]; !ok {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"preset must be '%s' or '%s', got: %s",
+			FSKPresetRTTY45, FSKPresetRTTY75, m.Preset,
+		)
+	}
+
+	if m.BaudRate != nil {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"preset and baudRate cannot both be specified",
+		)
+	}
+
+	return nil
+}
+
+// validateFraming validates the stop bits and parity parameters.
+func (m *FSK) validateFraming() error {
+	if m.StopBits != nil && *m.StopBits != 1 && *m.StopBits != 2 {
+		return fieldError("stopBits", ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"stopBits must be 1 or 2, got: %d",
+			*m.StopBits,
+		))
+	}
+
+	if m.Parity == nil {
+		return nil
+	}
+
+	switch *m.Parity {
+	case FSKParityNone:
+		return nil
+	case FSKParityEven, FSKParityOdd:
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"parity must be '%s', '%s' or '%s', got: %s",
+			FSKParityNone, FSKParityEven, FSKParityOdd, *m.Parity,
+		)
+	}
+
+	if m.Preset != "" {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"parity cannot be used with a preset, Baudot has no parity bit",
+		)
+	}
+
+	return nil
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *FSK) frequencyHz() float64 {
+	return m.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (m *FSK) validateFrequency() error {
 	if m.Frequency <= 0 {
@@ -208,7 +465,7 @@ func (m *FSK) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
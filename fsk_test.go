@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,7 +126,7 @@ func TestFSK_ParseArgs_ValidationErrors(t *testing.T) {
 				Text:      "TEST",
 				Frequency: 431000000.0,
 			},
-			expectedError: "inputType must be 'file' or 'text'",
+			expectedError: "inputType must be 'file', 'text' or 'reader'",
 		},
 		{
 			name: "missing text for text input",
@@ -275,7 +278,7 @@ func TestFSK_validateInputType(t *testing.T) {
 			name:        "invalid input type",
 			inputType:   "invalid",
 			expectError: true,
-			errorMsg:    "inputType must be 'file' or 'text'",
+			errorMsg:    "inputType must be 'file', 'text' or 'reader'",
 		},
 	}
 
@@ -361,6 +364,57 @@ func TestFSK_validateFrequency(t *testing.T) {
 	}
 }
 
+func TestFSK_validatePreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsk         FSK
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "no preset",
+			fsk:  FSK{},
+		},
+		{
+			name: "rtty45",
+			fsk:  FSK{Preset: FSKPresetRTTY45},
+		},
+		{
+			name: "rtty75",
+			fsk:  FSK{Preset: FSKPresetRTTY75},
+		},
+		{
+			name:        "unknown preset",
+			fsk:         FSK{Preset: "rtty110"},
+			expectError: true,
+			errorMsg:    "preset must be",
+		},
+		{
+			name: "preset with baud rate",
+			fsk: FSK{
+				Preset:   FSKPresetRTTY45,
+				BaudRate: intPtr(50),
+			},
+			expectError: true,
+			errorMsg:    "cannot both be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fsk.validatePreset()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestFSK_buildArgs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -390,6 +444,49 @@ func TestFSK_buildArgs(t *testing.T) {
 			},
 			expectedArgs: []string{"1200", "1296000000"},
 		},
+		{
+			name: "rtty45 preset",
+			fsk: FSK{
+				Preset:    FSKPresetRTTY45,
+				Frequency: 14085000.0,
+			},
+			expectedArgs: []string{
+				"45.45", "14085000", "--baudot", "--stopbits", "1.5",
+			},
+		},
+		{
+			name: "rtty75 preset",
+			fsk: FSK{
+				Preset:    FSKPresetRTTY75,
+				Frequency: 14085000.0,
+			},
+			expectedArgs: []string{
+				"75", "14085000", "--baudot", "--stopbits", "1.5",
+			},
+		},
+		{
+			name: "inverted with 2 stop bits",
+			fsk: FSK{
+				Invert:    boolPtr(true),
+				StopBits:  intPtr(2),
+				Frequency: 144500000.0,
+			},
+			expectedArgs: []string{
+				"50", "144500000", "--stopbits", "2", "--inverted",
+			},
+		},
+		{
+			name: "preset stop bits overridden",
+			fsk: FSK{
+				Preset:    FSKPresetRTTY45,
+				StopBits:  intPtr(2),
+				Invert:    boolPtr(false),
+				Frequency: 14085000.0,
+			},
+			expectedArgs: []string{
+				"45.45", "14085000", "--baudot", "--stopbits", "2",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -445,15 +542,6 @@ func TestFSK_prepareStdin(t *testing.T) {
 			expectError: true,
 			errorMsg:    "invalid input type",
 		},
-		{
-			name: "non-existent file",
-			fsk: FSK{
-				InputType: InputTypeFile,
-				File:      "/non/existent/file.txt",
-			},
-			expectError: true,
-			errorMsg:    "failed to open file",
-		},
 	}
 
 	for _, tt := range tests {
@@ -481,3 +569,233 @@ func TestFSK_prepareStdin(t *testing.T) {
 		})
 	}
 }
+
+func TestFSK_prepareStdin_MissingFile(t *testing.T) {
+	fsk := FSK{
+		InputType: InputTypeFile,
+		File:      "/non/existent/file.txt",
+	}
+
+	// The file is only opened once the input is read
+	stdin, err := fsk.prepareStdin()
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(stdin)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Contains(t, err.Error(), "failed to open file")
+}
+
+func TestFSK_ParseArgs_LeavesNoFileOpen(t *testing.T) {
+	if _, err := os.Stat("/proc/self/fd"); err != nil {
+		t.Skip("no /proc/self/fd")
+	}
+
+	file := filepath.Join(t.TempDir(), "input.txt")
+	require.NoError(t, os.WriteFile(file, []byte("CQ CQ"), 0o600))
+
+	rpitx := &RPITX{
+		logger: NopLogger(),
+		modules: map[ModuleName]Module{
+			ModuleNameFSK: &FSK{},
+		},
+	}
+
+	openFiles := func() int {
+		entries, err := os.ReadDir("/proc/self/fd")
+		require.NoError(t, err)
+
+		return len(entries)
+	}
+
+	for _, parity := range []FSKParity{FSKParityNone, FSKParityEven} {
+		args, err := json.Marshal(map[string]any{
+			"inputType": InputTypeFile,
+			"file":      file,
+			"frequency": 434000000,
+			"parity":    parity,
+		})
+		require.NoError(t, err)
+
+		before := openFiles()
+
+		for range 50 {
+			require.NoError(t, rpitx.ValidateArgs(ModuleNameFSK, args))
+
+			_, _, _, err := rpitx.PreviewCommand(ModuleNameFSK, args)
+			require.NoError(t, err)
+		}
+
+		// Allow for the runtime opening a descriptor or two meanwhile
+		assert.LessOrEqual(t, openFiles(), before+2, "parity %s", parity)
+	}
+}
+
+func TestFSK_validateFraming(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsk         FSK
+		expectError bool
+	}{
+		{name: "defaults", fsk: FSK{}},
+		{name: "1 stop bit", fsk: FSK{StopBits: intPtr(1)}},
+		{name: "2 stop bits", fsk: FSK{StopBits: intPtr(2)}},
+		{name: "0 stop bits", fsk: FSK{StopBits: intPtr(0)}, expectError: true},
+		{name: "3 stop bits", fsk: FSK{StopBits: intPtr(3)}, expectError: true},
+		{name: "no parity", fsk: FSK{Parity: stringPtr(FSKParityNone)}},
+		{name: "even parity", fsk: FSK{Parity: stringPtr(FSKParityEven)}},
+		{name: "odd parity", fsk: FSK{Parity: stringPtr(FSKParityOdd)}},
+		{
+			name:        "unknown parity",
+			fsk:         FSK{Parity: stringPtr("mark")},
+			expectError: true,
+		},
+		{
+			name: "no parity with preset",
+			fsk: FSK{
+				Preset: FSKPresetRTTY45,
+				Parity: stringPtr(FSKParityNone),
+			},
+		},
+		{
+			name: "parity with preset",
+			fsk: FSK{
+				Preset: FSKPresetRTTY45,
+				Parity: stringPtr(FSKParityEven),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fsk.validateFraming()
+			if tt.expectError {
+				require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFSK_prepareStdin_Parity(t *testing.T) {
+	tests := []struct {
+		name     string
+		parity   FSKParity
+		text     string
+		expected []byte
+	}{
+		{
+			name:   "none",
+			parity: FSKParityNone,
+			text:   "CQ",
+			// C = 0x43 and Q = 0x51 have 3 bits set, the newline 2
+			expected: []byte{0x43, 0x51, '\n'},
+		},
+		{
+			name:     "even",
+			parity:   FSKParityEven,
+			text:     "CQ",
+			expected: []byte{0xc3, 0xd1, '\n'},
+		},
+		{
+			name:     "odd",
+			parity:   FSKParityOdd,
+			text:     "CQ",
+			expected: []byte{0x43, 0x51, 0x8a},
+		},
+		{
+			name:     "odd with an even character",
+			parity:   FSKParityOdd,
+			text:     "A",
+			expected: []byte{0xc1, 0x8a},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsk := FSK{
+				InputType: InputTypeText,
+				Text:      tt.text,
+				Parity:    stringPtr(tt.parity),
+			}
+
+			stdin, err := fsk.prepareStdin()
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(stdin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, data)
+		})
+	}
+
+	fsk := FSK{
+		InputType: InputTypeText,
+		Text:      "CAFÉ",
+		Parity:    stringPtr(FSKParityEven),
+	}
+
+	_, err := fsk.prepareStdin()
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestFSK_ParseArgs_Reader(t *testing.T) {
+	args := json.RawMessage(`{"inputType": "reader", "frequency": 144500000}`)
+
+	m := &FSK{}
+
+	// Parsed without the reader of an execution, e.g. by ValidateArgs
+	_, stdin, err := m.ParseArgs(args)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(stdin)
+	require.NoError(t, err)
+	assert.Equal(t, "\n", string(data))
+
+	feed, writer := io.Pipe()
+	m.setReader(feed)
+
+	// The reader is kept, even through the reset of the args
+	for range 2 {
+		cmdArgs, stdin, err := m.ParseArgs(args)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"50", "144500000"}, cmdArgs)
+		require.NotNil(t, stdin)
+	}
+
+	_, stdin, err = m.ParseArgs(args)
+	require.NoError(t, err)
+
+	// Streamed as it is written, not buffered until the end
+	go func() {
+		_, _ = writer.Write([]byte("T=21.5C"))
+	}()
+
+	buf := make([]byte, 16)
+	n, err := stdin.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "T=21.5C", string(buf[:n]))
+
+	require.NoError(t, writer.Close())
+
+	rest, err := io.ReadAll(stdin)
+	require.NoError(t, err)
+	assert.Equal(t, "\n", string(rest))
+}
+
+func TestFSK_ParseArgs_ReaderParity(t *testing.T) {
+	m := &FSK{}
+	m.setReader(strings.NewReader("A\xc3"))
+
+	_, stdin, err := m.ParseArgs(json.RawMessage(
+		`{"inputType": "reader", "parity": "odd", "frequency": 144500000}`,
+	))
+	require.NoError(t, err)
+
+	// The characters before the invalid one still go out
+	data, err := io.ReadAll(stdin)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.Equal(t, []byte{0xc1}, data)
+}
@@ -1,10 +1,15 @@
 package gorpitx
 
 import (
+	"cmp"
 	"encoding/json"
+	"fmt"
 	"io"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
@@ -16,16 +21,77 @@ const (
 	ft8OffsetMin     = 0    // Minimum frequency offset in Hz
 	ft8OffsetMax     = 2500 // Maximum frequency offset in Hz
 	ft8OffsetDefault = 1240 // Default frequency offset in Hz
+
+	ft8FreeTextMaxLength = 13 // Maximum free-text message length
+	ft8FreeTextCharset   = "0-9 A-Z space + - . / ?"
+
+	ft8SlotDuration = 15 * time.Second         // FT8 transmission period
+	ft8TxDuration   = 12640 * time.Millisecond // 79 symbols of 160 ms
+	ft8SlotEven     = 0                        // Slot starting at :00 and :30
+	ft8SlotOdd      = 1                        // Slot starting at :15 and :45
+
+	ft8TokenCQ   = "CQ"
+	ft8TokenR    = "R"
+	ft8TokenRRR  = "RRR"
+	ft8TokenRR73 = "RR73"
+	ft8Token73   = "73"
+)
+
+// FT8 message grammar building blocks, compiled once.
+//
+//nolint:gochecknoglobals
+var (
+	ft8CallsignRegex = regexp.MustCompile(
+		`^(?:[A-Z0-9]{1,4}/)?[A-Z0-9]{0,3}[0-9][A-Z0-9]{0,3}[A-Z]` +
+			`(?:/[A-Z0-9]{1,4})?$|^<[A-Z0-9/]{3,11}>$`,
+	)
+	ft8GridRegex       = regexp.MustCompile(`^[A-R]{2}[0-9]{2}$`)
+	ft8ReportRegex     = regexp.MustCompile(`^R?[+-][0-9]{2}$`)
+	ft8CQModifierRegex = regexp.MustCompile(`^(?:[A-Z]{1,4}|[0-9]{3})$`)
+	ft8RSTRegex        = regexp.MustCompile(`^5[0-9]9$`)
+	ft8ExchangeRegex   = regexp.MustCompile(`^[A-Z0-9]{1,4}$`)
+	ft8FreeTextRegex   = regexp.MustCompile(`^[0-9A-Z +\-./?]+$`)
 )
 
+// ft8BandFrequencies maps band names to their standard FT8 dial frequency
+// in Hz.
+//
+//nolint:gochecknoglobals
+var ft8BandFrequencies = map[string]float64{
+	"160m": 1840000,
+	"80m":  3573000,
+	"60m":  5357000,
+	"40m":  7074000,
+	"30m":  10136000,
+	"20m":  14074000,
+	"17m":  18100000,
+	"15m":  21074000,
+	"12m":  24915000,
+	"10m":  28074000,
+	"6m":   50313000,
+	"2m":   144174000,
+}
+
 type FT8 struct {
 	// `-f` specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"optional;freq=hz"`
+
+	// Band selects the standard FT8 dial frequency for an amateur band
+	// (e.g. "20m", "40m"). Only used when Frequency is zero; an explicit
+	// Frequency always takes precedence. Optional parameter.
+	Band string `json:"band,omitempty" schema:"enum=160m|80m|60m|40m|30m|20m|17m|15m|12m|10m|6m|2m"` //nolint:lll
 
-	// `-m` specifies the message to transmit. Required parameter.
-	// Example: "CQ CA0ALL JN06"
-	Message string `json:"message"`
+	// `-m` specifies the message to transmit. Required unless Messages is
+	// set. Example: "CQ CA0ALL JN06"
+	Message string `json:"message" schema:"optional"`
+
+	// Messages specifies a sequence to transmit instead of Message, e.g.
+	// CQ, report and RR73 of a QSO. Each message goes out in its own run of
+	// the binary, which waits for the next slot matching Slot, so they fill
+	// successive even or odd slots. Requires AutoSlot, cannot be combined
+	// with Message or Repeat. Optional parameter.
+	Messages []string `json:"messages,omitempty" schema:"minItems=1"`
 
 	// `-p` specifies clock PPM correction instead of NTP adjust.
 	// Optional parameter, defaults to automatic NTP adjustment.
@@ -33,16 +99,21 @@ type FT8 struct {
 
 	// `-o` specifies frequency offset (0-2500Hz). Optional parameter.
 	// Default: 1240Hz
-	Offset *float64 `json:"offset,omitempty"`
+	Offset *float64 `json:"offset,omitempty" schema:"minimum=0;maximum=2500"`
 
 	// `-s` specifies time slot to transmit (0 or 1). Optional parameter.
 	// 0 = first 15s slot, 1 = second 15s slot, 2 = always (every 15s)
 	// Default: 0
-	Slot *int `json:"slot,omitempty"`
+	Slot *int `json:"slot,omitempty" schema:"enum=0|1|2"`
 
 	// `-r` flag enables repeat mode (every 15s). Optional parameter.
 	// Default: false (single transmission)
 	Repeat *bool `json:"repeat,omitempty"`
+
+	// AutoSlot delays the start of the process until the next 15-second UTC
+	// boundary matching Slot (0 = :00/:30, 1 = :15/:45, 2 or unset = any).
+	// Optional parameter. Default: false (start immediately)
+	AutoSlot *bool `json:"autoSlot,omitempty"`
 }
 
 func (m *FT8) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -50,6 +121,10 @@ func (m *FT8) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
 		return nil, nil, ctxerrors.Wrap(err, "failed to unmarshal args")
 	}
 
+	if err := m.resolveBand(); err != nil {
+		return nil, nil, err
+	}
+
 	if err := m.validate(); err != nil {
 		return nil, nil, err
 	}
@@ -66,8 +141,10 @@ func (m *FT8) buildArgs() []string {
 	args = append(args, "-f",
 		strconv.FormatFloat(m.Frequency, 'f', 0, 64))
 
-	// Add message argument (required)
-	args = append(args, "-m", m.Message)
+	// Add message argument, a sequence appends each message to its run
+	if len(m.Messages) == 0 {
+		args = append(args, "-m", m.Message)
+	}
 
 	// Add PPM argument
 	if m.PPM != nil {
@@ -91,34 +168,143 @@ func (m *FT8) buildArgs() []string {
 		args = append(args, "-r")
 	}
 
+	// The message of each run of a sequence is appended after this flag
+	if len(m.Messages) > 0 {
+		args = append(args, "-m")
+	}
+
 	return args
 }
 
-// validate validates all FT8 parameters.
-func (m *FT8) validate() error {
-	if err := m.validateFrequency(); err != nil {
-		return err
+// argSequence returns the messages of a sequence, run one per slot.
+func (m *FT8) argSequence() ([]string, bool) {
+	return m.Messages, len(m.Messages) > 0
+}
+
+// startDelay returns how long to wait before starting the process so the
+// transmission begins on the requested slot boundary.
+func (m *FT8) startDelay(now time.Time) time.Duration {
+	if m.AutoSlot == nil || !*m.AutoSlot {
+		return 0
 	}
 
-	if err := m.validateMessage(); err != nil {
-		return err
+	slot := -1
+	if m.Slot != nil {
+		slot = *m.Slot
 	}
 
-	if err := m.validatePPM(); err != nil {
-		return err
+	return ft8SlotDelay(now, slot)
+}
+
+// estimatedDuration returns how long the transmission takes once the
+// process has started: one frame per message, the messages of a sequence
+// being a slot cycle apart. Repeating transmissions have no end. The binary
+// waiting for its slot itself without AutoSlot comes on top.
+func (m *FT8) estimatedDuration() (time.Duration, bool) {
+	if m.Repeat != nil && *m.Repeat {
+		return 0, false
 	}
 
-	if err := m.validateOffset(); err != nil {
-		return err
+	// Even and odd slots come every other period, slot 2 every period
+	cycle := 2 * ft8SlotDuration
+	if m.Slot != nil && *m.Slot != ft8SlotEven && *m.Slot != ft8SlotOdd {
+		cycle = ft8SlotDuration
 	}
 
-	if err := m.validateSlot(); err != nil {
-		return err
+	runs := max(len(m.Messages), 1)
+
+	return time.Duration(runs-1)*cycle + ft8TxDuration, true
+}
+
+// defaultTimeout returns the natural runtime of the transmission. The binary
+// waits for its even or odd slot, up to a full cycle of two slots, and then
+// transmits for one slot; each further message of a sequence goes out a
+// cycle later. A repeating beacon has no end, so it gets NoTimeout.
+func (m *FT8) defaultTimeout() time.Duration {
+	if m.Repeat != nil && *m.Repeat {
+		return NoTimeout
+	}
+
+	runs := max(len(m.Messages), 1)
+
+	return time.Duration(2*runs+1) * ft8SlotDuration
+}
+
+// ft8SlotDelay returns the time from now until the next FT8 slot boundary.
+// Slot 0 picks the even sequence (:00/:30), slot 1 the odd sequence
+// (:15/:45) and any other value the next 15-second boundary.
+func ft8SlotDelay(now time.Time, slot int) time.Duration {
+	cycle := ft8SlotDuration
+	target := time.Duration(0)
+
+	switch slot {
+	case ft8SlotEven:
+		cycle = 2 * ft8SlotDuration
+	case ft8SlotOdd:
+		cycle = 2 * ft8SlotDuration
+		target = ft8SlotDuration
+	}
+
+	// Unix time is aligned to UTC minutes, so the remainder is the position
+	// within the current cycle
+	elapsed := time.Duration(now.UnixNano() % int64(cycle))
+
+	return (target - elapsed + cycle) % cycle
+}
+
+// resolveBand fills in Frequency from Band when no explicit frequency was
+// given.
+func (m *FT8) resolveBand() error {
+	if m.Band == "" {
+		return nil
+	}
+
+	freq, ok := ft8BandFrequencies[strings.ToLower(m.Band)]
+	if !ok {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unknown FT8 band %q, must be one of: %s",
+			m.Band, strings.Join(FT8Bands(), ", "),
+		)
+	}
+
+	if m.Frequency == 0 {
+		m.Frequency = freq
 	}
 
 	return nil
 }
 
+// FT8Bands returns the sorted list of band names accepted by FT8.Band.
+func FT8Bands() []string {
+	bands := make([]string, 0, len(ft8BandFrequencies))
+	for band := range ft8BandFrequencies {
+		bands = append(bands, band)
+	}
+
+	slices.SortFunc(bands, func(a, b string) int {
+		return cmp.Compare(ft8BandFrequencies[a], ft8BandFrequencies[b])
+	})
+
+	return bands
+}
+
+// validate validates all FT8 parameters.
+func (m *FT8) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"message", m.validateMessage},
+		fieldCheck{"ppm", m.validatePPM},
+		fieldCheck{"offset", m.validateOffset},
+		fieldCheck{"slot", m.validateSlot},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *FT8) frequencyHz() float64 {
+	return m.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (m *FT8) validateFrequency() error {
 	if m.Frequency <= 0 {
@@ -134,7 +320,7 @@ func (m *FT8) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -143,13 +329,179 @@ func (m *FT8) validateFrequency() error {
 
 // validateMessage validates the message parameter.
 func (m *FT8) validateMessage() error {
+	if len(m.Messages) > 0 {
+		return m.validateMessages()
+	}
+
 	if strings.TrimSpace(m.Message) == "" {
 		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "message")
 	}
 
+	return validateFT8Grammar(m.Message)
+}
+
+// validateMessages validates a message sequence.
+func (m *FT8) validateMessages() error {
+	if m.Message != "" {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"message and messages cannot both be specified",
+		)
+	}
+
+	if m.AutoSlot == nil || !*m.AutoSlot {
+		return fieldError("autoSlot", ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"messages requires autoSlot",
+		))
+	}
+
+	if m.Repeat != nil && *m.Repeat {
+		return fieldError("repeat", ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"messages cannot be combined with repeat",
+		))
+	}
+
+	var errs ValidationErrors
+
+	for i, message := range m.Messages {
+		if err := validateFT8SequenceMessage(message, i); err != nil {
+			errs = append(errs, newValidationError(
+				fmt.Sprintf("messages[%d]", i), err,
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateFT8SequenceMessage validates a message of a sequence.
+func validateFT8SequenceMessage(message string, index int) error {
+	if strings.TrimSpace(message) == "" {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrRequiredFieldNotSet,
+			"messages[%d]",
+			index,
+		)
+	}
+
+	if err := validateFT8Grammar(message); err != nil {
+		return ctxerrors.Wrapf(err, "messages[%d]", index)
+	}
+
 	return nil
 }
 
+// validateFT8Grammar checks that a message is either one of the standard
+// FT8 message forms or fits the FT8 free-text limits. Anything else won't
+// encode and would just transmit noise.
+func validateFT8Grammar(message string) error {
+	normalized := strings.ToUpper(strings.TrimSpace(message))
+
+	if isStandardFT8Message(strings.Fields(normalized)) ||
+		isFT8FreeText(normalized) {
+		return nil
+	}
+
+	return ctxerrors.Wrapf(
+		commonerrors.ErrInvalidValue,
+		"FT8 message must be a standard message or free text of at most %d "+
+			"characters (%s), got: %s",
+		ft8FreeTextMaxLength, ft8FreeTextCharset, message,
+	)
+}
+
+// isStandardFT8Message checks for "CQ ..." and "CALL CALL ..." forms.
+func isStandardFT8Message(tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+
+	if tokens[0] == ft8TokenCQ {
+		return isFT8CQMessage(tokens[1:])
+	}
+
+	if len(tokens) < 2 ||
+		!isFT8Callsign(tokens[0]) ||
+		!isFT8Callsign(tokens[1]) {
+		return false
+	}
+
+	return isFT8Exchange(tokens[2:])
+}
+
+// isFT8CQMessage checks the tokens following CQ: [modifier] CALL [GRID].
+func isFT8CQMessage(tokens []string) bool {
+	// Directed CQ (CQ DX, CQ NA, CQ POTA, CQ 145...)
+	if len(tokens) > 0 &&
+		!isFT8Callsign(tokens[0]) &&
+		ft8CQModifierRegex.MatchString(tokens[0]) {
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) == 0 || !isFT8Callsign(tokens[0]) {
+		return false
+	}
+
+	switch len(tokens) {
+	case 1:
+		return true
+	case 2:
+		return ft8GridRegex.MatchString(tokens[1])
+	default:
+		return false
+	}
+}
+
+// isFT8Exchange checks the tokens following the two callsigns: nothing,
+// a grid, a report, RRR/RR73/73, "R GRID" or a contest RST exchange.
+func isFT8Exchange(tokens []string) bool {
+	switch len(tokens) {
+	case 0:
+		return true
+	case 1:
+		token := tokens[0]
+
+		return token == ft8TokenRRR ||
+			token == ft8TokenRR73 ||
+			token == ft8Token73 ||
+			ft8GridRegex.MatchString(token) ||
+			ft8ReportRegex.MatchString(token)
+	case 2:
+		if tokens[0] == ft8TokenR {
+			return ft8GridRegex.MatchString(tokens[1])
+		}
+
+		return isFT8RSTExchange(tokens)
+	case 3:
+		return tokens[0] == ft8TokenR && isFT8RSTExchange(tokens[1:])
+	default:
+		return false
+	}
+}
+
+// isFT8RSTExchange checks a contest exchange like "599 CA".
+func isFT8RSTExchange(tokens []string) bool {
+	return ft8RSTRegex.MatchString(tokens[0]) &&
+		ft8ExchangeRegex.MatchString(tokens[1])
+}
+
+// isFT8Callsign checks if a token looks like an FT8-encodable callsign.
+func isFT8Callsign(token string) bool {
+	return ft8CallsignRegex.MatchString(token)
+}
+
+// isFT8FreeText checks if a message fits the FT8 free-text limits.
+func isFT8FreeText(message string) bool {
+	return len(message) <= ft8FreeTextMaxLength &&
+		ft8FreeTextRegex.MatchString(message)
+}
+
 // validatePPM validates the PPM parameter.
 func (m *FT8) validatePPM() error {
 	// PPM can be any float value (positive, negative, or zero)
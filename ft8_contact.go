@@ -0,0 +1,199 @@
+package gorpitx
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// ft8AllTxtFields is the number of fields in a WSJT-X ALL.TXT line
+	// before the decoded message: time, dial MHz, Rx/Tx, mode, SNR, DT and
+	// audio offset.
+	ft8AllTxtFields = 7
+
+	ft8GridLength = 4
+
+	adifFieldCall = "call"
+	adifFieldGrid = "gridsquare"
+)
+
+// adifFieldRegex matches an ADIF field like <CALL:5>K0HAM, the optional
+// data type after the length included.
+//
+//nolint:gochecknoglobals
+var adifFieldRegex = regexp.MustCompile(`<([A-Za-z_]+):(\d+)(?::[A-Za-z])?>`)
+
+// FT8Contact is the station worked in a log entry.
+type FT8Contact struct {
+	Call string `json:"call"`
+	Grid string `json:"grid,omitempty"`
+}
+
+// FT8MessageFromContacts returns the message calling theirCall from myCall
+// with myGrid, e.g. "K0HAM W1AW FN31", the usual answer to a CQ. Both
+// callsigns must be FT8-encodable and myGrid a 4-character locator.
+func FT8MessageFromContacts(myCall, theirCall, myGrid string) (string, error) {
+	myCall = strings.ToUpper(strings.TrimSpace(myCall))
+	theirCall = strings.ToUpper(strings.TrimSpace(theirCall))
+	myGrid = strings.ToUpper(strings.TrimSpace(myGrid))
+
+	for _, call := range []string{myCall, theirCall} {
+		if !isFT8Callsign(call) {
+			return "", ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"invalid FT8 callsign: %q",
+				call,
+			)
+		}
+	}
+
+	if !ft8GridRegex.MatchString(myGrid) {
+		return "", ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"grid must be a 4-character locator like FN31, got: %q",
+			myGrid,
+		)
+	}
+
+	return theirCall + " " + myCall + " " + myGrid, nil
+}
+
+// ParseFT8Contact reads the station from a log line, either an ADIF record
+// with CALL and optionally GRIDSQUARE fields or a WSJT-X ALL.TXT line like
+//
+//	231016_123015    14.074 Rx FT8    -12  0.3 1234 CQ K0HAM EM48
+//
+// where the station is the sender of the decoded message. Grids are
+// shortened to 4 characters.
+func ParseFT8Contact(line string) (FT8Contact, error) {
+	var (
+		contact FT8Contact
+		err     error
+	)
+
+	if strings.Contains(line, "<") {
+		contact, err = parseADIFContact(line)
+	} else {
+		contact, err = parseAllTxtContact(line)
+	}
+
+	if err != nil {
+		return FT8Contact{}, err
+	}
+
+	contact.Call = strings.ToUpper(contact.Call)
+	if !isFT8Callsign(contact.Call) {
+		return FT8Contact{}, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid FT8 callsign: %q",
+			contact.Call,
+		)
+	}
+
+	if contact.Grid == "" {
+		return contact, nil
+	}
+
+	contact.Grid = strings.ToUpper(contact.Grid)
+	if len(contact.Grid) > ft8GridLength {
+		contact.Grid = contact.Grid[:ft8GridLength]
+	}
+
+	if !ft8GridRegex.MatchString(contact.Grid) {
+		return FT8Contact{}, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid grid: %q",
+			contact.Grid,
+		)
+	}
+
+	return contact, nil
+}
+
+// parseADIFContact reads the CALL and GRIDSQUARE fields of an ADIF record.
+func parseADIFContact(record string) (FT8Contact, error) {
+	var contact FT8Contact
+
+	for _, match := range adifFieldRegex.FindAllStringSubmatchIndex(record, -1) {
+		name := strings.ToLower(record[match[2]:match[3]])
+
+		// The regex only matches digits, so Atoi can't fail
+		length, _ := strconv.Atoi(record[match[4]:match[5]])
+
+		start := match[1]
+		if start+length > len(record) {
+			return FT8Contact{}, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"ADIF field %s is longer than the record",
+				name,
+			)
+		}
+
+		value := strings.TrimSpace(record[start : start+length])
+
+		switch name {
+		case adifFieldCall:
+			contact.Call = value
+		case adifFieldGrid:
+			contact.Grid = value
+		}
+	}
+
+	if contact.Call == "" {
+		return FT8Contact{}, ctxerrors.Wrap(
+			commonerrors.ErrRequiredFieldNotSet,
+			"ADIF record has no CALL field",
+		)
+	}
+
+	return contact, nil
+}
+
+// parseAllTxtContact reads the sender of the decoded message in a WSJT-X
+// ALL.TXT line: the callsign after CQ and its modifier, or the second
+// callsign of a directed message, with the grid when one follows.
+func parseAllTxtContact(line string) (FT8Contact, error) {
+	fields := strings.Fields(strings.ToUpper(line))
+	if len(fields) <= ft8AllTxtFields {
+		return FT8Contact{}, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"not a WSJT-X ALL.TXT line: %q",
+			line,
+		)
+	}
+
+	tokens := fields[ft8AllTxtFields:]
+
+	var rest []string
+
+	switch {
+	case tokens[0] == ft8TokenCQ && len(tokens) > 1:
+		rest = tokens[1:]
+
+		// Directed CQ (CQ DX, CQ POTA...)
+		if len(rest) > 1 && !isFT8Callsign(rest[0]) {
+			rest = rest[1:]
+		}
+	case len(tokens) > 1:
+		rest = tokens[1:]
+	default:
+		return FT8Contact{}, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"no sender in message: %q",
+			strings.Join(tokens, " "),
+		)
+	}
+
+	// RR73 also reads as a grid but is a sign-off
+	contact := FT8Contact{Call: rest[0]}
+	if len(rest) > 1 && rest[1] != ft8TokenRR73 &&
+		ft8GridRegex.MatchString(rest[1]) {
+		contact.Grid = rest[1]
+	}
+
+	return contact, nil
+}
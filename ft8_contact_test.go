@@ -0,0 +1,164 @@
+package gorpitx
+
+import (
+	"testing"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFT8MessageFromContacts(t *testing.T) {
+	tests := []struct {
+		name        string
+		myCall      string
+		theirCall   string
+		myGrid      string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:      "answer to a CQ",
+			myCall:    "W1AW",
+			theirCall: "K0HAM",
+			myGrid:    "FN31",
+			expected:  "K0HAM W1AW FN31",
+		},
+		{
+			name:      "normalized",
+			myCall:    " w1aw ",
+			theirCall: "ea8/k0ham",
+			myGrid:    "fn31",
+			expected:  "EA8/K0HAM W1AW FN31",
+		},
+		{
+			name:        "invalid own callsign",
+			myCall:      "W1AW!",
+			theirCall:   "K0HAM",
+			myGrid:      "FN31",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "missing their callsign",
+			myCall:      "W1AW",
+			myGrid:      "FN31",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "6-character grid",
+			myCall:      "W1AW",
+			theirCall:   "K0HAM",
+			myGrid:      "FN31PR",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "grid out of range",
+			myCall:      "W1AW",
+			theirCall:   "K0HAM",
+			myGrid:      "ZZ31",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := FT8MessageFromContacts(
+				tt.myCall, tt.theirCall, tt.myGrid,
+			)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, message)
+			require.NoError(t, validateFT8Grammar(message))
+		})
+	}
+}
+
+func TestParseFT8Contact(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		expected    FT8Contact
+		expectedErr error
+	}{
+		{
+			name:     "all.txt cq",
+			line:     "231016_123015    14.074 Rx FT8    -12  0.3 1234 CQ K0HAM EM48",
+			expected: FT8Contact{Call: "K0HAM", Grid: "EM48"},
+		},
+		{
+			name:     "all.txt directed cq",
+			line:     "231016_123015    14.074 Rx FT8    -3  0.1 800 CQ DX K0HAM EM48",
+			expected: FT8Contact{Call: "K0HAM", Grid: "EM48"},
+		},
+		{
+			name:     "all.txt reply",
+			line:     "231016_123030    14.074 Rx FT8    -8  0.2 900 W1AW K0HAM -10",
+			expected: FT8Contact{Call: "K0HAM"},
+		},
+		{
+			name:     "all.txt sign-off is not a grid",
+			line:     "231016_123100    14.074 Rx FT8    -8  0.2 900 W1AW K0HAM RR73",
+			expected: FT8Contact{Call: "K0HAM"},
+		},
+		{
+			name:        "all.txt without message",
+			line:        "231016_123015    14.074 Rx FT8    -12  0.3 1234",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "all.txt single token",
+			line:        "231016_123015    14.074 Rx FT8    -12  0.3 1234 CQ",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "adif record",
+			line: "<CALL:5>k0ham <GRIDSQUARE:6>em48ab <MODE:3>FT8 " +
+				"<QSO_DATE:8:D>20231016 <EOR>",
+			expected: FT8Contact{Call: "K0HAM", Grid: "EM48"},
+		},
+		{
+			name:     "adif record without grid",
+			line:     "<call:4>W1AW<mode:3>FT8<eor>",
+			expected: FT8Contact{Call: "W1AW"},
+		},
+		{
+			name:        "adif record without call",
+			line:        "<GRIDSQUARE:4>EM48 <EOR>",
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name:        "adif field longer than record",
+			line:        "<CALL:20>K0HAM",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "adif invalid grid",
+			line:        "<CALL:5>K0HAM <GRIDSQUARE:4>1234 <EOR>",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "adif invalid callsign",
+			line:        "<CALL:5>HELLO <EOR>",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contact, err := ParseFT8Contact(tt.line)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, contact)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package gorpitx
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -209,6 +213,90 @@ func TestFT8_ParseArgs(t *testing.T) {
 	}
 }
 
+func TestFT8_ParseArgs_Band(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         map[string]any
+		expectError   bool
+		expectedFreq  float64
+		expectArgs    []string
+		errorContains string
+	}{
+		{
+			name: "band resolves frequency",
+			input: map[string]any{
+				"band":    "20m",
+				"message": "CQ W1AW FN31",
+			},
+			expectedFreq: 14074000,
+			expectArgs:   []string{"-f", "14074000", "-m", "CQ W1AW FN31"},
+		},
+		{
+			name: "band is case insensitive",
+			input: map[string]any{
+				"band":    "40M",
+				"message": "CQ W1AW FN31",
+			},
+			expectedFreq: 7074000,
+			expectArgs:   []string{"-f", "7074000", "-m", "CQ W1AW FN31"},
+		},
+		{
+			name: "explicit frequency wins over band",
+			input: map[string]any{
+				"band":      "20m",
+				"frequency": 14080000.0,
+				"message":   "CQ W1AW FN31",
+			},
+			expectedFreq: 14080000,
+			expectArgs:   []string{"-f", "14080000", "-m", "CQ W1AW FN31"},
+		},
+		{
+			name: "unknown band",
+			input: map[string]any{
+				"band":    "11m",
+				"message": "CQ W1AW FN31",
+			},
+			expectError:   true,
+			errorContains: "unknown FT8 band",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft8 := &FT8{}
+			inputBytes, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+
+			args, _, err := ft8.ParseArgs(inputBytes)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+				assert.Contains(t, err.Error(), tt.errorContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expectedFreq, ft8.Frequency, 0)
+			assert.Equal(t, tt.expectArgs, args)
+		})
+	}
+}
+
+func TestFT8Bands(t *testing.T) {
+	bands := FT8Bands()
+
+	assert.Len(t, bands, len(ft8BandFrequencies))
+	assert.Equal(t, "160m", bands[0])
+	assert.Equal(t, "2m", bands[len(bands)-1])
+
+	for _, band := range bands {
+		freq := ft8BandFrequencies[band]
+		assert.True(t, isValidFreqHz(freq), "band %s out of range", band)
+	}
+}
+
 func TestFT8_BuildArgs(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -397,15 +485,17 @@ func TestFT8_ValidateMessage(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name:        "very long message allowed",
+			name:        "very long non-standard message rejected",
 			message:     "W8ABC DX EM79 CALLING CQ FROM GRID SQUARE",
-			expectError: false,
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
 		},
 		{
-			name: "any length message allowed",
+			name: "long free text message rejected",
 			message: "VK2ABC G0XYZ 73 THIS IS A VERY LONG MESSAGE FOR TESTING " +
 				"PURPOSES",
-			expectError: false,
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
 		},
 	}
 
@@ -427,6 +517,76 @@ func TestFT8_ValidateMessage(t *testing.T) {
 	}
 }
 
+func TestValidateFT8Grammar(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		expectError bool
+	}{
+		{name: "CQ with grid", message: "CQ W1AW FN31"},
+		{name: "CQ without grid", message: "CQ K0HAM"},
+		{name: "directed CQ", message: "CQ DX K0HAM EM69"},
+		{name: "CQ POTA", message: "CQ POTA W1AW FN31"},
+		{name: "CQ with frequency", message: "CQ 145 W1AW FN31"},
+		{name: "compound callsign", message: "CQ W1AW/P FN31"},
+		{name: "prefixed callsign", message: "CQ VE3/K0HAM"},
+		{name: "hashed callsign", message: "<PJ4/K1ABC> W9XYZ"},
+		{name: "reply with grid", message: "W1AW K0HAM EM69"},
+		{name: "reply with R and grid", message: "W1AW K0HAM R EM69"},
+		{name: "signal report", message: "K0HAM W1AW -15"},
+		{name: "positive report", message: "K0HAM W1AW +03"},
+		{name: "roger report", message: "W1AW K0HAM R-08"},
+		{name: "RRR", message: "K0HAM W1AW RRR"},
+		{name: "RR73", message: "K0HAM W1AW RR73"},
+		{name: "73", message: "W1AW K0HAM 73"},
+		{name: "contest exchange", message: "W1AW K0HAM 599 CA"},
+		{name: "roger contest exchange", message: "W1AW K0HAM R 599 CA"},
+		{name: "lowercase", message: "cq w1aw fn31"},
+		{name: "free text", message: "TNX BOB 73 GL"},
+		{name: "free text with symbols", message: "HELLO+-./?"},
+		{
+			name:        "free text too long",
+			message:     "THIS IS WAY TOO LONG",
+			expectError: true,
+		},
+		{
+			name:        "free text with invalid characters",
+			message:     "HELLO!",
+			expectError: true,
+		},
+		{
+			name:        "CQ with invalid grid",
+			message:     "CQ W1AW ZZ99 EXTRA",
+			expectError: true,
+		},
+		{
+			name:        "CQ without callsign",
+			message:     "CQ DX NA EU OC AF",
+			expectError: true,
+		},
+		{
+			name:        "report out of format",
+			message:     "K0HAM W1AW REPORT -150",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFT8Grammar(tt.message)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestFT8_ValidatePPM(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -746,3 +906,244 @@ func TestFT8_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestFT8SlotDelay(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		slot     int
+		expected time.Duration
+	}{
+		{
+			name:     "any slot on boundary",
+			now:      base,
+			slot:     2,
+			expected: 0,
+		},
+		{
+			name:     "any slot mid-period",
+			now:      base.Add(4 * time.Second),
+			slot:     2,
+			expected: 11 * time.Second,
+		},
+		{
+			name:     "any slot just after odd boundary",
+			now:      base.Add(16 * time.Second),
+			slot:     -1,
+			expected: 14 * time.Second,
+		},
+		{
+			name:     "even slot from odd period",
+			now:      base.Add(20 * time.Second),
+			slot:     0,
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "even slot from even period",
+			now:      base.Add(5 * time.Second),
+			slot:     0,
+			expected: 25 * time.Second,
+		},
+		{
+			name:     "odd slot from even period",
+			now:      base.Add(10 * time.Second),
+			slot:     1,
+			expected: 5 * time.Second,
+		},
+		{
+			name:     "odd slot just after odd boundary",
+			now:      base.Add(46 * time.Second),
+			slot:     1,
+			expected: 29 * time.Second,
+		},
+		{
+			name:     "sub-second precision",
+			now:      base.Add(14*time.Second + 500*time.Millisecond),
+			slot:     2,
+			expected: 500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ft8SlotDelay(tt.now, tt.slot))
+		})
+	}
+}
+
+func TestFT8_StartDelay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 20, 0, time.UTC)
+
+	ft8 := &FT8{}
+	assert.Zero(t, ft8.startDelay(now), "no delay without autoSlot")
+
+	ft8.AutoSlot = boolPtr(false)
+	assert.Zero(t, ft8.startDelay(now), "no delay with autoSlot disabled")
+
+	ft8.AutoSlot = boolPtr(true)
+	assert.Equal(t, 10*time.Second, ft8.startDelay(now))
+
+	ft8.Slot = intPtr(1)
+	assert.Equal(t, 25*time.Second, ft8.startDelay(now))
+}
+
+func TestRPITX_Exec_FT8AutoSlotCancelled(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameFT8: &FT8{},
+		},
+		commander: mockCommander,
+	}
+
+	args := []byte(`{
+		"frequency": 14074000,
+		"message": "CQ W1AW FN31",
+		"slot": 0,
+		"autoSlot": true
+	}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := rpitx.Exec(ctx, ModuleNameFT8, args, time.Second)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The process must never have been started
+	assert.Empty(t, mockCommander.CallOrder())
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestFT8_ParseArgs_Messages(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         string
+		expectArgs   []string
+		expectValues []string
+		expectedErr  error
+	}{
+		{
+			name: "qso sequence",
+			args: `{"frequency": 14074000, "slot": 1, "autoSlot": true, ` +
+				`"messages": ["CQ W1AW FN31", "K0HAM W1AW -10", ` +
+				`"K0HAM W1AW RR73"]}`,
+			expectArgs: []string{"-f", "14074000", "-s", "1", "-m"},
+			expectValues: []string{
+				"CQ W1AW FN31", "K0HAM W1AW -10", "K0HAM W1AW RR73",
+			},
+		},
+		{
+			name:        "without autoSlot",
+			args:        `{"frequency": 14074000, "messages": ["CQ W1AW FN31"]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "with message",
+			args: `{"frequency": 14074000, "autoSlot": true, ` +
+				`"message": "CQ W1AW FN31", "messages": ["CQ W1AW FN31"]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "with repeat",
+			args: `{"frequency": 14074000, "autoSlot": true, "repeat": true, ` +
+				`"messages": ["CQ W1AW FN31"]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "empty message in sequence",
+			args: `{"frequency": 14074000, "autoSlot": true, ` +
+				`"messages": ["CQ W1AW FN31", " "]}`,
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "invalid message in sequence",
+			args: `{"frequency": 14074000, "autoSlot": true, ` +
+				`"messages": ["CQ W1AW FN31", "THIS IS WAY TOO LONG"]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft8 := &FT8{}
+
+			args, _, err := ft8.ParseArgs(json.RawMessage(tt.args))
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectArgs, args)
+
+			values, ok := ft8.argSequence()
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectValues, values)
+		})
+	}
+}
+
+func TestFT8_ArgSequence_SingleMessage(t *testing.T) {
+	ft8 := &FT8{}
+
+	_, _, err := ft8.ParseArgs(json.RawMessage(
+		`{"frequency": 14074000, "message": "CQ W1AW FN31"}`,
+	))
+	require.NoError(t, err)
+
+	_, ok := ft8.argSequence()
+	assert.False(t, ok)
+}
+
+func TestFT8_EstimatedDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		ft8        FT8
+		expected   time.Duration
+		expectedOK bool
+	}{
+		{
+			name:       "single message",
+			ft8:        FT8{Message: "CQ W1AW FN31"},
+			expected:   ft8TxDuration,
+			expectedOK: true,
+		},
+		{
+			name:     "repeat",
+			ft8:      FT8{Message: "CQ W1AW FN31", Repeat: boolPtr(true)},
+			expected: 0,
+		},
+		{
+			name: "sequence on even slots",
+			ft8: FT8{
+				Messages: []string{"CQ W1AW FN31", "K1ABC W1AW -10", "RR73"},
+				Slot:     intPtr(0),
+			},
+			expected:   2*30*time.Second + ft8TxDuration,
+			expectedOK: true,
+		},
+		{
+			name: "sequence on any slot",
+			ft8: FT8{
+				Messages: []string{"CQ W1AW FN31", "K1ABC W1AW -10"},
+				Slot:     intPtr(2),
+			},
+			expected:   15*time.Second + ft8TxDuration,
+			expectedOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := tt.ft8.estimatedDuration()
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
@@ -8,68 +8,181 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/psyb0t/commander"
-	"github.com/psyb0t/common-go/env"
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
-	"github.com/sirupsen/logrus"
 )
 
+// NoTimeout is the Exec timeout that runs the module until it exits on its
+// own or is stopped, e.g. for an indefinite beacon.
+const NoTimeout time.Duration = 0
+
 const (
-	minFreqKHz            = 5
-	maxFreqKHz            = 1500000
-	gracefulStopTimeout   = 3 * time.Second
-	streamingPollInterval = 10 * time.Millisecond
+	minFreqKHz          = 5
+	maxFreqKHz          = 1500000
+	gracefulStopTimeout = 3 * time.Second
 )
 
+// Module is the contract every transmitter module implements. ParseArgs
+// receives the raw JSON args passed to Exec, validates them and returns the
+// command-line arguments for the binary named after the module (looked up in
+// Config.Path) along with an optional reader that is fed to its stdin.
+// The registered instance is a template: every execution, validation and
+// preview parses into a fresh copy of it, so ParseArgs starts from the
+// registered state and never sees what a previous call left.
 type Module interface {
 	ParseArgs(json.RawMessage) ([]string, io.Reader, error)
 }
 
+// The built-in modules and the optional behaviors they rely on, checked at
+// compile time so a signature drifting from its interface doesn't silently
+// turn the behavior off.
+var (
+	_ Module = (*PIFMRDS)(nil)
+	_ Module = (*TUNE)(nil)
+	_ Module = (*MORSE)(nil)
+	_ Module = (*SPECTRUMPAINT)(nil)
+	_ Module = (*PICHIRP)(nil)
+	_ Module = (*POCSAG)(nil)
+	_ Module = (*FT8)(nil)
+	_ Module = (*PISSTV)(nil)
+	_ Module = (*PIRTTY)(nil)
+	_ Module = (*FSK)(nil)
+	_ Module = (*AudioSockBroadcast)(nil)
+	_ Module = (*SENDIQ)(nil)
+	_ Module = (*PIDTMF)(nil)
+	_ Module = (*SSB)(nil)
+
+	_ inputPreparer     = (*PIFMRDS)(nil)
+	_ freqStepLimited   = (*PIFMRDS)(nil)
+	_ startDelayer      = (*FT8)(nil)
+	_ argSequencer      = (*FT8)(nil)
+	_ durationEstimator = (*FT8)(nil)
+	_ durationEstimator = (*PISSTV)(nil)
+	_ durationEstimator = (*MORSE)(nil)
+	_ timeoutDefaulter  = (*FT8)(nil)
+	_ configReceiver    = (*AudioSockBroadcast)(nil)
+	_ repeatSpacer      = (*POCSAG)(nil)
+	_ stdinModule       = (*POCSAG)(nil)
+	_ stdinModule       = (*FSK)(nil)
+	_ readerInput       = (*FSK)(nil)
+
+	_ frequencyReporter = (*PIFMRDS)(nil)
+	_ frequencyReporter = (*TUNE)(nil)
+	_ frequencyReporter = (*MORSE)(nil)
+	_ frequencyReporter = (*SPECTRUMPAINT)(nil)
+	_ frequencyReporter = (*PICHIRP)(nil)
+	_ frequencyReporter = (*POCSAG)(nil)
+	_ frequencyReporter = (*FT8)(nil)
+	_ frequencyReporter = (*PISSTV)(nil)
+	_ frequencyReporter = (*PIRTTY)(nil)
+	_ frequencyReporter = (*FSK)(nil)
+	_ frequencyReporter = (*AudioSockBroadcast)(nil)
+	_ frequencyReporter = (*SENDIQ)(nil)
+	_ frequencyReporter = (*PIDTMF)(nil)
+	_ frequencyReporter = (*SSB)(nil)
+)
+
 type ModuleName = string
 
+// startDelayer is implemented by modules that must wait before their process
+// is started, e.g. FT8 aligning to a UTC time slot.
+type startDelayer interface {
+	startDelay(now time.Time) time.Duration
+}
+
+// inputPreparer is implemented by modules that convert their input before
+// the process is started, e.g. PIFMRDS resampling its audio, or that feed it
+// while it runs, like the PIFMRDS PS rotation. It returns the command args
+// to run with and a cleanup removing the converted input.
+type inputPreparer interface {
+	prepareInput(
+		ctx context.Context,
+		cmd commander.Commander,
+		args []string,
+	) ([]string, func(), error)
+}
+
+// configReceiver is implemented by modules whose defaults can be set in
+// the Config, e.g. the AudioSockBroadcast sample rate. It is called on the
+// fresh instance before every ParseArgs.
+type configReceiver interface {
+	applyConfig(config Config)
+}
+
+// freqStepLimited is implemented by modules whose binary only tunes in
+// 0.1 MHz steps, like pifmrds, so finer frequencies are rejected rather than
+// silently rounded. Modules taking exact Hz, e.g. FT8 or POCSAG, accept any
+// frequency within range.
+type freqStepLimited interface {
+	limitsFreqStep() bool
+}
+
+// repeatSpacer is implemented by modules that can run their repeats as
+// separate invocations of the binary, so the carrier drops in between. It
+// returns the number of runs and the gap between them, ok being false when
+// the binary should repeat on its own.
+type repeatSpacer interface {
+	repeatSpacing() (count int, gapMs int, ok bool)
+}
+
+// argSequencer is implemented by modules that can run their binary once per
+// value, e.g. FT8 sending a message sequence. Each value is appended to the
+// binary args of its run, ok being false when the binary runs once.
+type argSequencer interface {
+	argSequence() (values []string, ok bool)
+}
+
 type RPITX struct {
-	config      Config
-	commander   commander.Commander
-	modules     map[ModuleName]Module
-	isExecuting atomic.Bool
-	process     commander.Process
-	processMu   sync.RWMutex
+	config              Config
+	commander           commander.Commander
+	modules             map[ModuleName]Module
+	modulesMu           sync.RWMutex
+	isExecuting         atomic.Bool
+	isPaused            atomic.Bool
+	hooks               atomic.Pointer[Hooks]
+	process             commander.Process
+	processMu           sync.RWMutex
+	currentModule       ModuleName
+	startedAt           time.Time
+	logger              Logger
+	hamBandRegion       Region
+	frequencyPolicy     *FrequencyPolicy
+	harmonicWarnings    bool
+	binaryCheck         bool
+	skipRootCheck       bool
+	dryRun              bool
+	lastOutputLines     int
+	streamBuffer        int
+	pendingStreams      []hubStream
+	lastOutput          atomic.Pointer[stderrTail]
+	autoRestartRetries  int
+	autoRestartBackoff  time.Duration
+	maxTransmitDuration time.Duration
+	transmitCooldown    time.Duration
+	transmitEndedAt     atomic.Int64
+	stopCh              chan struct{}
+	metricsRecorder     MetricsRecorder
+	execution           *Execution
+	inputCleanup        func()
+	transmissions       transmissionLog
+	queue               jobQueue
+	optionErr           error
 }
 
-func newRPITX() *RPITX {
-	config, err := parseConfig()
+func newRPITX(opts ...Option) *RPITX {
+	r, err := New(opts...)
 	if err != nil {
 		panic(err)
 	}
 
-	// Check if running as root in production
-	if !env.IsDev() && os.Geteuid() != 0 {
-		panic("PIrateRF must be run as root in production mode")
-	}
-
-	return &RPITX{
-		config:    config,
-		commander: commander.New(),
-		modules: map[ModuleName]Module{
-			ModuleNamePIFMRDS:            &PIFMRDS{},
-			ModuleNameTUNE:               &TUNE{},
-			ModuleNameMORSE:              &MORSE{},
-			ModuleNameSPECTRUMPAINT:      &SPECTRUMPAINT{},
-			ModuleNamePICHIRP:            &PICHIRP{},
-			ModuleNamePOCSAG:             &POCSAG{},
-			ModuleNameFT8:                &FT8{},
-			ModuleNamePISSSTV:            &PISSTV{},
-			ModuleNamePIRTTY:             &PIRTTY{},
-			ModuleNameFSK:                &FSK{},
-			ModuleNameAudioSockBroadcast: &AudioSockBroadcast{},
-		},
-	}
+	return r
 }
 
 var (
@@ -77,103 +190,207 @@ var (
 	once     sync.Once //nolint:gochecknoglobals
 )
 
-func GetInstance() *RPITX {
+// GetInstance returns the process-wide RPITX, creating it with New and the
+// given options on the first call. Later calls ignore the options. It
+// panics with the error from New, e.g. one matching ErrRootRequired; call
+// New to get the error returned instead.
+func GetInstance(opts ...Option) *RPITX {
 	once.Do(func() {
-		instance = newRPITX()
+		instance = newRPITX(opts...)
 	})
 
 	return instance
 }
 
+// GetSupportedModules returns the names of the registered modules sorted
+// alphabetically.
 func (r *RPITX) GetSupportedModules() []ModuleName {
+	r.modulesMu.RLock()
+	defer r.modulesMu.RUnlock()
+
 	modules := make([]ModuleName, 0, len(r.modules))
 	for name := range r.modules {
 		modules = append(modules, name)
 	}
 
+	slices.Sort(modules)
+
 	return modules
 }
 
 func (r *RPITX) IsSupportedModule(name ModuleName) bool {
-	_, exists := r.modules[name]
+	_, exists := r.module(name)
 
 	return exists
 }
 
+// Exec runs the module and blocks until it finishes. A timeout greater than
+// zero stops the process once it elapses and returns
+// commonerrors.ErrTimeout. NoTimeout (zero) sets no deadline at all, so the
+// module runs until it exits on its own or Stop is called. Negative
+// timeouts are rejected with commonerrors.ErrInvalidValue. ExecOptions set
+// the environment, working directory and reader input of the process.
 func (r *RPITX) Exec(
 	ctx context.Context,
 	name ModuleName,
 	args []byte,
 	timeout time.Duration,
+	opts ...ExecOption,
 ) error {
-	if !r.isExecuting.CompareAndSwap(false, true) {
-		return ErrExecuting
-	}
-
-	defer r.cleanupExecution(ctx)
-
-	logrus.Debugf("executing module %s with args %s", name, args)
-	defer logrus.Debugf("finished executing module %s", name)
-
-	cmdName, cmdArgs, stdin, err := r.prepareCommand(name, args)
+	execution, err := r.ExecAsync(ctx, name, args, timeout, opts...)
 	if err != nil {
 		return err
 	}
 
-	if err := r.startProcess(ctx, name, cmdName, cmdArgs, stdin); err != nil {
-		return err
-	}
-
-	// Handle timeout manually if specified
-	if timeout > 0 {
-		return r.waitWithTimeout(ctx, timeout)
-	}
+	return execution.Wait()
+}
 
-	if err := r.process.Wait(); err != nil {
-		return ctxerrors.Wrap(err, "failed to wait for process")
+// ExecYAML runs the module like Exec with its args given as YAML, e.g. from
+// a config file. The YAML is converted to the module's JSON args, so the
+// field names and validation are the same. Invalid YAML is rejected with
+// commonerrors.ErrInvalidValue.
+func (r *RPITX) ExecYAML(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	timeout time.Duration,
+	opts ...ExecOption,
+) error {
+	jsonArgs, err := yamlToJSON(args)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "could not parse %s args", name)
 	}
 
-	return nil
+	return r.Exec(ctx, name, jsonArgs, timeout, opts...)
 }
 
+// cleanupExecution releases the process and frees the execution slot.
 func (r *RPITX) cleanupExecution(ctx context.Context) {
+	r.releaseProcess(ctx)
+	r.isExecuting.Store(false)
+}
+
+// releaseProcess kills the process of the execution if it is still around
+// and clears what it left behind, without freeing the execution slot.
+func (r *RPITX) releaseProcess(ctx context.Context) {
 	r.processMu.Lock()
 
 	if r.process != nil {
 		// fkin kill the fuckin' process
 		if err := r.process.Kill(ctx); err != nil {
-			logrus.Errorf("failed to kill the fuckin' process: %v", err)
+			r.log().Errorf("failed to kill the fuckin' process: %v", err)
 		}
+
+		r.markTransmitEnded()
+	}
+
+	if r.inputCleanup != nil {
+		r.inputCleanup()
+		r.inputCleanup = nil
 	}
 
 	r.process = nil
+	r.currentModule = ""
+	r.startedAt = time.Time{}
+	r.isPaused.Store(false)
 	r.processMu.Unlock()
+}
 
-	r.isExecuting.Store(false)
+// busyError returns ErrExecuting wrapped with the running module and its
+// start time, so callers can tell what blocked them.
+func (r *RPITX) busyError() error {
+	r.processMu.RLock()
+	module := r.currentModule
+	startedAt := r.startedAt
+	r.processMu.RUnlock()
+
+	// The execution slot is taken but nothing has been started yet
+	if module == "" {
+		return ErrExecuting
+	}
+
+	return ctxerrors.Wrapf(
+		ErrExecuting,
+		"%s running since %s",
+		module,
+		startedAt.Format(time.RFC3339),
+	)
 }
 
+// checkTimeout rejects negative execution timeouts, which would otherwise
+// be indistinguishable from NoTimeout.
+func checkTimeout(timeout time.Duration) error {
+	if timeout < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"timeout must not be negative, got %s, use NoTimeout to run "+
+				"until stopped",
+			timeout,
+		)
+	}
+
+	return nil
+}
+
+// parseModuleArgs normalizes frequency strings in the args and has the
+// module parse them, with its defaults from the config.
+func (r *RPITX) parseModuleArgs(
+	module Module,
+	args json.RawMessage,
+) ([]string, io.Reader, error) {
+	args, err := normalizeFreqArgs(module, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if receiver, ok := module.(configReceiver); ok {
+		receiver.applyConfig(r.config)
+	}
+
+	return module.ParseArgs(args)
+}
+
+// prepareCommand parses the args into a fresh instance of the module and
+// returns the command running it.
 func (r *RPITX) prepareCommand(
 	name ModuleName,
 	args []byte,
 ) (string, []string, io.Reader, error) {
-	if !r.IsSupportedModule(name) {
-		return "", nil, nil, ctxerrors.Wrap(ErrUnknownModule, name)
+	module, err := r.newModule(name)
+	if err != nil {
+		return "", nil, nil, err
 	}
 
-	module := r.modules[name]
+	return r.prepareModuleCommand(name, module, args)
+}
 
-	parsedArgs, stdin, err := module.ParseArgs(args)
+// prepareModuleCommand parses the args into the module instance and returns
+// the command running it. The instance then holds the args of the command,
+// e.g. for prepareInput.
+func (r *RPITX) prepareModuleCommand(
+	name ModuleName,
+	module Module,
+	args []byte,
+) (string, []string, io.Reader, error) {
+	parsedArgs, stdin, err := r.parseModuleArgs(module, args)
 	if err != nil {
 		return "", nil, nil, ctxerrors.Wrap(err, "failed to parse args")
 	}
 
+	if err := r.checkFrequencyPolicy(module); err != nil {
+		return "", nil, nil, err
+	}
+
+	r.warnOutsideHamBand(name, module)
+	r.warnHarmonics(name, module)
+
 	var (
 		cmdName string
 		cmdArgs []string
 	)
 
-	if env.IsDev() {
-		cmdName, cmdArgs = r.getMockExecCmd(name, parsedArgs)
+	if r.mockMode() {
+		cmdName, cmdArgs = r.getMockExecCmd(name, parsedArgs, stdin != nil)
 
 		return cmdName, cmdArgs, stdin, nil
 	}
@@ -183,71 +400,224 @@ func (r *RPITX) prepareCommand(
 	cmdArgs = []string{"-oL"}
 
 	// Check if this is a script-based module
-	if IsScriptModule(name) {
+	if path, isScript := r.ModuleNameToScriptName(name); isScript {
 		// Ensure script exists on filesystem
-		if err := EnsureScriptExists(name); err != nil {
+		if err := r.EnsureScriptExists(name, false); err != nil {
 			return "", nil, nil, ctxerrors.Wrap(err, "failed to ensure script exists")
 		}
 
-		scriptPath, _ := ModuleNameToScriptName(name)
-		cmdArgs = append(cmdArgs, scriptPath)
+		cmdArgs = append(cmdArgs, path)
 		cmdArgs = append(cmdArgs, parsedArgs...)
 
-		logrus.Debugf("script command prepared: %s %v", cmdName, cmdArgs)
+		r.log().Debugf("script command prepared: %s %v", cmdName, cmdArgs)
 
 		return cmdName, cmdArgs, stdin, nil
 	}
 
-	binaryPath := filepath.Join(r.config.Path, name)
+	binaryPath, err := r.binaryPath(name)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	wrapperArgs, err := r.wrapperArgs(module)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	cmdArgs = append(cmdArgs, wrapperArgs...)
 	cmdArgs = append(cmdArgs, binaryPath)
 	cmdArgs = append(cmdArgs, parsedArgs...)
 
-	logrus.Debugf("production command prepared: %s %v", cmdName, cmdArgs)
+	r.log().Debugf("production command prepared: %s %v", cmdName, cmdArgs)
 
 	return cmdName, cmdArgs, stdin, nil
 }
 
-func (r *RPITX) startProcess(
+// wrapperArgs returns the helper script invocation the binary is run
+// through when the module spaces its repeats or runs a sequence, or nil.
+func (r *RPITX) wrapperArgs(module Module) ([]string, error) {
+	var (
+		name string
+		args []string
+	)
+
+	if spacer, ok := module.(repeatSpacer); ok {
+		if count, gapMs, spaced := spacer.repeatSpacing(); spaced {
+			name = repeatScriptName
+			args = []string{strconv.Itoa(count), strconv.Itoa(gapMs)}
+		}
+	}
+
+	if sequencer, ok := module.(argSequencer); ok {
+		if values, sequenced := sequencer.argSequence(); sequenced {
+			name = sequenceScriptName
+			args = append([]string{strconv.Itoa(len(values))}, values...)
+		}
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	path, written, err := ensureHelperScript(
+		r.scriptDir(), name, helperScripts[name],
+	)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to ensure %s exists", name)
+	}
+
+	if written {
+		r.log().Infof("wrote embedded script %s", path)
+	}
+
+	return append([]string{path}, args...), nil
+}
+
+// binaryPath returns the path of the module binary, taken from
+// Config.BinaryPaths when overridden or else from Config.Path. Overridden
+// paths must exist.
+func (r *RPITX) binaryPath(name ModuleName) (string, error) {
+	override, ok := r.config.BinaryPaths[name]
+	if !ok {
+		return filepath.Join(r.rpitxPath(), name), nil
+	}
+
+	path := expandPath(override)
+
+	if _, err := os.Stat(path); err != nil {
+		return "", ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"binary for module %s not found at %s: %v",
+			name, path, err,
+		)
+	}
+
+	return path, nil
+}
+
+// prepareInput has the module convert its input, if it needs to, and
+// returns the command args to run with and a cleanup for the converted
+// input. The dev mode mock doesn't read the input, so nothing is converted.
+func (r *RPITX) prepareInput(
 	ctx context.Context,
-	moduleName ModuleName,
-	cmdName string,
+	module Module,
 	cmdArgs []string,
-	stdin io.Reader,
+) ([]string, func(), error) {
+	preparer, ok := module.(inputPreparer)
+	if !ok || r.mockMode() {
+		return cmdArgs, func() {}, nil
+	}
+
+	cmdArgs, cleanup, err := preparer.prepareInput(ctx, r.commander, cmdArgs)
+	if err != nil {
+		return nil, nil, ctxerrors.Wrap(err, "failed to prepare input")
+	}
+
+	return cmdArgs, cleanup, nil
+}
+
+// waitForStart blocks until the module is ready to start transmitting or
+// the context is cancelled.
+func (r *RPITX) waitForStart(
+	ctx context.Context,
+	name ModuleName,
+	module Module,
 ) error {
-	r.processMu.Lock()
+	delayer, ok := module.(startDelayer)
+	if !ok {
+		return nil
+	}
+
+	delay := delayer.startDelay(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	r.log().Debugf("waiting %s before starting module %s", delay, name)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctxerrors.Wrap(ctx.Err(), "cancelled while waiting to start")
+	}
+}
 
+// commandOptions returns the commander options used to run the module.
+func (r *RPITX) commandOptions(
+	moduleName ModuleName,
+	stdin io.Reader,
+	options execOptions,
+) []commander.Option {
 	var opts []commander.Option
 	if stdin != nil {
 		opts = append(opts, commander.WithStdin(stdin))
 	}
 
 	// Set environment variables for script modules
-	if IsScriptModule(moduleName) {
-		env := []string{
-			fmt.Sprintf("RPITX_PATH=%s", r.config.Path),
+	var env []string
+	if r.IsScriptModule(moduleName) {
+		env = []string{
+			fmt.Sprintf("%s=%s", envVarNameRpitxPath, r.rpitxPath()),
 		}
-		opts = append(opts, commander.WithEnv(env))
 	}
 
+	return append(opts, options.commanderOptions(env)...)
+}
+
+func (r *RPITX) startProcess(
+	ctx context.Context,
+	moduleName ModuleName,
+	cmdName string,
+	cmdArgs []string,
+	stdin io.Reader,
+	options execOptions,
+) (commander.Process, error) {
+	r.processMu.Lock()
+
 	process, err := r.commander.Start(
 		ctx,
 		cmdName,
 		cmdArgs,
-		opts...,
+		r.commandOptions(moduleName, stdin, options)...,
 	)
+
+	if err == nil {
+		var captures []hubStream
+		if options.capture != nil {
+			captures = append(captures, options.capture.stream())
+		}
+
+		// Read from the start, with the streams waiting for it attached
+		// before the first line
+		process = &hubProcess{
+			Process: process,
+			hub: newOutputHub(
+				process, r.streamBufferSize(), r.pendingStreams, captures,
+			),
+		}
+		r.pendingStreams = nil
+		r.currentModule = moduleName
+		r.startedAt = time.Now()
+	}
+
 	r.process = process
+
 	r.processMu.Unlock()
 
 	if err != nil {
-		return ctxerrors.Wrap(err, "failed to start process")
+		return nil, ctxerrors.Wrap(err, "failed to start process")
 	}
 
-	return nil
+	return process, nil
 }
 
 func (r *RPITX) StreamOutputs(stdout, stderr chan<- string) {
 	if !r.isExecuting.Load() {
-		logrus.WithError(ErrNotExecuting).Warn("not executing")
+		r.log().Warnf("not executing: %v", ErrNotExecuting)
 
 		return
 	}
@@ -257,79 +627,142 @@ func (r *RPITX) StreamOutputs(stdout, stderr chan<- string) {
 	r.processMu.RUnlock()
 
 	if process != nil {
-		process.Stream(stdout, stderr)
+		r.forwardStreamAsync(context.Background(), process, stdout, stderr)
 
 		return
 	}
 
-	logrus.Warn("no process to stream")
+	r.log().Warnf("no process to stream")
 }
 
-// StreamOutputsAsync starts streaming outputs for the currently executing
-// process. This is a convenience method that can be called before or during
-// execution. It will wait for execution to start and then begin streaming.
+// StreamOutputsAsync streams the output of the running process like
+// StreamOutputs, or of the next one started when nothing is running yet,
+// and returns right away. A stream set up before the process starts gets
+// its output from the first line, so it can be called before Exec without
+// missing anything.
 func (r *RPITX) StreamOutputsAsync(stdout, stderr chan<- string) {
-	go func() {
-		// Wait for execution to start
-		for !r.isExecuting.Load() {
-			time.Sleep(streamingPollInterval)
-		}
-
-		// Wait a bit more for the process to be created
-		for {
-			r.processMu.RLock()
-			process := r.process
-			r.processMu.RUnlock()
-
-			if process != nil {
-				process.Stream(stdout, stderr)
+	stream, _ := r.startRelays(context.Background(), stdout, stderr)
 
-				break
-			}
+	r.processMu.Lock()
+	defer r.processMu.Unlock()
 
-			if !r.isExecuting.Load() {
-				// Execution finished before we could get the process
-				logrus.Warn("execution finished before streaming could start")
+	if r.process != nil {
+		r.process.Stream(stream.stdout, stream.stderr)
 
-				break
-			}
+		return
+	}
 
-			time.Sleep(streamingPollInterval)
-		}
-	}()
+	r.pendingStreams = append(r.pendingStreams, stream)
 }
 
+// Stop gracefully stops the running process, giving it gracefulStopTimeout
+// to exit after SIGTERM before it is killed. The process ending by either
+// signal is a successful stop and returns nil.
 func (r *RPITX) Stop(ctx context.Context) error {
+	return r.StopWithTimeout(ctx, gracefulStopTimeout)
+}
+
+// StopWithTimeout sends SIGTERM to the running process and kills it if it
+// hasn't exited once the timeout elapses. A timeout of zero kills the process
+// right away. The process ending by either signal is the expected outcome,
+// so commonerrors.ErrTerminated and ErrKilled are reported as nil. Returns
+// ErrNotExecuting when nothing is running.
+func (r *RPITX) StopWithTimeout(
+	ctx context.Context,
+	timeout time.Duration,
+) error {
 	if !r.isExecuting.Load() {
 		return ErrNotExecuting
 	}
 
+	r.requestStop()
+
 	r.processMu.RLock()
 	process := r.process
+	module := r.currentModule
 	r.processMu.RUnlock()
 
-	if process != nil {
-		if err := process.Stop(ctx); err != nil {
-			return ctxerrors.Wrap(err, "failed to stop process")
+	r.metrics().StopRequested(module)
+
+	if process == nil {
+		return nil
+	}
+
+	if timeout <= 0 {
+		err := process.Kill(ctx)
+		if err != nil && !isStopSignal(err) {
+			return ctxerrors.Wrap(err, "failed to kill process")
 		}
+
+		return nil
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := r.stopProcess(stopCtx, process)
+	if err != nil && !isStopSignal(err) {
+		return ctxerrors.Wrap(err, "failed to stop process")
 	}
 
 	return nil
 }
 
-// waitWithTimeout waits for process completion with manual timeout handling.
-func (r *RPITX) waitWithTimeout(
+// Kill force-terminates the running process with SIGKILL, skipping the
+// SIGTERM grace period, e.g. for emergency stops. The process being killed is
+// the expected outcome and returns nil. Returns ErrNotExecuting when nothing
+// is running.
+func (r *RPITX) Kill(ctx context.Context) error {
+	return r.StopWithTimeout(ctx, 0)
+}
+
+// isStopSignal reports whether err only says the process ended by the
+// SIGTERM or SIGKILL sent to stop it.
+func isStopSignal(err error) bool {
+	return errors.Is(err, commonerrors.ErrTerminated) ||
+		errors.Is(err, commonerrors.ErrKilled)
+}
+
+// stopProcess gracefully stops the process, resuming it first if it was
+// paused so it can handle the termination signal.
+func (r *RPITX) stopProcess(
 	ctx context.Context,
+	process commander.Process,
+) error {
+	r.resumeIfPaused(process)
+
+	return process.Stop(ctx) //nolint:wrapcheck // wrapped by callers
+}
+
+// waitProcess waits for the process to finish, stopping it once the timeout
+// elapses if one is specified. A done ctx kills the process right away,
+// whatever the timeout.
+func (r *RPITX) waitProcess(
+	ctx context.Context,
+	process commander.Process,
 	timeout time.Duration,
 ) error {
 	errCh := make(chan error, 1)
 
 	// Start waiting for process in goroutine
 	go func() {
-		errCh <- r.process.Wait()
+		if p, ok := process.(*hubProcess); ok {
+			p.waitCaptured()
+		}
+
+		errCh <- process.Wait()
 	}()
 
-	// Wait for either completion or timeout
+	var timeoutCh <-chan time.Time
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		timeoutCh = timer.C
+	}
+
+	// Wait for completion, timeout or cancellation
 	select {
 	case err := <-errCh:
 		// Process completed normally
@@ -339,53 +772,70 @@ func (r *RPITX) waitWithTimeout(
 
 		return nil
 
-	case <-time.After(timeout):
-		// Timeout occurred - use graceful stop with timeout
-		logrus.Debug("timeout reached, performing graceful stop")
+	case <-timeoutCh:
+		return r.stopAfterTimeout(ctx, process, errCh)
 
-		stopCtx, cancel := context.WithTimeout(
-			ctx,
-			gracefulStopTimeout,
-		)
+	case <-ctx.Done():
+		return r.killAfterCancel(ctx, process, errCh)
+	}
+}
 
-		defer cancel()
+// stopAfterTimeout gracefully stops the process whose timeout elapsed and
+// waits for it to exit.
+func (r *RPITX) stopAfterTimeout(
+	ctx context.Context,
+	process commander.Process,
+	errCh <-chan error,
+) error {
+	r.log().Debugf("timeout reached, performing graceful stop")
 
-		err := r.Stop(stopCtx)
-		if err != nil {
-			logrus.WithError(err).
-				Warn("failed to gracefully stop process after timeout")
-		}
+	stopCtx, cancel := context.WithTimeout(
+		ctx,
+		gracefulStopTimeout,
+	)
 
-		// Wait for the stop to complete
-		if err = <-errCh; err != nil {
-			// Check if this was our expected timeout termination
-			if errors.Is(err, commonerrors.ErrTerminated) ||
-				errors.Is(err, commonerrors.ErrKilled) {
-				return commonerrors.ErrTimeout
-			}
+	defer cancel()
 
-			return ctxerrors.Wrap(err, "process failed after timeout stop")
+	err := r.stopProcess(stopCtx, process)
+	if err != nil {
+		r.log().Warnf(
+			"failed to gracefully stop process after timeout: %v", err,
+		)
+	}
+
+	// Wait for the stop to complete
+	if err = <-errCh; err != nil {
+		// Check if this was our expected timeout termination
+		if isStopSignal(err) {
+			return commonerrors.ErrTimeout
 		}
 
-		return commonerrors.ErrTimeout
+		return ctxerrors.Wrap(err, "process failed after timeout stop")
 	}
+
+	return commonerrors.ErrTimeout
 }
 
-// getMockExecCmd returns mock command and args for dev environment execution.
-func (r *RPITX) getMockExecCmd(
-	name ModuleName,
-	args []string,
-) (string, []string) {
-	logrus.Debugf("preparing mock execution of module %s with args %s", name, args)
-
-	// Build the mock command that echoes every second
-	mockCmd := fmt.Sprintf(`
-		while true; do
-			echo "mocking execution of %s %s..."
-			sleep 1
-		done
-	`, name, strings.Join(args, " "))
-
-	// Return shell command and args
-	return "sh", []string{"-c", mockCmd}
+// killAfterCancel kills the process of a cancelled execution and waits for
+// it to exit. The whole process group is killed, as the context only kills
+// the process itself and would leave e.g. the sendiq of a script module
+// transmitting.
+func (r *RPITX) killAfterCancel(
+	ctx context.Context,
+	process commander.Process,
+	errCh <-chan error,
+) error {
+	r.log().Debugf("context done, killing process: %v", ctx.Err())
+
+	r.resumeIfPaused(process)
+
+	//nolint:contextcheck // ctx is done, the kill must still go through
+	if err := process.Kill(context.Background()); err != nil &&
+		!isStopSignal(err) {
+		r.log().Warnf("failed to kill process after cancel: %v", err)
+	}
+
+	<-errCh
+
+	return ctxerrors.Wrap(ctx.Err(), "execution cancelled")
 }
@@ -38,39 +38,30 @@ func TestRPITX_StartInGoroutineAndStop_Integration(t *testing.T) {
 	ctx := context.Background()
 
 	// Start execution in a goroutine
-	errCh := make(chan error, 1)
-
 	go func() {
-		err := rpitx.Exec(ctx, ModuleNamePIFMRDS, argsBytes, 30*time.Second)
-		errCh <- err
+		_ = rpitx.Exec(ctx, ModuleNamePIFMRDS, argsBytes, 30*time.Second)
 	}()
 
-	// Wait a bit for execution to start
-	time.Sleep(200 * time.Millisecond)
-
-	// Verify it's executing
-	assert.True(t, rpitx.isExecuting.Load(), "RPITX should be executing")
+	// Wait for execution to start
+	require.Eventually(t, rpitx.isExecuting.Load,
+		2*time.Second, 10*time.Millisecond, "RPITX should be executing")
 
 	// Stop execution
-	stopErr := rpitx.Stop(ctx)
-	// Stop should succeed or return expected termination errors
-	if stopErr != nil &&
-		!errors.Is(stopErr, commonerrors.ErrTerminated) &&
-		!errors.Is(stopErr, commonerrors.ErrKilled) {
-		t.Errorf("unexpected stop error: %v", stopErr)
-	}
+	require.NoError(t, rpitx.Stop(ctx))
 
 	// Wait for execution to complete
-	select {
-	case execErr := <-errCh:
-		// Execution should complete (possibly with expected termination errors)
-		if execErr != nil &&
-			!errors.Is(execErr, commonerrors.ErrTerminated) &&
-			!errors.Is(execErr, commonerrors.ErrKilled) {
-			t.Errorf("unexpected execution error: %v", execErr)
-		}
-	case <-time.After(10 * time.Second):
-		t.Fatal("execution should have completed after stop")
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	execErr := rpitx.WaitForExit(waitCtx)
+	require.NotErrorIs(t, execErr, context.DeadlineExceeded,
+		"execution should have completed after stop")
+
+	// Execution should complete (possibly with expected termination errors)
+	if execErr != nil &&
+		!errors.Is(execErr, commonerrors.ErrTerminated) &&
+		!errors.Is(execErr, commonerrors.ErrKilled) {
+		t.Errorf("unexpected execution error: %v", execErr)
 	}
 
 	// Verify no longer executing
@@ -175,13 +166,7 @@ func TestRPITX_Stop_Integration(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Stop execution
-	stopErr := rpitx.Stop(ctx)
-	// Stop should succeed or return expected termination errors
-	if stopErr != nil &&
-		!errors.Is(stopErr, commonerrors.ErrTerminated) &&
-		!errors.Is(stopErr, commonerrors.ErrKilled) {
-		t.Errorf("unexpected stop error: %v", stopErr)
-	}
+	require.NoError(t, rpitx.Stop(ctx))
 
 	// Wait for execution to complete
 	select {
@@ -448,12 +433,6 @@ func analyzeStopResults(
 			successCount++
 		case errors.Is(stopErr, ErrNotExecuting):
 			notExecutingCount++
-		case errors.Is(stopErr, commonerrors.ErrTerminated) ||
-			errors.Is(stopErr, commonerrors.ErrKilled):
-			// Process termination errors are expected when stopping
-			successCount++
-
-			t.Logf("Stop returned expected termination error: %v", stopErr)
 		default:
 			t.Errorf("Unexpected stop error: %v", stopErr)
 		}
@@ -510,18 +489,27 @@ func startExecutionForStreaming(
 
 	execDone := make(chan struct{})
 
+	// Returns once the process has started, its output is kept for the
+	// first stream so no sleep is needed before streaming
+	execution, err := rpitx.ExecAsync(
+		ctx, ModuleNamePIFMRDS,
+		[]byte(`{"freq": 107.9, "audio": ".fixtures/test.wav"}`),
+		10*time.Second,
+	)
+	if err != nil {
+		execErr = err
+
+		close(execDone)
+
+		return execDone, &execErr
+	}
+
 	go func() {
 		defer close(execDone)
 
-		execErr = rpitx.Exec(
-			ctx, ModuleNamePIFMRDS,
-			[]byte(`{"freq": 107.9, "audio": ".fixtures/test.wav"}`),
-			10*time.Second,
-		)
+		execErr = execution.Wait()
 	}()
 
-	time.Sleep(50 * time.Millisecond) // Wait briefly for execution to start
-
 	return execDone, &execErr
 }
 
@@ -3,13 +3,17 @@ package gorpitx
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/psyb0t/commander"
 	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -113,6 +117,55 @@ func TestRPITX_Exec_DevEnvironment(t *testing.T) {
 	assert.Contains(t, err.Error(), "context deadline exceeded")
 }
 
+func TestRPITX_ExecYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        string
+		expectedErr error
+	}{
+		{
+			name: "valid args",
+			args: "frequency: 434000000\nrate: 20\nmessage: TEST YAML\n",
+		},
+		{
+			name:        "invalid yaml",
+			args:        "frequency: [434000000\n",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "module validation",
+			args:        "frequency: 434000000\nrate: 20\n",
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx, _ := newExecutionTestRPITX(t)
+			rpitx.config.Mock.Iterations = 1
+
+			err := rpitx.ExecYAML(
+				context.Background(),
+				ModuleNameMORSE,
+				[]byte(tt.args),
+				5*time.Second,
+			)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, rpitx.TransmissionLog())
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			records := rpitx.TransmissionLog()
+			require.Len(t, records, 1)
+			assert.InDelta(t, 434000000.0, records[0].FrequencyHz, 0)
+		})
+	}
+}
+
 func TestRPITX_GetInstance(t *testing.T) {
 	// Set ENV=dev to avoid root check in tests
 	t.Setenv(env.EnvVarName, env.EnvTypeDev)
@@ -145,34 +198,27 @@ func TestRPITX_GetSupportedModules(t *testing.T) {
 	rpitx := GetInstance()
 	modules := rpitx.GetSupportedModules()
 
-	// Should return all registered modules
-	assert.Len(t, modules, 11)
-	assert.Contains(t, modules, ModuleNamePIFMRDS)
-	assert.Contains(t, modules, ModuleNameTUNE)
-	assert.Contains(t, modules, ModuleNameMORSE)
-	assert.Contains(t, modules, ModuleNameSPECTRUMPAINT)
-	assert.Contains(t, modules, ModuleNamePICHIRP)
-	assert.Contains(t, modules, ModuleNamePOCSAG)
-	assert.Contains(t, modules, ModuleNameFT8)
-	assert.Contains(t, modules, ModuleNamePISSSTV)
-	assert.Contains(t, modules, ModuleNamePIRTTY)
-	assert.Contains(t, modules, ModuleNameFSK)
-	assert.Contains(t, modules, ModuleNameAudioSockBroadcast)
-
-	// Should return a new slice each time (checking length consistency)
-	modules2 := rpitx.GetSupportedModules()
-	assert.Len(t, modules2, 11)
-	assert.Contains(t, modules2, ModuleNamePIFMRDS)
-	assert.Contains(t, modules2, ModuleNameTUNE)
-	assert.Contains(t, modules2, ModuleNameMORSE)
-	assert.Contains(t, modules2, ModuleNameSPECTRUMPAINT)
-	assert.Contains(t, modules2, ModuleNamePICHIRP)
-	assert.Contains(t, modules2, ModuleNamePOCSAG)
-	assert.Contains(t, modules2, ModuleNameFT8)
-	assert.Contains(t, modules2, ModuleNamePISSSTV)
-	assert.Contains(t, modules2, ModuleNamePIRTTY)
-	assert.Contains(t, modules2, ModuleNameFSK)
-	assert.Contains(t, modules2, ModuleNameAudioSockBroadcast)
+	// Should return all registered modules sorted by name
+	expected := []ModuleName{
+		ModuleNameAudioSockBroadcast,
+		ModuleNameFSK,
+		ModuleNameMORSE,
+		ModuleNamePICHIRP,
+		ModuleNamePIDTMF,
+		ModuleNamePIFMRDS,
+		ModuleNameFT8,
+		ModuleNamePIRTTY,
+		ModuleNamePISSSTV,
+		ModuleNamePOCSAG,
+		ModuleNameSENDIQ,
+		ModuleNameSPECTRUMPAINT,
+		ModuleNameSSB,
+		ModuleNameTUNE,
+	}
+	assert.Equal(t, expected, modules)
+
+	// Should return the same order every time
+	assert.Equal(t, expected, rpitx.GetSupportedModules())
 }
 
 func TestRPITX_IsSupportedModule(t *testing.T) {
@@ -197,99 +243,44 @@ func TestRPITX_IsSupportedModule(t *testing.T) {
 }
 
 func TestRPITX_StreamOutputs_DuringExecution(t *testing.T) {
-	// Test StreamOutputs timing during actual execution
 	t.Setenv(env.EnvVarName, env.EnvTypeDev)
 
-	// Reset singleton for test
-	instance = nil
-	once = sync.Once{}
-
-	rpitx := GetInstance()
+	// The mock prints its first line right away and the next one a second
+	// later
+	rpitx := &RPITX{
+		commander: commander.New(),
+		modules:   map[ModuleName]Module{ModuleNameMORSE: &MORSE{}},
+	}
 	ctx := context.Background()
 
-	// Create valid MORSE args
-	args := map[string]any{
+	argsJSON, err := json.Marshal(map[string]any{
 		"frequency": 434000000.0,
 		"rate":      20,
 		"message":   "TEST STREAMING",
-	}
-	argsJSON, err := json.Marshal(args)
+	})
 	require.NoError(t, err)
 
-	// Create channels for streaming
-	stdout := make(chan string, 100)
-	stderr := make(chan string, 100)
-
-	// Track execution state
-	execStarted := make(chan bool, 1)
-	execFinished := make(chan error, 1)
-
-	// Start execution in goroutine
-	go func() {
-		// Signal that execution is starting
-		execStarted <- true
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, argsJSON, 0)
+	require.NoError(t, err)
 
-		// Execute with timeout
-		err := rpitx.Exec(ctx, ModuleNameMORSE, argsJSON, 2*time.Second)
-		execFinished <- err
+	defer func() {
+		_ = execution.Stop(ctx)
+		<-execution.Done()
 	}()
 
-	// Wait for execution to start
-	<-execStarted
-
-	// Give the process time to actually start (this is the critical timing)
+	// A client attaching late, after the first line was printed, still
+	// gets it as the first stream
 	time.Sleep(200 * time.Millisecond)
 
-	// Now try to stream outputs - this should work if timing is correct
-	streamStarted := make(chan bool, 1)
-
-	go func() {
-		rpitx.StreamOutputs(stdout, stderr)
-
-		streamStarted <- true
-	}()
-
-	// Wait for streaming to be set up
-	<-streamStarted
-
-	// Collect some output or timeout
-	outputReceived := false
-	timeout := time.After(1500 * time.Millisecond)
-
-	for {
-		select {
-		case line := <-stdout:
-			t.Logf("Received stdout: %s", line)
-
-			outputReceived = true
-		case line := <-stderr:
-			t.Logf("Received stderr: %s", line)
-
-			outputReceived = true
-		case err := <-execFinished:
-			t.Logf("Execution finished with error: %v", err)
-
-			goto checkResults
-		case <-timeout:
-			t.Log("Test timeout reached")
-
-			goto checkResults
-		}
-	}
+	stdout := make(chan string, 100)
+	rpitx.StreamOutputs(stdout, nil)
 
-checkResults:
-	// We should have received some output from the mock command
-	if !outputReceived {
-		t.Log(
-			"WARNING: No output received - this indicates a timing issue " +
-				"with StreamOutputs",
-		)
-		t.Log("The mock command should output 'mocking execution' every second")
+	select {
+	case line := <-stdout:
+		assert.Contains(t, line, "mocking execution of morse")
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("first line missed by a stream attached after the start")
 	}
-
-	// Clean up
-	instance = nil
-	once = sync.Once{}
 }
 
 func TestRPITX_StreamOutputsAsync(t *testing.T) {
@@ -371,7 +362,7 @@ func TestRPITX_getMockExecCmd(t *testing.T) {
 
 	args := []string{"-freq", "107.9", "-audio", ".fixtures/test.wav"}
 
-	cmdName, cmdArgs := rpitx.getMockExecCmd(ModuleNamePIFMRDS, args)
+	cmdName, cmdArgs := rpitx.getMockExecCmd(ModuleNamePIFMRDS, args, false)
 
 	// Should return shell command
 	assert.Equal(t, "sh", cmdName)
@@ -387,7 +378,7 @@ func TestRPITX_getMockExecCmd_CommandContent(t *testing.T) {
 
 	args := []string{"-freq", "107.9", "-ps", "TEST FM"}
 
-	cmdName, cmdArgs := rpitx.getMockExecCmd("testmodule", args)
+	cmdName, cmdArgs := rpitx.getMockExecCmd("testmodule", args, false)
 
 	// Should return shell command
 	assert.Equal(t, "sh", cmdName)
@@ -400,6 +391,68 @@ func TestRPITX_getMockExecCmd_CommandContent(t *testing.T) {
 	assert.Contains(t, cmdArgs[1], "-freq 107.9 -ps TEST FM")
 	assert.Contains(t, cmdArgs[1], "sleep 1")
 	assert.Contains(t, cmdArgs[1], "done")
+	assert.NotContains(t, cmdArgs[1], "mocking stdin")
+
+	_, cmdArgs = rpitx.getMockExecCmd("testmodule", args, true)
+	assert.Contains(t, cmdArgs[1], "echo \"mocking stdin: $line\"")
+}
+
+func TestRPITX_MockEchoesStdin(t *testing.T) {
+	// Set ENV=dev to test mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx, err := New(WithLogger(NopLogger()))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		module   ModuleName
+		args     map[string]any
+		expected []string
+	}{
+		{
+			name:   "POCSAG pages",
+			module: ModuleNamePOCSAG,
+			args: map[string]any{
+				"frequency": 466230000,
+				"messages": []map[string]any{
+					{"address": 123, "message": "Hello"},
+					{"address": 456, "message": "World"},
+				},
+			},
+			expected: []string{
+				"mocking stdin: 123:Hello",
+				"mocking stdin: 456:World",
+			},
+		},
+		{
+			name:   "FSK text",
+			module: ModuleNameFSK,
+			args: map[string]any{
+				"frequency": 434000000,
+				"inputType": "text",
+				"text":      "HELLO",
+			},
+			expected: []string{"mocking stdin: HELLO"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := json.Marshal(tt.args)
+			require.NoError(t, err)
+
+			stdout, _, err := rpitx.ExecOutput(
+				context.Background(), tt.module, args, 500*time.Millisecond,
+			)
+			require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+			lines := strings.Split(string(stdout), "\n")
+			require.Greater(t, len(lines), len(tt.expected))
+			assert.Equal(t, tt.expected, lines[:len(tt.expected)])
+			assert.Contains(t, lines[len(tt.expected)], "mocking execution of")
+		})
+	}
 }
 
 func TestRPITX_Exec_TuneModule(t *testing.T) {
@@ -549,6 +602,7 @@ func TestRPITX_Exec_TuneModule(t *testing.T) {
 func TestRPITX_ProductionExecution_Success(t *testing.T) {
 	// Test actual production execution path with mock commander
 	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+	t.Setenv("HOME", "/home/pi")
 
 	mockCommander := commander.NewMock()
 	rpitx := &RPITX{
@@ -573,7 +627,7 @@ func TestRPITX_ProductionExecution_Success(t *testing.T) {
 
 	// Mock successful production execution with stdbuf wrapper
 	mockCommander.Expect("stdbuf",
-		"-oL", "$HOME/rpitx/pifmrds",
+		"-oL", "/home/pi/rpitx/pifmrds",
 		"-freq", "107.9",
 		"-audio", ".fixtures/test.wav",
 		"-pi", "1234",
@@ -626,6 +680,14 @@ func TestRPITX_ProductionExecution_StartFailure(t *testing.T) {
 }
 
 func TestPIFMRDS_ControlPipeValidation(t *testing.T) {
+	dir := t.TempDir()
+
+	regularFile := filepath.Join(dir, "regular.pipe")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0o600))
+
+	fifo := filepath.Join(dir, "rds.pipe")
+	require.NoError(t, CreateControlPipe(fifo))
+
 	tests := []struct {
 		name        string
 		controlPipe *string
@@ -649,6 +711,17 @@ func TestPIFMRDS_ControlPipeValidation(t *testing.T) {
 			expectError: true,
 			errorMsg:    "control pipe does not exist",
 		},
+		{
+			name:        "regular file control pipe",
+			controlPipe: stringPtr(regularFile),
+			expectError: true,
+			errorMsg:    "control pipe is not a FIFO",
+		},
+		{
+			name:        "fifo control pipe",
+			controlPipe: stringPtr(fifo),
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -866,6 +939,147 @@ func TestRPITX_PrepareCommand_Production(t *testing.T) {
 	t.Logf("Production command: %s %v", cmdName, cmdArgs)
 }
 
+func TestRPITX_PrepareCommand_BinaryPaths(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	local := filepath.Join(home, "tune")
+	require.NoError(t, os.WriteFile(local, nil, 0o600))
+
+	tests := []struct {
+		name         string
+		binaryPaths  map[ModuleName]string
+		expectedPath string
+		expectedErr  error
+	}{
+		{
+			name:         "no override",
+			expectedPath: "/opt/rpitx/tune",
+		},
+		{
+			name: "override for another module",
+			binaryPaths: map[ModuleName]string{
+				ModuleNamePOCSAG: "/usr/bin/pocsag",
+			},
+			expectedPath: "/opt/rpitx/tune",
+		},
+		{
+			name:         "override",
+			binaryPaths:  map[ModuleName]string{ModuleNameTUNE: local},
+			expectedPath: local,
+		},
+		{
+			name:         "override with home",
+			binaryPaths:  map[ModuleName]string{ModuleNameTUNE: "~/tune"},
+			expectedPath: local,
+		},
+		{
+			name: "missing override",
+			binaryPaths: map[ModuleName]string{
+				ModuleNameTUNE: filepath.Join(home, "missing"),
+			},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{
+				config: Config{
+					Path:        "/opt/rpitx",
+					BinaryPaths: tt.binaryPaths,
+				},
+				modules: map[ModuleName]Module{ModuleNameTUNE: &TUNE{}},
+			}
+
+			_, cmdArgs, _, err := rpitx.prepareCommand(
+				ModuleNameTUNE,
+				[]byte(`{"frequency": 434000000}`),
+			)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPath, cmdArgs[1])
+		})
+	}
+}
+
+func TestRPITX_PrepareCommand_RepeatGap(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	scriptDir := t.TempDir()
+	rpitx := &RPITX{
+		config: Config{Path: "/opt/rpitx", ScriptDir: scriptDir},
+		modules: map[ModuleName]Module{
+			ModuleNamePOCSAG: &POCSAG{},
+		},
+	}
+
+	// Without a gap the binary repeats on its own
+	_, cmdArgs, _, err := rpitx.prepareCommand(
+		ModuleNamePOCSAG,
+		[]byte(`{"frequency": 466230000, "repeatCount": 3, `+
+			`"messages": [{"address": 1, "message": "hi"}]}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"-oL", "/opt/rpitx/pocsag", "-f", "466230000", "-t", "3",
+	}, cmdArgs)
+
+	// With a gap every repeat is a separate run through the repeat script
+	_, cmdArgs, _, err = rpitx.prepareCommand(
+		ModuleNamePOCSAG,
+		[]byte(`{"frequency": 466230000, "repeatCount": 3, "gapMs": 750, `+
+			`"messages": [{"address": 1, "message": "hi"}]}`),
+	)
+	require.NoError(t, err)
+
+	scriptPath := filepath.Join(scriptDir, repeatScriptName)
+	assert.Equal(t, []string{
+		"-oL", scriptPath, "3", "750",
+		"/opt/rpitx/pocsag", "-f", "466230000", "-t", "1",
+	}, cmdArgs)
+
+	content, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, repeatScript, string(content))
+}
+
+func TestRPITX_PrepareCommand_Sequence(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	scriptDir := t.TempDir()
+	rpitx := &RPITX{
+		config: Config{Path: "/opt/rpitx", ScriptDir: scriptDir},
+		modules: map[ModuleName]Module{
+			ModuleNameFT8: &FT8{},
+		},
+	}
+
+	_, cmdArgs, _, err := rpitx.prepareCommand(
+		ModuleNameFT8,
+		[]byte(`{"frequency": 14074000, "autoSlot": true, `+
+			`"messages": ["CQ W1AW FN31", "K0HAM W1AW RR73"]}`),
+	)
+	require.NoError(t, err)
+
+	scriptPath := filepath.Join(scriptDir, sequenceScriptName)
+	assert.Equal(t, []string{
+		"-oL", scriptPath, "2", "CQ W1AW FN31", "K0HAM W1AW RR73",
+		"/opt/rpitx/pift8", "-f", "14074000", "-m",
+	}, cmdArgs)
+
+	content, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, sequenceScript, string(content))
+}
+
 func TestRPITX_PrepareCommand_Development(t *testing.T) {
 	// Test that development mode uses mock execution
 	t.Setenv(env.EnvVarName, env.EnvTypeDev)
@@ -981,6 +1195,9 @@ func TestModules_StdinBehavior(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The public predicate matches what the module actually does
+			assert.Equal(t, !tt.expectStdinNil, moduleUsesStdin(tt.module))
+
 			inputBytes, err := json.Marshal(tt.input)
 			require.NoError(t, err)
 
@@ -1000,3 +1217,64 @@ func TestModules_StdinBehavior(t *testing.T) {
 		})
 	}
 }
+
+func TestRPITX_BusyError(t *testing.T) {
+	rpitx := &RPITX{}
+
+	// Slot taken before anything was started
+	err := rpitx.busyError()
+	require.ErrorIs(t, err, ErrExecuting)
+	assert.Equal(t, ErrExecuting.Error(), err.Error())
+
+	startedAt := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	rpitx.currentModule = ModuleNamePOCSAG
+	rpitx.startedAt = startedAt
+
+	err = rpitx.busyError()
+	require.ErrorIs(t, err, ErrExecuting)
+	assert.Contains(
+		t, err.Error(), "pocsag running since 2025-06-01T12:30:00Z",
+	)
+}
+
+func TestRPITX_PrepareInput(t *testing.T) {
+	installResamplers(t, "sox")
+
+	module := &PIFMRDS{ResampleTo: intPtr(48000)}
+	rpitx := &RPITX{commander: commander.NewMock()}
+	cmdArgs := []string{"-freq", "107.9", "-audio", "in.wav"}
+
+	// The dev mode mock doesn't read the audio
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	args, cleanup, err := rpitx.prepareInput(
+		context.Background(), module, cmdArgs,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, cmdArgs, args)
+	cleanup()
+
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	_, _, err = rpitx.prepareInput(
+		context.Background(), module, cmdArgs,
+	)
+	require.ErrorIs(t, err, commander.ErrUnexpectedCommand)
+	assert.Contains(t, err.Error(), "failed to prepare input")
+}
+
+func TestRPITX_InputCleanup(t *testing.T) {
+	rpitx := &RPITX{}
+
+	var cleaned []string
+
+	rpitx.setInputCleanup(func() { cleaned = append(cleaned, "first") })
+
+	// A restart replaces the input of the previous launch
+	rpitx.setInputCleanup(func() { cleaned = append(cleaned, "second") })
+	assert.Equal(t, []string{"first"}, cleaned)
+
+	rpitx.cleanupExecution(context.Background())
+	assert.Equal(t, []string{"first", "second"}, cleaned)
+	assert.Nil(t, rpitx.inputCleanup)
+}
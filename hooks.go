@@ -0,0 +1,169 @@
+package gorpitx
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// hookEventBuffer fits every event of a single execution (start, a
+	// pending progress tick, stop and error) so dispatching never blocks.
+	hookEventBuffer = 4
+
+	// progressInterval is how often OnProgress is called.
+	progressInterval = time.Second
+)
+
+// Hooks are callbacks invoked on execution lifecycle events, e.g. to drive
+// LEDs or update a UI. They run in order on a separate goroutine so they
+// never block the transmission. OnProgress fires on every tick while the
+// module runs, the others at most once per execution.
+type Hooks struct {
+	// OnStart is called once the module process has started.
+	OnStart func(name ModuleName)
+
+	// OnStop is called once a started execution has finished, with the same
+	// error Exec returns (nil on success).
+	OnStop func(err error)
+
+	// OnError is called when an execution fails, including failures to
+	// parse the args or start the process.
+	OnError func(err error)
+
+	// OnProgress is called every second while the module process runs,
+	// e.g. to drive a progress bar, with the time since it started and the
+	// expected length of the transmission. total is 0 when the length isn't
+	// known, e.g. for TUNE or a repeating FT8. Ticks are skipped while the
+	// hooks are still busy with earlier events, and none fires after
+	// OnStop.
+	OnProgress func(elapsed, total time.Duration)
+}
+
+// SetHooks sets the lifecycle callbacks used by the following executions.
+func (r *RPITX) SetHooks(hooks Hooks) {
+	r.hooks.Store(&hooks)
+}
+
+// hookDispatcher delivers the lifecycle events of one execution.
+type hookDispatcher struct {
+	hooks  Hooks
+	events chan func()
+	logger Logger
+}
+
+// newHookDispatcher returns a dispatcher for a new execution, or nil when no
+// hooks are set.
+func (r *RPITX) newHookDispatcher() *hookDispatcher {
+	hooks := r.hooks.Load()
+	if hooks == nil {
+		return nil
+	}
+
+	d := &hookDispatcher{
+		hooks:  *hooks,
+		events: make(chan func(), hookEventBuffer),
+		logger: r.log(),
+	}
+
+	go d.run()
+
+	return d
+}
+
+func (d *hookDispatcher) run() {
+	for event := range d.events {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					d.logger.Errorf("lifecycle hook panicked: %v", r)
+				}
+			}()
+
+			event()
+		}()
+	}
+}
+
+// started reports that the module process has started.
+func (d *hookDispatcher) started(name ModuleName) {
+	if d == nil || d.hooks.OnStart == nil {
+		return
+	}
+
+	d.events <- func() { d.hooks.OnStart(name) }
+}
+
+// trackProgress calls OnProgress every progressInterval until the returned
+// func is called, which waits for the ticks to stop. total is the expected
+// length of the transmission, 0 when unknown.
+func (d *hookDispatcher) trackProgress(total time.Duration) func() {
+	if d == nil || d.hooks.OnProgress == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				d.progressed(time.Since(start), total)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// progressed reports the progress of the execution, unless the hooks are
+// still busy with earlier events so ticks don't pile up.
+func (d *hookDispatcher) progressed(elapsed, total time.Duration) {
+	if len(d.events) > 0 {
+		return
+	}
+
+	d.events <- func() { d.hooks.OnProgress(elapsed, total) }
+}
+
+// stopped reports that a started execution has finished and ends the
+// dispatcher.
+func (d *hookDispatcher) stopped(err error) {
+	if d == nil {
+		return
+	}
+
+	if d.hooks.OnStop != nil {
+		d.events <- func() { d.hooks.OnStop(err) }
+	}
+
+	d.failed(err)
+}
+
+// failed reports the execution error, if any, and ends the dispatcher.
+func (d *hookDispatcher) failed(err error) {
+	if d == nil {
+		return
+	}
+
+	if err != nil && d.hooks.OnError != nil {
+		d.events <- func() { d.hooks.OnError(err) }
+	}
+
+	close(d.events)
+}
@@ -0,0 +1,237 @@
+package gorpitx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordHooks returns hooks that report every event on the returned channel.
+func recordHooks() (Hooks, chan string) {
+	events := make(chan string, 10)
+
+	return Hooks{
+		OnStart: func(name ModuleName) {
+			events <- "start " + name
+		},
+		OnStop: func(err error) {
+			events <- fmt.Sprintf("stop %v", err)
+		},
+		OnError: func(err error) {
+			events <- fmt.Sprintf("error %v", err)
+		},
+	}, events
+}
+
+// collectEvents reads the expected number of events, failing on timeout.
+func collectEvents(t *testing.T, events chan string, count int) []string {
+	t.Helper()
+
+	collected := make([]string, 0, count)
+
+	for range count {
+		select {
+		case event := <-events:
+			collected = append(collected, event)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for hook events, got %v", collected)
+		}
+	}
+
+	// Nothing else must fire
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected hook event: %s", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	return collected
+}
+
+func TestRPITX_Hooks_Success(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		config: Config{Path: "/opt/rpitx"},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: mockCommander,
+	}
+
+	hooks, events := recordHooks()
+	rpitx.SetHooks(hooks)
+
+	mockCommander.Expect(
+		"stdbuf", "-oL", "/opt/rpitx/tune", "-f", "434000000",
+	).ReturnError(nil)
+
+	err := rpitx.Exec(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), 0,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"start tune", "stop <nil>"},
+		collectEvents(t, events, 2),
+	)
+}
+
+func TestRPITX_Hooks_Timeout(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	hooks, events := recordHooks()
+	rpitx.SetHooks(hooks)
+
+	err := rpitx.Exec(
+		context.Background(), ModuleNameMORSE, args, 200*time.Millisecond,
+	)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	assert.Equal(t,
+		[]string{
+			"start morse",
+			"stop " + commonerrors.ErrTimeout.Error(),
+			"error " + commonerrors.ErrTimeout.Error(),
+		},
+		collectEvents(t, events, 3),
+	)
+}
+
+func TestRPITX_Hooks_LaunchFailure(t *testing.T) {
+	rpitx, _ := newExecutionTestRPITX(t)
+
+	hooks, events := recordHooks()
+	rpitx.SetHooks(hooks)
+
+	err := rpitx.Exec(context.Background(), "nonexistent", []byte(`{}`), 0)
+	require.ErrorIs(t, err, ErrUnknownModule)
+
+	collected := collectEvents(t, events, 1)
+	assert.Contains(t, collected[0], "error ")
+	assert.Contains(t, collected[0], ErrUnknownModule.Error())
+}
+
+func TestRPITX_Hooks_ExecOutput(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	hooks, events := recordHooks()
+	rpitx.SetHooks(hooks)
+
+	_, _, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameMORSE, args, 200*time.Millisecond,
+	)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	assert.Equal(t,
+		[]string{
+			"start morse",
+			"stop " + commonerrors.ErrTimeout.Error(),
+			"error " + commonerrors.ErrTimeout.Error(),
+		},
+		collectEvents(t, events, 3),
+	)
+}
+
+func TestRPITX_Hooks_PanicDoesNotBreakExecution(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		config: Config{Path: "/opt/rpitx"},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+		commander: mockCommander,
+	}
+
+	stopped := make(chan error, 1)
+	rpitx.SetHooks(Hooks{
+		OnStart: func(ModuleName) { panic("boom") },
+		OnStop:  func(err error) { stopped <- err },
+	})
+
+	mockCommander.Expect(
+		"stdbuf", "-oL", "/opt/rpitx/tune", "-f", "434000000",
+	).ReturnError(nil)
+
+	err := rpitx.Exec(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), 0,
+	)
+	require.NoError(t, err)
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("OnStop was not called after a panicking OnStart")
+	}
+}
+
+func TestRPITX_Hooks_OnProgress(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	type tick struct{ elapsed, total time.Duration }
+
+	ticks := make(chan tick, 10)
+	stopped := make(chan struct{})
+	rpitx.SetHooks(Hooks{
+		OnProgress: func(elapsed, total time.Duration) {
+			ticks <- tick{elapsed, total}
+		},
+		OnStop: func(error) { close(stopped) },
+	})
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	var last time.Duration
+
+	for range 2 {
+		select {
+		case tick := <-ticks:
+			assert.Greater(t, tick.elapsed, last)
+			assert.GreaterOrEqual(t, tick.elapsed, progressInterval/2)
+
+			last = tick.elapsed
+		case <-time.After(3 * progressInterval):
+			t.Fatal("no progress reported")
+		}
+	}
+
+	require.NoError(t, execution.Stop(ctx))
+	<-stopped
+
+	// Hooks run in order, so ticks sent before OnStop are already in
+	for len(ticks) > 0 {
+		<-ticks
+	}
+
+	// The ticks stop with the process
+	select {
+	case tick := <-ticks:
+		t.Fatalf("progress reported after the execution: %v", tick)
+	case <-time.After(progressInterval + 200*time.Millisecond):
+	}
+}
+
+func TestHookDispatcher_TrackProgressWithoutHook(t *testing.T) {
+	var dispatcher *hookDispatcher
+
+	// Nothing to track, the stop func is still safe to call
+	dispatcher.trackProgress(time.Minute)()
+
+	dispatcher = &hookDispatcher{hooks: Hooks{}}
+	dispatcher.trackProgress(time.Minute)()
+}
@@ -0,0 +1,303 @@
+// Package httpapi exposes an RPITX over HTTP, turning it into a drop-in
+// transmit server:
+//
+//	POST /modules/{name}  start a module, the body is its JSON args
+//	POST /stop            gracefully stop the running module
+//	GET  /status          report the execution status
+//	GET  /stream          stream the output as server-sent events
+//	GET  /ws              stream the output over a WebSocket
+//
+// Errors are returned as {"error": "..."} with a matching status code, e.g.
+// 400 with the module's validation error text for invalid args.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gorpitx"
+)
+
+const (
+	// maxBodyBytes caps the size of the module args accepted in a request.
+	maxBodyBytes = 1 << 20
+
+	// timeoutParam is the query parameter holding the execution timeout as
+	// a Go duration, e.g. ?timeout=30s. Without it the configured default
+	// timeout applies, if any.
+	timeoutParam = "timeout"
+
+	// Server-sent event names used by the stream endpoint.
+	eventStdout = "stdout"
+	eventStderr = "stderr"
+	eventEnd    = "end"
+)
+
+// ErrStreamingUnsupported is returned by the stream endpoint when the
+// response writer can't flush.
+var ErrStreamingUnsupported = errors.New("streaming not supported")
+
+// Handler is an http.Handler serving the RPITX API.
+type Handler struct {
+	rpitx *gorpitx.RPITX
+	mux   *http.ServeMux
+
+	// allowedOrigins lists the origins allowed to open a WebSocket besides
+	// the server's own.
+	allowedOrigins []string
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithAllowedOrigins allows browser pages on the given origins, e.g.
+// "https://dashboard.example.com", to open the WebSocket endpoint. By
+// default only pages served from the same host as the API can, so a page
+// the operator happens to visit can't reach the transmitter. "*" allows
+// every origin.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *Handler) {
+		h.allowedOrigins = append(h.allowedOrigins, origins...)
+	}
+}
+
+// errorResponse is the body of every error response.
+type errorResponse struct {
+	Error string `json:"error"`
+
+	// Fields lists the invalid args of a failed validation.
+	Fields []fieldErrorResponse `json:"fields,omitempty"`
+}
+
+// fieldErrorResponse is an invalid arg in an error response.
+type fieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns a Handler serving the API for the given RPITX.
+func New(rpitx *gorpitx.RPITX, opts ...Option) *Handler {
+	h := &Handler{
+		rpitx: rpitx,
+		mux:   http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("POST /modules/{name}", h.handleExec)
+	h.mux.HandleFunc("POST /stop", h.handleStop)
+	h.mux.HandleFunc("GET /status", h.handleStatus)
+	h.mux.HandleFunc("GET /stream", h.handleStream)
+	h.mux.HandleFunc("GET /ws", h.handleWebSocket)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// handleExec validates the args and starts the module, responding with the
+// execution status once it is running.
+func (h *Handler) handleExec(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	args, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	timeout, err := parseTimeout(r, h.rpitx.ArgsDefaultTimeout(name, args))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if err := h.rpitx.ValidateArgs(name, args); err != nil {
+		writeError(w, validationStatus(err), err)
+
+		return
+	}
+
+	// The execution outlives the request
+	ctx := context.WithoutCancel(r.Context())
+
+	if _, err := h.rpitx.ExecAsync(ctx, name, args, timeout); err != nil {
+		writeError(w, execStatus(err), err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, h.rpitx.Status())
+}
+
+// handleStop gracefully stops the running module.
+func (h *Handler) handleStop(w http.ResponseWriter, r *http.Request) {
+	err := h.rpitx.Stop(r.Context())
+	if err != nil {
+		writeError(w, execStatus(err), err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.rpitx.Status())
+}
+
+// handleStatus reports the execution status.
+func (h *Handler) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.rpitx.Status())
+}
+
+// handleStream forwards the output of the running module as server-sent
+// events named stdout and stderr, followed by an end event once the output
+// ends. The stream stops when the client disconnects.
+func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, ErrStreamingUnsupported)
+
+		return
+	}
+
+	if !h.rpitx.Status().Executing {
+		writeError(w, http.StatusConflict, gorpitx.ErrNotExecuting)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		h.rpitx.StreamOutputsCtx(r.Context(), stdout, stderr)
+	}()
+
+	for {
+		select {
+		case line, ok := <-stdout:
+			if !ok {
+				stdout = nil
+
+				continue
+			}
+
+			writeEvent(w, eventStdout, line)
+		case line, ok := <-stderr:
+			if !ok {
+				stderr = nil
+
+				continue
+			}
+
+			writeEvent(w, eventStderr, line)
+		case <-done:
+			writeEvent(w, eventEnd, "")
+			flusher.Flush()
+
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// parseTimeout reads the optional execution timeout from the query, falling
+// back to the given default.
+func parseTimeout(
+	r *http.Request,
+	fallback time.Duration,
+) (time.Duration, error) {
+	value := r.URL.Query().Get(timeoutParam)
+	if value == "" {
+		return fallback, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid timeout %q",
+			value,
+		)
+	}
+
+	return timeout, nil
+}
+
+// validationStatus maps an args validation error to a status code.
+func validationStatus(err error) int {
+	if errors.Is(err, gorpitx.ErrUnknownModule) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusBadRequest
+}
+
+// execStatus maps an execution error to a status code.
+func execStatus(err error) int {
+	switch {
+	case errors.Is(err, gorpitx.ErrUnknownModule):
+		return http.StatusNotFound
+	case errors.Is(err, gorpitx.ErrExecuting),
+		errors.Is(err, gorpitx.ErrNotExecuting):
+		return http.StatusConflict
+	case errors.Is(err, gorpitx.ErrFreqNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, gorpitx.ErrCoolingDown):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v) //nolint:errchkjson
+}
+
+// writeError writes err as a JSON error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	resp := errorResponse{Error: err.Error()}
+
+	var validationErrs gorpitx.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			resp.Fields = append(resp.Fields, fieldErrorResponse{
+				Field:   fieldErr.Field,
+				Message: fieldErr.Message,
+			})
+		}
+	}
+
+	writeJSON(w, status, resp)
+}
+
+// writeEvent writes a single server-sent event.
+func writeEvent(w io.Writer, event, data string) {
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
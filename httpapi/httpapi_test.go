@@ -0,0 +1,252 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	"github.com/psyb0t/gorpitx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const morseArgs = `{"frequency":434000000,"rate":20,"message":"TEST"}`
+
+func newTestServer(
+	t *testing.T,
+	opts ...gorpitx.Option,
+) (*httptest.Server, *gorpitx.RPITX) {
+	t.Helper()
+
+	// Set ENV=dev to get mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx, err := gorpitx.New(opts...)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(New(rpitx))
+
+	t.Cleanup(func() {
+		_ = rpitx.Kill(context.Background())
+
+		server.Close()
+	})
+
+	return server, rpitx
+}
+
+func doRequest(
+	t *testing.T,
+	method, url, body string,
+) (int, map[string]any) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		method,
+		url,
+		strings.NewReader(body),
+	)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+
+	return resp.StatusCode, decoded
+}
+
+func TestHandler_Exec(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []gorpitx.Option
+		path           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "starts module",
+			path:           "/modules/morse?timeout=1m",
+			body:           morseArgs,
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "unknown module",
+			path:           "/modules/nope",
+			body:           `{}`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "unknown module",
+		},
+		{
+			name:           "invalid args",
+			path:           "/modules/morse",
+			body:           `{"frequency":434000000,"rate":20}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "message",
+		},
+		{
+			name:           "invalid timeout",
+			path:           "/modules/morse?timeout=soon",
+			body:           morseArgs,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid timeout",
+		},
+		{
+			name: "frequency not allowed",
+			opts: []gorpitx.Option{
+				gorpitx.WithFrequencyPolicy(gorpitx.FrequencyPolicy{
+					MaxHz: 30000000,
+				}),
+			},
+			path:           "/modules/morse",
+			body:           morseArgs,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, tt.opts...)
+
+			status, body := doRequest(
+				t,
+				http.MethodPost,
+				server.URL+tt.path,
+				tt.body,
+			)
+			assert.Equal(t, tt.expectedStatus, status)
+
+			if tt.expectedError != "" {
+				assert.Contains(t, body["error"], tt.expectedError)
+
+				return
+			}
+
+			assert.Equal(t, true, body["executing"])
+			assert.Equal(t, gorpitx.ModuleNameMORSE, body["module"])
+		})
+	}
+}
+
+func TestHandler_Lifecycle(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodGet, server.URL+"/status", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, body["executing"])
+
+	status, _ = doRequest(t, http.MethodPost, server.URL+"/stop", "")
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = doRequest(
+		t,
+		http.MethodPost,
+		server.URL+"/modules/morse",
+		morseArgs,
+	)
+	require.Equal(t, http.StatusAccepted, status)
+
+	// Busy while the first module runs
+	status, body = doRequest(
+		t,
+		http.MethodPost,
+		server.URL+"/modules/morse",
+		morseArgs,
+	)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Contains(t, body["error"], "busy")
+
+	status, body = doRequest(t, http.MethodGet, server.URL+"/status", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body["executing"])
+
+	status, _ = doRequest(t, http.MethodPost, server.URL+"/stop", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.Eventually(t, func() bool {
+		_, body := doRequest(t, http.MethodGet, server.URL+"/status", "")
+
+		return body["executing"] == false
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestHandler_Stream(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodGet, server.URL+"/stream", "")
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Contains(t, body["error"], "not executing")
+
+	status, _ = doRequest(
+		t,
+		http.MethodPost,
+		server.URL+"/modules/morse",
+		morseArgs,
+	)
+	require.Equal(t, http.StatusAccepted, status)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		server.URL+"/stream",
+		nil,
+	)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	require.True(t, scanner.Scan())
+	assert.Equal(t, "event: stdout", scanner.Text())
+
+	require.True(t, scanner.Scan())
+	assert.Contains(t, scanner.Text(), "data: mocking execution of morse")
+}
+
+func TestHandler_Exec_FieldErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	status, body := doRequest(
+		t,
+		http.MethodPost,
+		server.URL+"/modules/morse",
+		`{"frequency":1,"rate":-1}`,
+	)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	fields, ok := body["fields"].([]any)
+	require.True(t, ok)
+
+	names := make([]any, 0, len(fields))
+	for _, field := range fields {
+		fieldErr, ok := field.(map[string]any)
+		require.True(t, ok)
+		assert.NotContains(t, fieldErr["message"], ".go:")
+
+		names = append(names, fieldErr["field"])
+	}
+
+	assert.Equal(t, []any{"frequency", "rate", "message"}, names)
+}
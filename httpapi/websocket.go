@@ -0,0 +1,384 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" //nolint:gosec // mandated by RFC 6455 for the handshake
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gorpitx"
+)
+
+// Minimal RFC 6455 server side, enough to push text frames to a browser and
+// notice when it goes away.
+const (
+	webSocketGUID    = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketVersion = "13"
+
+	opcodeText  = 0x1
+	opcodeClose = 0x8
+	opcodePing  = 0x9
+	opcodePong  = 0xA
+
+	finBit         = 0x80
+	maskBit        = 0x80
+	opcodeMask     = 0x0F
+	payloadLenMask = 0x7F
+
+	payloadLen16 = 126 // the length follows as 16 bits
+	payloadLen64 = 127 // the length follows as 64 bits
+	maskKeySize  = 4
+
+	// maxClientFrameBytes caps the frames read from the client, which is
+	// only expected to send control frames.
+	maxClientFrameBytes = 1 << 16
+
+	closeNormal = 1000
+
+	// wsWriteTimeout bounds every frame write, so a client that stops
+	// reading is dropped instead of blocking the handler forever.
+	wsWriteTimeout = 10 * time.Second
+
+	// anyOrigin allows WebSocket upgrades from every origin.
+	anyOrigin = "*"
+)
+
+var (
+	// ErrWebSocketHandshake is returned when a request isn't a valid
+	// WebSocket upgrade.
+	ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+
+	// ErrOriginNotAllowed is returned when a browser asks for a WebSocket
+	// upgrade from an origin other than the server's own that isn't
+	// allowed with WithAllowedOrigins.
+	ErrOriginNotAllowed = errors.New("websocket origin not allowed")
+)
+
+// wsFrame is the JSON payload of every text frame sent to the client. Output
+// frames carry Stream and Line, status frames carry Status.
+type wsFrame struct {
+	Stream string                   `json:"stream,omitempty"`
+	Line   string                   `json:"line,omitempty"`
+	Status *gorpitx.ExecutionStatus `json:"status,omitempty"`
+}
+
+// handleWebSocket forwards the output of the running module to a WebSocket
+// client as JSON text frames like {"stream":"stdout","line":"..."} and
+// closes the connection once the output ends. When nothing is executing a
+// single status frame is sent before closing. The client disconnecting
+// stops the forwarding, and so does a frame that can't be written within
+// wsWriteTimeout. Cross-origin upgrades are refused with 403 unless the
+// origin is allowed with WithAllowedOrigins.
+func (h *Handler) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !h.originAllowed(r) {
+		writeError(w, http.StatusForbidden, ErrOriginNotAllowed)
+
+		return
+	}
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if status := h.rpitx.Status(); !status.Executing {
+		_ = conn.writeJSON(wsFrame{Status: &status})
+		_ = conn.writeClose(closeNormal)
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+
+	go conn.readLoop(cancel)
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		h.rpitx.StreamOutputsCtx(ctx, stdout, stderr)
+	}()
+
+	for {
+		var frame wsFrame
+
+		select {
+		case line, ok := <-stdout:
+			if !ok {
+				stdout = nil
+
+				continue
+			}
+
+			frame = wsFrame{Stream: eventStdout, Line: line}
+		case line, ok := <-stderr:
+			if !ok {
+				stderr = nil
+
+				continue
+			}
+
+			frame = wsFrame{Stream: eventStderr, Line: line}
+		case <-done:
+			if ctx.Err() == nil {
+				_ = conn.writeClose(closeNormal)
+			}
+
+			return
+		}
+
+		// A failed or stalled write drops the client, the deferred close
+		// releases the connection and cancel ends the forwarding
+		if err := conn.writeJSON(frame); err != nil {
+			return
+		}
+	}
+}
+
+// originAllowed reports whether the WebSocket upgrade may proceed. Requests
+// without an Origin header don't come from a browser page and are allowed,
+// as are same-origin requests and the origins set with WithAllowedOrigins.
+func (h *Handler) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range h.allowedOrigins {
+		if allowed == anyOrigin || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// wsConn is a server side WebSocket connection.
+type wsConn struct {
+	net.Conn
+
+	reader       *bufio.Reader
+	writeMu      sync.Mutex
+	writeTimeout time.Duration
+}
+
+// upgradeWebSocket performs the opening handshake and hijacks the
+// connection. On failure an error response has already been written.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		r.Header.Get("Sec-WebSocket-Version") != webSocketVersion ||
+		key == "" {
+		w.Header().Set("Sec-WebSocket-Version", webSocketVersion)
+		writeError(w, http.StatusBadRequest, ErrWebSocketHandshake)
+
+		return nil, ErrWebSocketHandshake
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, ErrStreamingUnsupported)
+
+		return nil, ErrStreamingUnsupported
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return nil, ctxerrors.Wrap(err, "failed to hijack connection")
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + webSocketAccept(key) + "\r\n\r\n"
+
+	if _, err := rw.WriteString(response); err != nil {
+		_ = conn.Close()
+
+		return nil, ctxerrors.Wrap(err, "failed to write handshake")
+	}
+
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+
+		return nil, ctxerrors.Wrap(err, "failed to write handshake")
+	}
+
+	return &wsConn{
+		Conn:         conn,
+		reader:       rw.Reader,
+		writeTimeout: wsWriteTimeout,
+	}, nil
+}
+
+// webSocketAccept computes the Sec-WebSocket-Accept value for a key.
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID)) //nolint:gosec
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains reports whether a comma separated header has the token,
+// case-insensitively.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for part := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// writeJSON sends v as a text frame.
+func (c *wsConn) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to marshal frame")
+	}
+
+	return c.writeFrame(opcodeText, data)
+}
+
+// writeClose sends a close frame with the status code.
+func (c *wsConn) writeClose(code uint16) error {
+	return c.writeFrame(opcodeClose, binary.BigEndian.AppendUint16(nil, code))
+}
+
+// writeFrame sends a single unmasked, unfragmented frame. A write that
+// doesn't complete within the write timeout fails.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{finBit | opcode}
+
+	switch length := len(payload); {
+	case length < payloadLen16:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, payloadLen16)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, payloadLen64)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	deadline := time.Now().Add(c.writeTimeout)
+	if err := c.SetWriteDeadline(deadline); err != nil {
+		return ctxerrors.Wrap(err, "failed to set write deadline")
+	}
+
+	if _, err := c.Write(append(header, payload...)); err != nil {
+		return ctxerrors.Wrap(err, "failed to write frame")
+	}
+
+	return nil
+}
+
+// readLoop reads client frames, answering pings and close frames, and calls
+// cancel once the client closes the connection or it fails.
+func (c *wsConn) readLoop(cancel context.CancelFunc) {
+	defer cancel()
+
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opcodePing:
+			if err := c.writeFrame(opcodePong, payload); err != nil {
+				return
+			}
+		case opcodeClose:
+			_ = c.writeClose(closeNormal)
+
+			return
+		}
+	}
+}
+
+// readFrame reads a single masked client frame and returns its opcode and
+// unmasked payload.
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, ctxerrors.Wrap(err, "failed to read frame header")
+	}
+
+	opcode := header[0] & opcodeMask
+	masked := header[1]&maskBit != 0
+	length := uint64(header[1] & payloadLenMask)
+
+	switch length {
+	case payloadLen16:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, ctxerrors.Wrap(err, "failed to read frame length")
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case payloadLen64:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, ctxerrors.Wrap(err, "failed to read frame length")
+		}
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked || length > maxClientFrameBytes {
+		return 0, nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unexpected client frame (masked: %t, length: %d)",
+			masked, length,
+		)
+	}
+
+	var maskKey [maskKeySize]byte
+	if _, err := io.ReadFull(c.reader, maskKey[:]); err != nil {
+		return 0, nil, ctxerrors.Wrap(err, "failed to read frame mask")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, ctxerrors.Wrap(err, "failed to read frame payload")
+	}
+
+	for i := range payload {
+		payload[i] ^= maskKey[i%maskKeySize]
+	}
+
+	return opcode, payload, nil
+}
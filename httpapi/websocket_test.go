@@ -0,0 +1,298 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/gorpitx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWSKey is the sample key from RFC 6455 section 1.3.
+const (
+	testWSKey    = "dGhlIHNhbXBsZSBub25jZQ=="
+	testWSAccept = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+)
+
+// dialWebSocket performs the opening handshake against the server and
+// returns the connection and a reader positioned at the first frame.
+func dialWebSocket(t *testing.T, url string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = io.WriteString(conn, "GET /ws HTTP/1.1\r\n"+
+		"Host: localhost\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: "+testWSKey+"\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n")
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, testWSAccept, resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, reader
+}
+
+// readServerFrame reads a single unmasked server frame.
+func readServerFrame(t *testing.T, reader *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	require.NoError(t, err)
+
+	length := uint64(header[1] & payloadLenMask)
+
+	switch length {
+	case payloadLen16:
+		var ext [2]byte
+		_, err := io.ReadFull(reader, ext[:])
+		require.NoError(t, err)
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case payloadLen64:
+		var ext [8]byte
+		_, err := io.ReadFull(reader, ext[:])
+		require.NoError(t, err)
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+
+	return header[0] & opcodeMask, payload
+}
+
+// writeClientFrame writes a single masked client frame.
+func writeClientFrame(
+	t *testing.T,
+	conn net.Conn,
+	opcode byte,
+	payload []byte,
+) {
+	t.Helper()
+
+	maskKey := []byte{1, 2, 3, 4}
+	frame := []byte{finBit | opcode, maskBit | byte(len(payload))}
+	frame = append(frame, maskKey...)
+
+	for i, b := range payload {
+		frame = append(frame, b^maskKey[i%maskKeySize])
+	}
+
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func TestHandler_WebSocket_NotExecuting(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, reader := dialWebSocket(t, server.URL)
+
+	opcode, payload := readServerFrame(t, reader)
+	require.Equal(t, byte(opcodeText), opcode)
+
+	var frame wsFrame
+	require.NoError(t, json.Unmarshal(payload, &frame))
+	require.NotNil(t, frame.Status)
+	assert.False(t, frame.Status.Executing)
+
+	opcode, payload = readServerFrame(t, reader)
+	assert.Equal(t, byte(opcodeClose), opcode)
+	assert.Equal(t, uint16(closeNormal), binary.BigEndian.Uint16(payload))
+}
+
+func TestHandler_WebSocket_Stream(t *testing.T) {
+	server, rpitx := newTestServer(t)
+
+	status, _ := doRequest(
+		t,
+		http.MethodPost,
+		server.URL+"/modules/morse",
+		morseArgs,
+	)
+	require.Equal(t, http.StatusAccepted, status)
+
+	conn, reader := dialWebSocket(t, server.URL)
+
+	opcode, payload := readServerFrame(t, reader)
+	require.Equal(t, byte(opcodeText), opcode)
+
+	var frame wsFrame
+	require.NoError(t, json.Unmarshal(payload, &frame))
+	assert.Equal(t, eventStdout, frame.Stream)
+	assert.Contains(t, frame.Line, "mocking execution of morse")
+
+	// Pings are answered with the same payload
+	writeClientFrame(t, conn, opcodePing, []byte("hi"))
+
+	for {
+		opcode, payload = readServerFrame(t, reader)
+		if opcode == opcodePong {
+			break
+		}
+	}
+
+	assert.Equal(t, "hi", string(payload))
+
+	// Closing from the client is acknowledged and the module keeps running
+	writeClientFrame(t, conn, opcodeClose, nil)
+
+	for opcode != opcodeClose {
+		opcode, _ = readServerFrame(t, reader)
+	}
+
+	assert.True(t, rpitx.Status().Executing)
+}
+
+func TestHandler_WebSocket_BadHandshake(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	status, body := doRequest(t, http.MethodGet, server.URL+"/ws", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, ErrWebSocketHandshake.Error(), body["error"])
+}
+
+func TestHandler_WebSocket_CrossOrigin(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		server.URL+"/ws",
+		nil,
+	)
+	require.NoError(t, err)
+
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", testWSKey)
+	req.Header.Set("Sec-WebSocket-Version", webSocketVersion)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, ErrOriginNotAllowed.Error(), body["error"])
+}
+
+func TestHandler_OriginAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "no origin", want: true},
+		{name: "same origin", origin: "http://raspberrypi:8080", want: true},
+		{
+			name:   "same origin different case",
+			origin: "http://RaspberryPi:8080",
+			want:   true,
+		},
+		{name: "cross origin", origin: "https://evil.example.com"},
+		{name: "other port", origin: "http://raspberrypi:9090"},
+		{
+			name:    "allowed origin",
+			allowed: []string{"https://dashboard.example.com"},
+			origin:  "https://dashboard.example.com",
+			want:    true,
+		},
+		{
+			name:    "not in allowed origins",
+			allowed: []string{"https://dashboard.example.com"},
+			origin:  "https://evil.example.com",
+		},
+		{
+			name:    "any origin",
+			allowed: []string{anyOrigin},
+			origin:  "https://evil.example.com",
+			want:    true,
+		},
+		{name: "malformed origin", origin: "://"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(nil, WithAllowedOrigins(tt.allowed...))
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"http://raspberrypi:8080/ws",
+				nil,
+			)
+
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			assert.Equal(t, tt.want, h.originAllowed(req))
+		})
+	}
+}
+
+func TestWSConn_WriteTimeout(t *testing.T) {
+	// Nothing ever reads the other end, like a stalled client
+	server, client := net.Pipe()
+
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	conn := &wsConn{
+		Conn:         server,
+		reader:       bufio.NewReader(server),
+		writeTimeout: 50 * time.Millisecond,
+	}
+
+	start := time.Now()
+	err := conn.writeJSON(wsFrame{Stream: eventStdout, Line: "stuck"})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	assert.Equal(t, testWSAccept, webSocketAccept(testWSKey))
+}
+
+func TestWSFrame_JSON(t *testing.T) {
+	data, err := json.Marshal(wsFrame{Stream: eventStderr, Line: "oops"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"stream":"stderr","line":"oops"}`, string(data))
+
+	data, err = json.Marshal(wsFrame{Status: &gorpitx.ExecutionStatus{}})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"status":{`)
+}
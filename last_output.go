@@ -0,0 +1,115 @@
+package gorpitx
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/psyb0t/commander"
+)
+
+const (
+	// defaultLastOutputSize is how many stderr lines are kept by default.
+	defaultLastOutputSize = 100
+	// stderrTailGrace is how long to wait for the stderr stream to drain
+	// once the process has exited.
+	stderrTailGrace = 100 * time.Millisecond
+)
+
+// LastOutput returns the last lines written to stderr by the current or,
+// once it has finished, the most recent execution, oldest first. The lines
+// are kept after a crash so there is something to look at even when nobody
+// was streaming. Returns nil before the first execution.
+func (r *RPITX) LastOutput() []string {
+	tail := r.lastOutput.Load()
+	if tail == nil {
+		return nil
+	}
+
+	return tail.lines()
+}
+
+// lastOutputSize returns how many stderr lines to keep.
+func (r *RPITX) lastOutputSize() int {
+	if r.lastOutputLines <= 0 {
+		return defaultLastOutputSize
+	}
+
+	return r.lastOutputLines
+}
+
+// captureStderr starts collecting the stderr lines of the process, making
+// them the ones LastOutput returns.
+func (r *RPITX) captureStderr(process commander.Process) *stderrTail {
+	tail := newStderrTail(r.lastOutputSize())
+	r.lastOutput.Store(tail)
+
+	// Not through the output hub, whose kept lines are for the first user
+	// stream
+	stderr := make(chan string, tail.limit)
+	rawProcess(process).Stream(nil, stderr)
+
+	go func() {
+		defer close(tail.done)
+
+		for line := range stderr {
+			tail.add(line)
+		}
+	}()
+
+	return tail
+}
+
+// stderrTail is a ring buffer of the last lines a process wrote to stderr.
+type stderrTail struct {
+	mu    sync.Mutex
+	buf   []string
+	next  int
+	limit int
+	done  chan struct{}
+}
+
+func newStderrTail(limit int) *stderrTail {
+	return &stderrTail{
+		buf:   make([]string, 0, limit),
+		limit: limit,
+		done:  make(chan struct{}),
+	}
+}
+
+func (t *stderrTail) add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.buf) < t.limit {
+		t.buf = append(t.buf, line)
+
+		return
+	}
+
+	t.buf[t.next] = line
+	t.next = (t.next + 1) % t.limit
+}
+
+// lines returns the captured lines, oldest first.
+func (t *stderrTail) lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.buf) == 0 {
+		return nil
+	}
+
+	return slices.Concat(t.buf[t.next:], t.buf[:t.next])
+}
+
+// wait returns the captured lines once the stderr stream has ended, or
+// whatever was captured so far if it doesn't end shortly.
+func (t *stderrTail) wait() []string {
+	select {
+	case <-t.done:
+	case <-time.After(stderrTailGrace):
+	}
+
+	return t.lines()
+}
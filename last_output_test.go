@@ -0,0 +1,120 @@
+package gorpitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStderrTail(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		lines    int
+		expected []string
+	}{
+		{
+			name:     "empty",
+			limit:    3,
+			expected: nil,
+		},
+		{
+			name:     "below limit",
+			limit:    3,
+			lines:    2,
+			expected: []string{"line 0", "line 1"},
+		},
+		{
+			name:     "at limit",
+			limit:    3,
+			lines:    3,
+			expected: []string{"line 0", "line 1", "line 2"},
+		},
+		{
+			name:     "wrapped",
+			limit:    3,
+			lines:    7,
+			expected: []string{"line 4", "line 5", "line 6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tail := newStderrTail(tt.limit)
+			for i := range tt.lines {
+				tail.add(fmt.Sprintf("line %d", i))
+			}
+
+			assert.Equal(t, tt.expected, tail.lines())
+
+			// wait gives up on a stream that doesn't end
+			assert.Equal(t, tt.expected, tail.wait())
+		})
+	}
+}
+
+func TestWithLastOutputSize(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, defaultLastOutputSize, r.lastOutputSize())
+	assert.Nil(t, r.LastOutput())
+
+	r, err = New(WithLastOutputSize(5))
+	require.NoError(t, err)
+	assert.Equal(t, 5, r.lastOutputSize())
+
+	_, err = New(WithLastOutputSize(-1))
+	assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestRPITX_LastOutput(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	dir := t.TempDir()
+	// Sleep before the output so it isn't written before the capture is set
+	// up and after it so it isn't lost to the process exiting right away
+	script := "#!/bin/sh\nsleep 0.2\n" +
+		"for i in 1 2 3 4 5; do echo \"err $i\" >&2; done\n" +
+		"sleep 0.2\nexit 1\n"
+	//nolint:gosec // the fake binary must be executable
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "failing"), []byte(script), 0o755,
+	))
+
+	rpitx := &RPITX{
+		config:          Config{Path: dir},
+		commander:       commander.New(),
+		lastOutputLines: 3,
+		modules: map[ModuleName]Module{
+			"failing": &TUNE{},
+		},
+	}
+
+	args := []byte(`{"frequency": 434000000}`)
+	expected := []string{"err 3", "err 4", "err 5"}
+
+	err := rpitx.Exec(context.Background(), "failing", args, 5*time.Second)
+
+	var execErr *ExecError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, expected, execErr.Stderr)
+	assert.Equal(t, expected, rpitx.LastOutput())
+
+	_, _, err = rpitx.ExecOutput(
+		context.Background(), "failing", args, 5*time.Second,
+	)
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, expected, execErr.Stderr)
+	assert.Equal(t, expected, rpitx.LastOutput())
+}
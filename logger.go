@@ -0,0 +1,25 @@
+package gorpitx
+
+// Logger is the logging interface used by RPITX. Any logrus.FieldLogger
+// satisfies it, which is also the default, and NopLogger silences it.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// nopLogger discards every message.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...any) {}
+func (nopLogger) Infof(string, ...any)  {}
+func (nopLogger) Warnf(string, ...any)  {}
+func (nopLogger) Errorf(string, ...any) {}
+
+// NopLogger returns a Logger that discards every message.
+//
+//nolint:ireturn // Logger is an interface by design
+func NopLogger() Logger {
+	return nopLogger{}
+}
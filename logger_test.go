@@ -0,0 +1,61 @@
+package gorpitx
+
+import (
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLogger records the formats of the messages it receives.
+type recordingLogger struct {
+	messages chan string
+}
+
+func (l *recordingLogger) Debugf(format string, _ ...any) {
+	l.messages <- "debug: " + format
+}
+
+func (l *recordingLogger) Infof(format string, _ ...any) {
+	l.messages <- "info: " + format
+}
+
+func (l *recordingLogger) Warnf(format string, _ ...any) {
+	l.messages <- "warn: " + format
+}
+
+func (l *recordingLogger) Errorf(format string, _ ...any) {
+	l.messages <- "error: " + format
+}
+
+func TestNopLogger(t *testing.T) {
+	logger := NopLogger()
+
+	assert.NotPanics(t, func() {
+		logger.Debugf("debug %d", 1)
+		logger.Infof("info %d", 2)
+		logger.Warnf("warn %d", 3)
+		logger.Errorf("error %d", 4)
+	})
+}
+
+func TestRPITX_CustomLogger(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	logger := &recordingLogger{messages: make(chan string, 10)}
+
+	r, err := New(WithLogger(logger))
+	require.NoError(t, err)
+
+	stdout := make(chan string)
+	stderr := make(chan string)
+	r.StreamOutputs(stdout, stderr)
+
+	select {
+	case msg := <-logger.messages:
+		assert.Equal(t, "warn: not executing: %v", msg)
+	default:
+		t.Fatal("expected a warning on the custom logger")
+	}
+}
@@ -0,0 +1,42 @@
+package gorpitx
+
+import "time"
+
+// MetricsRecorder receives execution measurements, e.g. the collector from
+// the metrics subpackage. Its methods are called synchronously from the
+// execution path so they must be fast and must not block.
+type MetricsRecorder interface {
+	// ExecutionStarted is called once the module process has started.
+	ExecutionStarted(name ModuleName)
+
+	// ExecutionFinished is called once a started execution has finished,
+	// with how long it ran and the same error Exec returns.
+	ExecutionFinished(name ModuleName, duration time.Duration, err error)
+
+	// ExecutionFailed is called when an execution fails before its process
+	// is started, e.g. on invalid args or while busy.
+	ExecutionFailed(name ModuleName, err error)
+
+	// StopRequested is called when the running module is asked to stop.
+	StopRequested(name ModuleName)
+}
+
+// nopMetrics discards every measurement.
+type nopMetrics struct{}
+
+func (nopMetrics) ExecutionStarted(ModuleName)                        {}
+func (nopMetrics) ExecutionFinished(ModuleName, time.Duration, error) {}
+func (nopMetrics) ExecutionFailed(ModuleName, error)                  {}
+func (nopMetrics) StopRequested(ModuleName)                           {}
+
+// metrics returns the instance metrics recorder, falling back to one that
+// discards everything.
+//
+//nolint:ireturn // MetricsRecorder is an interface by design
+func (r *RPITX) metrics() MetricsRecorder {
+	if r.metricsRecorder == nil {
+		return nopMetrics{}
+	}
+
+	return r.metricsRecorder
+}
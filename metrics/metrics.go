@@ -0,0 +1,179 @@
+// Package metrics exposes gorpitx execution metrics as a Prometheus
+// collector.
+//
+//	collector := metrics.New()
+//	prometheus.MustRegister(collector)
+//
+//	rpitx, err := gorpitx.New(gorpitx.WithMetrics(collector))
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/gorpitx"
+)
+
+const (
+	namespace = "gorpitx"
+
+	labelModule    = "module"
+	labelErrorType = "type"
+)
+
+// Error types used as the type label of the errors counter.
+const (
+	ErrorTypeBusy          = "busy"
+	ErrorTypeUnknownModule = "unknown_module"
+	ErrorTypeInvalidArgs   = "invalid_args"
+	ErrorTypeNotAllowed    = "not_allowed"
+	ErrorTypeMaxDuration   = "max_duration"
+	ErrorTypeCooldown      = "cooldown"
+	ErrorTypeTimeout       = "timeout"
+	ErrorTypeTerminated    = "terminated"
+	ErrorTypeKilled        = "killed"
+	ErrorTypeExit          = "exit"
+	ErrorTypeSignal        = "signal"
+	ErrorTypeOther         = "other"
+)
+
+// durationBuckets spans single-second bursts to multi-hour broadcasts.
+var durationBuckets = prometheus.ExponentialBuckets( //nolint:gochecknoglobals
+	1, 2, 15,
+)
+
+// Collector is a prometheus.Collector reporting gorpitx executions. Pass it
+// to gorpitx.WithMetrics to have an RPITX feed it.
+type Collector struct {
+	executions *prometheus.CounterVec
+	executing  *prometheus.GaugeVec
+	duration   *prometheus.HistogramVec
+	errors     *prometheus.CounterVec
+	stops      *prometheus.CounterVec
+}
+
+var (
+	_ prometheus.Collector    = (*Collector)(nil)
+	_ gorpitx.MetricsRecorder = (*Collector)(nil)
+)
+
+// New returns a Collector with its metrics in the gorpitx namespace.
+func New() *Collector {
+	return &Collector{
+		executions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "executions_total",
+			Help:      "Total module executions started.",
+		}, []string{labelModule}),
+		executing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "executing",
+			Help:      "Whether the module is currently executing.",
+		}, []string{labelModule}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "execution_duration_seconds",
+			Help:      "How long module executions ran.",
+			Buckets:   durationBuckets,
+		}, []string{labelModule}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Total failed module executions by error type.",
+		}, []string{labelModule, labelErrorType}),
+		stops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stops_total",
+			Help:      "Total stop requests for running modules.",
+		}, []string{labelModule}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.executions.Describe(ch)
+	c.executing.Describe(ch)
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.stops.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.executions.Collect(ch)
+	c.executing.Collect(ch)
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.stops.Collect(ch)
+}
+
+// ExecutionStarted implements gorpitx.MetricsRecorder.
+func (c *Collector) ExecutionStarted(name gorpitx.ModuleName) {
+	c.executions.WithLabelValues(name).Inc()
+	c.executing.WithLabelValues(name).Set(1)
+}
+
+// ExecutionFinished implements gorpitx.MetricsRecorder.
+func (c *Collector) ExecutionFinished(
+	name gorpitx.ModuleName,
+	duration time.Duration,
+	err error,
+) {
+	c.executing.WithLabelValues(name).Set(0)
+	c.duration.WithLabelValues(name).Observe(duration.Seconds())
+
+	if err != nil {
+		c.errors.WithLabelValues(name, ErrorType(err)).Inc()
+	}
+}
+
+// ExecutionFailed implements gorpitx.MetricsRecorder.
+func (c *Collector) ExecutionFailed(name gorpitx.ModuleName, err error) {
+	c.errors.WithLabelValues(name, ErrorType(err)).Inc()
+}
+
+// StopRequested implements gorpitx.MetricsRecorder.
+func (c *Collector) StopRequested(name gorpitx.ModuleName) {
+	c.stops.WithLabelValues(name).Inc()
+}
+
+// ErrorType classifies an execution error into the value of the type label
+// of the errors counter.
+func ErrorType(err error) string {
+	var execErr *gorpitx.ExecError
+
+	switch {
+	case errors.Is(err, gorpitx.ErrExecuting):
+		return ErrorTypeBusy
+	case errors.Is(err, gorpitx.ErrUnknownModule):
+		return ErrorTypeUnknownModule
+	case errors.Is(err, gorpitx.ErrFreqNotAllowed):
+		return ErrorTypeNotAllowed
+	case errors.Is(err, gorpitx.ErrMaxDurationExceeded):
+		return ErrorTypeMaxDuration
+	case errors.Is(err, gorpitx.ErrCoolingDown):
+		return ErrorTypeCooldown
+	case errors.Is(err, commonerrors.ErrTimeout):
+		return ErrorTypeTimeout
+	case errors.Is(err, commonerrors.ErrTerminated):
+		return ErrorTypeTerminated
+	case errors.Is(err, commonerrors.ErrKilled):
+		return ErrorTypeKilled
+	case errors.As(err, &execErr) && execErr.Signal != 0:
+		return ErrorTypeSignal
+	case errors.As(err, &execErr) && execErr.ExitCode >= 0:
+		return ErrorTypeExit
+	case errors.Is(err, commonerrors.ErrInvalidValue),
+		errors.Is(err, commonerrors.ErrRequiredFieldNotSet),
+		errors.Is(err, commonerrors.ErrFileNotFound),
+		errors.Is(err, gorpitx.ErrFreqOutOfRange),
+		errors.Is(err, gorpitx.ErrFreqPrecision),
+		errors.Is(err, gorpitx.ErrPIInvalidHex),
+		errors.Is(err, gorpitx.ErrPSTooLong):
+		return ErrorTypeInvalidArgs
+	default:
+		return ErrorTypeOther
+	}
+}
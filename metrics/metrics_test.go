@@ -0,0 +1,251 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/psyb0t/gorpitx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricValue returns the value of the metric with the given name and
+// labels, or -1 when there's no such metric. Histograms report their
+// sample count.
+func metricValue(
+	t *testing.T,
+	registry *prometheus.Registry,
+	name string,
+	labels map[string]string,
+) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			if !hasLabels(metric, labels) {
+				continue
+			}
+
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	return -1
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	found := 0
+
+	for _, pair := range metric.GetLabel() {
+		if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+			found++
+		}
+	}
+
+	return found == len(labels)
+}
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"busy", gorpitx.ErrExecuting, ErrorTypeBusy},
+		{
+			"unknown module",
+			ctxerrors.Wrap(gorpitx.ErrUnknownModule, "nope"),
+			ErrorTypeUnknownModule,
+		},
+		{"not allowed", gorpitx.ErrFreqNotAllowed, ErrorTypeNotAllowed},
+		{
+			"max duration",
+			ctxerrors.Wrap(gorpitx.ErrMaxDurationExceeded, "stopped after 1m"),
+			ErrorTypeMaxDuration,
+		},
+		{
+			"cooldown",
+			ctxerrors.Wrap(gorpitx.ErrCoolingDown, "allowed in 5s"),
+			ErrorTypeCooldown,
+		},
+		{"timeout", commonerrors.ErrTimeout, ErrorTypeTimeout},
+		{
+			"terminated",
+			&gorpitx.ExecError{
+				ExitCode: -1,
+				Signal:   15,
+				Err:      commonerrors.ErrTerminated,
+			},
+			ErrorTypeTerminated,
+		},
+		{"killed", commonerrors.ErrKilled, ErrorTypeKilled},
+		{
+			"signal",
+			&gorpitx.ExecError{
+				ExitCode: -1,
+				Signal:   11,
+				Err:      errors.New("segfault"),
+			},
+			ErrorTypeSignal,
+		},
+		{
+			"exit",
+			&gorpitx.ExecError{ExitCode: 1, Err: commonerrors.ErrFailed},
+			ErrorTypeExit,
+		},
+		{
+			"invalid args",
+			ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "frequency"),
+			ErrorTypeInvalidArgs,
+		},
+		{
+			"frequency out of range",
+			gorpitx.ErrFreqOutOfRange,
+			ErrorTypeInvalidArgs,
+		},
+		{"other", errors.New("boom"), ErrorTypeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ErrorType(tt.err))
+		})
+	}
+}
+
+func TestCollector_TransmitLimits(t *testing.T) {
+	// Set ENV=dev to get mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	collector := New()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	rpitx, err := gorpitx.New(
+		gorpitx.WithMetrics(collector),
+		gorpitx.WithLogger(gorpitx.NopLogger()),
+		gorpitx.WithMaxTransmitDuration(200*time.Millisecond),
+		gorpitx.WithTransmitCooldown(time.Hour),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	args := []byte(`{"frequency": 14070000, "rate": 20, "message": "CQ"}`)
+
+	err = rpitx.Exec(ctx, gorpitx.ModuleNameMORSE, args, gorpitx.NoTimeout)
+	require.ErrorIs(t, err, gorpitx.ErrMaxDurationExceeded)
+
+	err = rpitx.Exec(ctx, gorpitx.ModuleNameMORSE, args, gorpitx.NoTimeout)
+	require.ErrorIs(t, err, gorpitx.ErrCoolingDown)
+
+	for _, errorType := range []string{
+		ErrorTypeMaxDuration,
+		ErrorTypeCooldown,
+	} {
+		assert.InDelta(t, 1, metricValue(t, registry, "gorpitx_errors_total",
+			map[string]string{
+				"module": gorpitx.ModuleNameMORSE,
+				"type":   errorType,
+			}), 0, errorType)
+	}
+}
+
+func TestCollector(t *testing.T) {
+	// Set ENV=dev to get mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	collector := New()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	rpitx, err := gorpitx.New(
+		gorpitx.WithMetrics(collector),
+		gorpitx.WithLogger(gorpitx.NopLogger()),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	morse := map[string]string{"module": gorpitx.ModuleNameMORSE}
+	args := []byte(`{"frequency": 14070000, "rate": 20, "message": "CQ"}`)
+
+	// Timed out execution
+	err = rpitx.Exec(ctx, gorpitx.ModuleNameMORSE, args, 200*time.Millisecond)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	assert.InDelta(t, 1, metricValue(t, registry,
+		"gorpitx_executions_total", morse), 0)
+	assert.InDelta(t, 0, metricValue(t, registry,
+		"gorpitx_executing", morse), 0)
+	assert.InDelta(t, 1, metricValue(t, registry,
+		"gorpitx_execution_duration_seconds", morse), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "gorpitx_errors_total",
+		map[string]string{
+			"module": gorpitx.ModuleNameMORSE,
+			"type":   ErrorTypeTimeout,
+		}), 0)
+
+	// Invalid args never start
+	err = rpitx.Exec(
+		ctx, gorpitx.ModuleNameMORSE, []byte(`{"frequency": 1}`), 0,
+	)
+	require.Error(t, err)
+
+	assert.InDelta(t, 1, metricValue(t, registry,
+		"gorpitx_executions_total", morse), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "gorpitx_errors_total",
+		map[string]string{
+			"module": gorpitx.ModuleNameMORSE,
+			"type":   ErrorTypeInvalidArgs,
+		}), 0)
+
+	// Stopped execution
+	execution, err := rpitx.ExecAsync(ctx, gorpitx.ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1, metricValue(t, registry,
+		"gorpitx_executing", morse), 0)
+
+	_, err = rpitx.ExecAsync(ctx, gorpitx.ModuleNameTUNE, args, 0)
+	require.ErrorIs(t, err, gorpitx.ErrExecuting)
+
+	_ = rpitx.Stop(ctx)
+	_ = execution.Wait()
+
+	assert.InDelta(t, 2, metricValue(t, registry,
+		"gorpitx_executions_total", morse), 0)
+	assert.InDelta(t, 0, metricValue(t, registry,
+		"gorpitx_executing", morse), 0)
+	assert.InDelta(t, 1, metricValue(t, registry,
+		"gorpitx_stops_total", morse), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "gorpitx_errors_total",
+		map[string]string{
+			"module": gorpitx.ModuleNameMORSE,
+			"type":   ErrorTypeTerminated,
+		}), 0)
+	assert.InDelta(t, 1, metricValue(t, registry, "gorpitx_errors_total",
+		map[string]string{
+			"module": gorpitx.ModuleNameTUNE,
+			"type":   ErrorTypeBusy,
+		}), 0)
+}
@@ -0,0 +1,100 @@
+package gorpitx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMockLine     = "mocking execution of {module} {args}..."
+	defaultMockInterval = time.Second
+)
+
+// MockConfig controls the process run instead of the real binary in dev
+// mode. The zero value echoes a status line every second until stopped.
+type MockConfig struct {
+	// Line is echoed on every iteration, with {module} and {args} replaced
+	// by the module name and its command-line args. Defaults to
+	// "mocking execution of {module} {args}...".
+	Line string
+	// Interval is the time between two lines, 1s by default.
+	Interval time.Duration
+	// Iterations makes the mock exit successfully after echoing that many
+	// lines, e.g. to simulate a module that completes. Zero runs until
+	// stopped.
+	Iterations int
+}
+
+// getMockExecCmd returns mock command and args for dev environment execution.
+// When the module feeds the process a stdin, its lines are echoed first so
+// the payload can be checked without the real binary.
+func (r *RPITX) getMockExecCmd(
+	name ModuleName,
+	args []string,
+	hasStdin bool,
+) (string, []string) {
+	r.log().Debugf(
+		"preparing mock execution of module %s with args %s", name, args,
+	)
+
+	mock := r.config.Mock
+
+	line := mock.Line
+	if line == "" {
+		line = defaultMockLine
+	}
+
+	line = strings.NewReplacer(
+		"{module}", name,
+		"{args}", strings.Join(args, " "),
+	).Replace(line)
+
+	interval := mock.Interval
+	if interval <= 0 {
+		interval = defaultMockInterval
+	}
+
+	var mockCmd string
+
+	if hasStdin {
+		mockCmd = `
+		while IFS= read -r line || [ -n "$line" ]; do
+			echo "mocking stdin: $line"
+		done`
+	}
+
+	loop := "while true; do"
+	if mock.Iterations > 0 {
+		loop = fmt.Sprintf(
+			"i=0; while [ $i -lt %d ]; do i=$((i+1))", mock.Iterations,
+		)
+	}
+
+	// Build the mock command that echoes on every interval
+	mockCmd += fmt.Sprintf(`
+		%s
+			echo "%s"
+			sleep %s
+		done
+	`,
+		loop,
+		escapeDoubleQuoted(line),
+		strconv.FormatFloat(interval.Seconds(), 'f', -1, 64),
+	)
+
+	// Return shell command and args
+	return "sh", []string{"-c", mockCmd}
+}
+
+// escapeDoubleQuoted escapes the characters the shell still interprets
+// inside double quotes.
+func escapeDoubleQuoted(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"$", `\$`,
+		"`", "\\`",
+	).Replace(s)
+}
@@ -0,0 +1,115 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_getMockExecCmd_Config(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     MockConfig
+		contains []string
+	}{
+		{
+			name: "defaults",
+			contains: []string{
+				"while true; do",
+				`echo "mocking execution of tune -f 434000000..."`,
+				"sleep 1\n",
+			},
+		},
+		{
+			name: "custom line and interval",
+			mock: MockConfig{
+				Line:     "TX {module} [{args}]",
+				Interval: 250 * time.Millisecond,
+			},
+			contains: []string{
+				"while true; do",
+				`echo "TX tune [-f 434000000]"`,
+				"sleep 0.25\n",
+			},
+		},
+		{
+			name: "iterations",
+			mock: MockConfig{Iterations: 3},
+			contains: []string{
+				"i=0; while [ $i -lt 3 ]; do i=$((i+1))",
+			},
+		},
+		{
+			name: "line is escaped",
+			mock: MockConfig{Line: "$HOME \"q\" `id` \\"},
+			contains: []string{
+				"echo \"\\$HOME \\\"q\\\" \\`id\\` \\\\\"",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{config: Config{Mock: tt.mock}}
+
+			cmdName, cmdArgs := rpitx.getMockExecCmd(
+				ModuleNameTUNE, []string{"-f", "434000000"}, false,
+			)
+			assert.Equal(t, "sh", cmdName)
+			require.Len(t, cmdArgs, 2)
+
+			for _, s := range tt.contains {
+				assert.Contains(t, cmdArgs[1], s)
+			}
+		})
+	}
+}
+
+func TestRPITX_Mock_Execution(t *testing.T) {
+	// Set ENV=dev to test mock execution
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx, err := New(
+		WithLogger(NopLogger()),
+		WithMock(MockConfig{
+			Line:       `{module} says "$hi"`,
+			Interval:   10 * time.Millisecond,
+			Iterations: 3,
+		}),
+	)
+	require.NoError(t, err)
+
+	args, err := json.Marshal(map[string]any{"frequency": 434000000})
+	require.NoError(t, err)
+
+	// The mock completes on its own
+	start := time.Now()
+	stdout, _, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameTUNE, args, 5*time.Second,
+	)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t,
+		strings.Repeat("tune says \"$hi\"\n", 3), string(stdout))
+
+	require.NoError(t, rpitx.Exec(
+		context.Background(), ModuleNameTUNE, args, 5*time.Second,
+	))
+}
+
+func TestWithMock_Invalid(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	_, err := New(WithMock(MockConfig{Interval: -time.Second}))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	_, err = New(WithMock(MockConfig{Iterations: -1}))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
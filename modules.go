@@ -0,0 +1,237 @@
+package gorpitx
+
+import (
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// ModuleInfo describes a registered module, e.g. to build a UI around it.
+type ModuleInfo struct {
+	// Name is the name the module is registered under.
+	Name ModuleName `json:"name"`
+
+	// Description is a one-line summary of what the module transmits. It is
+	// empty for custom modules.
+	Description string `json:"description,omitempty"`
+
+	// UsesStdin is true when the module feeds its binary through stdin, e.g.
+	// the POCSAG messages.
+	UsesStdin bool `json:"usesStdin"`
+
+	// UsesScript is true when the module runs an embedded script instead of
+	// an rpitx binary.
+	UsesScript bool `json:"usesScript"`
+}
+
+// AllModuleNames returns the names of the built-in modules sorted
+// alphabetically, without needing an RPITX. Modules added with
+// RegisterModule are not included, see GetSupportedModules for those.
+func AllModuleNames() []ModuleName {
+	names := make([]ModuleName, 0, len(moduleDescriptions))
+	for name := range moduleDescriptions {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// ParseModuleName returns the built-in module named s, ignoring case and
+// surrounding spaces, e.g. to validate a module from a CLI flag or request
+// before it reaches Exec. Returns ErrUnknownModule for any other name.
+func ParseModuleName(s string) (ModuleName, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if _, ok := moduleDescriptions[name]; !ok {
+		return "", ctxerrors.Wrapf(ErrUnknownModule, "%q", s)
+	}
+
+	return name, nil
+}
+
+// stdinModule is implemented by modules that feed their binary through
+// stdin.
+type stdinModule interface {
+	usesStdin() bool
+}
+
+// readerInput is implemented by modules whose stdin can be the reader
+// given to the execution with WithExecReader instead of data from the args,
+// e.g. FSK with InputTypeReader. setReader is called on the instance of the
+// execution before its args are parsed, and readsReader reports whether the
+// parsed args use it.
+type readerInput interface {
+	setReader(reader io.Reader)
+	readsReader() bool
+}
+
+// moduleDescriptions summarizes the built-in modules.
+//
+//nolint:gochecknoglobals
+var moduleDescriptions = map[ModuleName]string{
+	ModuleNamePIFMRDS:            "FM broadcasting with RDS data",
+	ModuleNameTUNE:               "Simple carrier wave",
+	ModuleNameMORSE:              "Morse code",
+	ModuleNameSPECTRUMPAINT:      "Picture painted on the spectrum",
+	ModuleNamePICHIRP:            "Carrier frequency sweep",
+	ModuleNamePOCSAG:             "POCSAG pager messages",
+	ModuleNameFT8:                "FT8 digital mode",
+	ModuleNamePISSSTV:            "Slow Scan Television picture",
+	ModuleNamePIRTTY:             "RTTY (Radio Teletype) text",
+	ModuleNameFSK:                "FSK text through minimodem",
+	ModuleNameAudioSockBroadcast: "Audio streamed from a unix socket",
+	ModuleNameSENDIQ:             "Pre-generated IQ file",
+	ModuleNamePIDTMF:             "DTMF tone sequence",
+	ModuleNameSSB:                "SSB voice from a WAV file",
+}
+
+// GetModuleInfo describes the module registered under the given name. The
+// second result is false when there's no such module.
+func (r *RPITX) GetModuleInfo(name ModuleName) (ModuleInfo, bool) {
+	m, ok := r.module(name)
+	if !ok {
+		return ModuleInfo{}, false
+	}
+
+	return ModuleInfo{
+		Name:        name,
+		Description: moduleDescriptions[name],
+		UsesStdin:   moduleUsesStdin(m),
+		UsesScript:  r.IsScriptModule(name),
+	}, true
+}
+
+// ModuleUsesStdin reports whether the module registered under the given
+// name feeds its binary through stdin, e.g. POCSAG messages, so a front-end
+// knows to offer a message body rather than argument fields only. It is
+// false for unknown and custom modules. It complements IsScriptModule.
+func (r *RPITX) ModuleUsesStdin(name ModuleName) bool {
+	m, ok := r.module(name)
+	if !ok {
+		return false
+	}
+
+	return moduleUsesStdin(m)
+}
+
+// moduleUsesStdin reports whether the module implementation feeds its
+// binary through stdin.
+func moduleUsesStdin(m Module) bool {
+	stdin, ok := m.(stdinModule)
+
+	return ok && stdin.usesStdin()
+}
+
+// RegisterModule adds a custom module under the given name. The module's
+// binary is expected at Config.Path/name. Registration is rejected with
+// ErrExecuting while a module is running and with ErrModuleExists when the
+// name is already taken.
+func (r *RPITX) RegisterModule(name ModuleName, m Module) error {
+	if strings.TrimSpace(name) == "" {
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "name")
+	}
+
+	if m == nil {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"module %s cannot be nil",
+			name,
+		)
+	}
+
+	if !r.isExecuting.CompareAndSwap(false, true) {
+		return ErrExecuting
+	}
+
+	defer r.isExecuting.Store(false)
+
+	r.modulesMu.Lock()
+	defer r.modulesMu.Unlock()
+
+	if _, exists := r.modules[name]; exists {
+		return ctxerrors.Wrap(ErrModuleExists, name)
+	}
+
+	if r.modules == nil {
+		r.modules = map[ModuleName]Module{}
+	}
+
+	r.modules[name] = m
+
+	return nil
+}
+
+// UnregisterModule removes a module. Returns ErrExecuting while a module is
+// running and ErrUnknownModule when no module is registered under the name.
+func (r *RPITX) UnregisterModule(name ModuleName) error {
+	if !r.isExecuting.CompareAndSwap(false, true) {
+		return ErrExecuting
+	}
+
+	defer r.isExecuting.Store(false)
+
+	r.modulesMu.Lock()
+	defer r.modulesMu.Unlock()
+
+	if _, exists := r.modules[name]; !exists {
+		return ctxerrors.Wrap(ErrUnknownModule, name)
+	}
+
+	delete(r.modules, name)
+
+	return nil
+}
+
+// module returns the module registered under the given name.
+//
+//nolint:ireturn // Module is an interface by design
+func (r *RPITX) module(name ModuleName) (Module, bool) {
+	r.modulesMu.RLock()
+	defer r.modulesMu.RUnlock()
+
+	m, ok := r.modules[name]
+
+	return m, ok
+}
+
+// newModule returns a fresh instance of the module registered under the
+// given name to parse args into. The registered module is only a template,
+// so validations, previews and the running execution never share the
+// fields they parse.
+//
+//nolint:ireturn // Module is an interface by design
+func (r *RPITX) newModule(name ModuleName) (Module, error) {
+	template, ok := r.module(name)
+	if !ok {
+		return nil, ctxerrors.Wrap(ErrUnknownModule, name)
+	}
+
+	return cloneModule(template), nil
+}
+
+// cloneModule returns a shallow copy of a module that is a pointer to a
+// struct, keeping what was set on it outside of the args. Other modules can't be parsed into and are returned as they are.
+//
+//nolint:ireturn // Module is an interface by design
+func cloneModule(template Module) Module {
+	value := reflect.ValueOf(template)
+	if value.Kind() != reflect.Pointer || value.IsNil() ||
+		value.Elem().Kind() != reflect.Struct {
+		return template
+	}
+
+	clone := reflect.New(value.Elem().Type())
+	clone.Elem().Set(value.Elem())
+
+	module, ok := clone.Interface().(Module)
+	if !ok {
+		return template
+	}
+
+	return module
+}
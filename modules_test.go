@@ -0,0 +1,244 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type customModule struct {
+	args []string
+}
+
+func (m *customModule) ParseArgs(
+	_ json.RawMessage,
+) ([]string, io.Reader, error) {
+	return m.args, nil, nil
+}
+
+func TestAllModuleNames(t *testing.T) {
+	r := &RPITX{modules: defaultModules()}
+
+	assert.Equal(t, r.GetSupportedModules(), AllModuleNames())
+}
+
+func TestParseModuleName(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    ModuleName
+		expectedErr error
+	}{
+		{
+			name:     "exact name",
+			input:    "pifmrds",
+			expected: ModuleNamePIFMRDS,
+		},
+		{
+			name:     "case and spaces ignored",
+			input:    " Tune\n",
+			expected: ModuleNameTUNE,
+		},
+		{
+			name:        "typo",
+			input:       "pifm",
+			expectedErr: ErrUnknownModule,
+		},
+		{
+			name:        "empty",
+			input:       "",
+			expectedErr: ErrUnknownModule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := ParseModuleName(tt.input)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, name)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
+
+func TestRPITX_RegisterModule(t *testing.T) {
+	rpitx := &RPITX{}
+
+	err := rpitx.RegisterModule("custom", &customModule{})
+	require.NoError(t, err)
+	assert.True(t, rpitx.IsSupportedModule("custom"))
+	assert.Contains(t, rpitx.GetSupportedModules(), "custom")
+	assert.False(t, rpitx.isExecuting.Load())
+
+	err = rpitx.RegisterModule("custom", &customModule{})
+	require.ErrorIs(t, err, ErrModuleExists)
+
+	err = rpitx.RegisterModule(" ", &customModule{})
+	require.ErrorIs(t, err, commonerrors.ErrRequiredFieldNotSet)
+
+	err = rpitx.RegisterModule("other", nil)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	rpitx.isExecuting.Store(true)
+
+	err = rpitx.RegisterModule("other", &customModule{})
+	require.ErrorIs(t, err, ErrExecuting)
+	assert.False(t, rpitx.IsSupportedModule("other"))
+}
+
+func TestRPITX_UnregisterModule(t *testing.T) {
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE: &TUNE{},
+		},
+	}
+
+	rpitx.isExecuting.Store(true)
+	require.ErrorIs(t, rpitx.UnregisterModule(ModuleNameTUNE), ErrExecuting)
+	assert.True(t, rpitx.IsSupportedModule(ModuleNameTUNE))
+
+	rpitx.isExecuting.Store(false)
+	require.NoError(t, rpitx.UnregisterModule(ModuleNameTUNE))
+	assert.False(t, rpitx.IsSupportedModule(ModuleNameTUNE))
+	assert.False(t, rpitx.isExecuting.Load())
+
+	require.ErrorIs(t, rpitx.UnregisterModule(ModuleNameTUNE), ErrUnknownModule)
+}
+
+func TestRPITX_GetModuleInfo(t *testing.T) {
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:               &TUNE{},
+			ModuleNamePOCSAG:             &POCSAG{},
+			ModuleNameAudioSockBroadcast: &AudioSockBroadcast{},
+			"custom":                     &customModule{},
+		},
+	}
+
+	tests := []struct {
+		name     ModuleName
+		expected ModuleInfo
+		found    bool
+	}{
+		{
+			name: ModuleNameTUNE,
+			expected: ModuleInfo{
+				Name:        ModuleNameTUNE,
+				Description: "Simple carrier wave",
+			},
+			found: true,
+		},
+		{
+			name: ModuleNamePOCSAG,
+			expected: ModuleInfo{
+				Name:        ModuleNamePOCSAG,
+				Description: "POCSAG pager messages",
+				UsesStdin:   true,
+			},
+			found: true,
+		},
+		{
+			name: ModuleNameAudioSockBroadcast,
+			expected: ModuleInfo{
+				Name:        ModuleNameAudioSockBroadcast,
+				Description: "Audio streamed from a unix socket",
+				UsesScript:  true,
+			},
+			found: true,
+		},
+		{
+			name:     "custom",
+			expected: ModuleInfo{Name: "custom"},
+			found:    true,
+		},
+		{
+			name: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, found := rpitx.GetModuleInfo(tt.name)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, info)
+		})
+	}
+}
+
+func TestRPITX_Exec_RegisteredModule(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		config:    Config{Path: "/opt/rpitx"},
+		commander: mockCommander,
+	}
+
+	err := rpitx.RegisterModule("mytx", &customModule{
+		args: []string{"-f", "434000000"},
+	})
+	require.NoError(t, err)
+
+	mockCommander.Expect(
+		"stdbuf", "-oL", "/opt/rpitx/mytx", "-f", "434000000",
+	).ReturnError(nil)
+
+	err = rpitx.Exec(context.Background(), "mytx", []byte(`{}`), 0)
+	require.NoError(t, err)
+	assert.NoError(t, mockCommander.VerifyExpectations())
+}
+
+func TestCloneModule(t *testing.T) {
+	fsk := &FSK{Preset: FSKPresetRTTY45}
+
+	clone, ok := cloneModule(fsk).(*FSK)
+	require.True(t, ok)
+	assert.NotSame(t, fsk, clone)
+	assert.Equal(t, FSKPresetRTTY45, clone.Preset)
+
+	_, _, err := clone.ParseArgs([]byte(
+		`{"inputType": "text", "text": "CQ", "frequency": 144500000}`,
+	))
+	require.NoError(t, err)
+	assert.Empty(t, fsk.InputType)
+
+	// Modules that aren't pointers to a struct are used as they are
+	module := valueModule{}
+	assert.Equal(t, module, cloneModule(module))
+}
+
+type valueModule struct{}
+
+func (valueModule) ParseArgs(json.RawMessage) ([]string, io.Reader, error) {
+	return nil, nil, nil
+}
+
+func TestRPITX_ModuleUsesStdin(t *testing.T) {
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:   &TUNE{},
+			ModuleNamePOCSAG: &POCSAG{},
+			ModuleNameFSK:    &FSK{},
+			"custom":         &customModule{},
+		},
+	}
+
+	assert.False(t, rpitx.ModuleUsesStdin(ModuleNameTUNE))
+	assert.True(t, rpitx.ModuleUsesStdin(ModuleNamePOCSAG))
+	assert.True(t, rpitx.ModuleUsesStdin(ModuleNameFSK))
+	assert.False(t, rpitx.ModuleUsesStdin("custom"))
+	assert.False(t, rpitx.ModuleUsesStdin("unknown"))
+}
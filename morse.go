@@ -5,6 +5,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
@@ -12,16 +13,41 @@ import (
 
 const (
 	ModuleNameMORSE ModuleName = "morse"
+
+	// Morse timing in dits: a dah is 3, and the gaps are 1 between the
+	// elements of a character, 3 between characters and 7 between words
+	morseDahUnits        = 3
+	morseCharGapUnits    = 3
+	morseWordGapUnits    = 7
+	morseElementGapUnits = 1
 )
 
+// morseCode maps the characters morse can send to their dits and dahs.
+//
+//nolint:gochecknoglobals
+var morseCode = map[rune]string{
+	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".",
+	'F': "..-.", 'G': "--.", 'H': "....", 'I': "..", 'J': ".---",
+	'K': "-.-", 'L': ".-..", 'M': "--", 'N': "-.", 'O': "---",
+	'P': ".--.", 'Q': "--.-", 'R': ".-.", 'S': "...", 'T': "-",
+	'U': "..-", 'V': "...-", 'W': ".--", 'X': "-..-", 'Y': "-.--",
+	'Z': "--..",
+	'0': "-----", '1': ".----", '2': "..---", '3': "...--", '4': "....-",
+	'5': ".....", '6': "-....", '7': "--...", '8': "---..", '9': "----.",
+	'.': ".-.-.-", ',': "--..--", '?': "..--..", '/': "-..-.",
+	'=': "-...-", '+': ".-.-.", '-': "-....-", '@': ".--.-.",
+	'\'': ".----.", '!': "-.-.--", '(': "-.--.", ')': "-.--.-",
+	':': "---...", ';': "-.-.-.", '"': ".-..-.", '&': ".-...",
+}
+
 type MORSE struct {
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// Rate specifies the transmission rate in dits per minute. Required parameter.
 	// Must be positive integer value.
-	Rate int `json:"rate"`
+	Rate int `json:"rate" schema:"exclusiveMinimum=0"`
 
 	// Message specifies the text message to transmit in Morse code. Required
 	// parameter.
@@ -61,19 +87,72 @@ func (m *MORSE) buildArgs() []string {
 
 // validate validates all MORSE parameters.
 func (m *MORSE) validate() error {
-	if err := m.validateFrequency(); err != nil {
-		return err
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"rate", m.validateRate},
+		fieldCheck{"message", m.validateMessage},
+	)
+}
+
+// estimatedDuration returns how long the message takes to key at Rate
+// dits per minute. Characters without a Morse code are skipped.
+func (m *MORSE) estimatedDuration() (time.Duration, bool) {
+	if m.Rate <= 0 {
+		return 0, false
 	}
 
-	if err := m.validateRate(); err != nil {
-		return err
+	units := morseUnits(m.Message)
+
+	return time.Duration(units) * time.Minute / time.Duration(m.Rate), true
+}
+
+// morseUnits returns the length of the message in dits, gaps included.
+func morseUnits(message string) int {
+	units := 0
+
+	for i, word := range strings.Fields(strings.ToUpper(message)) {
+		if i > 0 {
+			units += morseWordGapUnits
+		}
+
+		chars := 0
+
+		for _, char := range word {
+			code, ok := morseCode[char]
+			if !ok {
+				continue
+			}
+
+			if chars > 0 {
+				units += morseCharGapUnits
+			}
+
+			chars++
+			units += morseCharUnits(code)
+		}
 	}
 
-	if err := m.validateMessage(); err != nil {
-		return err
+	return units
+}
+
+// morseCharUnits returns the length of a character code in dits.
+func morseCharUnits(code string) int {
+	units := (len(code) - 1) * morseElementGapUnits
+
+	for _, element := range code {
+		if element == '-' {
+			units += morseDahUnits
+		} else {
+			units++
+		}
 	}
 
-	return nil
+	return units
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *MORSE) frequencyHz() float64 {
+	return m.Frequency
 }
 
 // validateFrequency validates the frequency parameter.
@@ -91,7 +170,7 @@ func (m *MORSE) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -3,6 +3,7 @@ package gorpitx
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
@@ -421,3 +422,44 @@ func TestMORSE_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestMORSE_EstimatedDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		morse      MORSE
+		expected   time.Duration
+		expectedOK bool
+	}{
+		{
+			// PARIS is 50 dits with the trailing word gap of 7
+			name:       "paris",
+			morse:      MORSE{Rate: 60, Message: "PARIS"},
+			expected:   43 * time.Second,
+			expectedOK: true,
+		},
+		{
+			name:       "words and lower case",
+			morse:      MORSE{Rate: 60, Message: "e  e"},
+			expected:   9 * time.Second,
+			expectedOK: true,
+		},
+		{
+			name:       "unknown characters are skipped",
+			morse:      MORSE{Rate: 120, Message: "S#O#S"},
+			expected:   27 * time.Second / 2,
+			expectedOK: true,
+		},
+		{
+			name:  "no rate",
+			morse: MORSE{Message: "SOS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := tt.morse.estimatedDuration()
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
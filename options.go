@@ -0,0 +1,387 @@
+package gorpitx
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures an RPITX built with New.
+type Option func(*RPITX)
+
+// WithPath sets the directory holding the rpitx binaries, overriding the
+// GORPITX_PATH environment variable.
+func WithPath(path string) Option {
+	return func(r *RPITX) {
+		r.config.Path = path
+	}
+}
+
+// WithConfig replaces the configuration parsed from the environment. An
+// empty Path keeps the one from the environment. Options after it still
+// apply on top, e.g. WithPath.
+func WithConfig(config Config) Option {
+	return func(r *RPITX) {
+		path := r.config.Path
+		r.config = config
+
+		if r.config.Path == "" {
+			r.config.Path = path
+		}
+	}
+}
+
+// WithConfigFile loads a JSON or YAML config file in the format of
+// LoadConfig. Only the fields set in the file are applied, the rest of the
+// configuration, including what earlier options like WithMock or WithPath
+// set, is kept. New fails if the file can't be loaded.
+func WithConfigFile(path string) Option {
+	return func(r *RPITX) {
+		config := r.config.clone()
+		if err := config.loadFile(path); err != nil {
+			r.optionErr = errors.Join(r.optionErr, err)
+
+			return
+		}
+
+		r.config = config
+	}
+}
+
+// WithMock configures the process run instead of the binaries in dev mode,
+// e.g. to stream faster in tests or to simulate a module that completes.
+func WithMock(mock MockConfig) Option {
+	return func(r *RPITX) {
+		r.config.Mock = mock
+	}
+}
+
+// WithCommander sets the commander used to run the module processes, e.g. a
+// mock in tests.
+func WithCommander(cmdr commander.Commander) Option {
+	return func(r *RPITX) {
+		r.commander = cmdr
+	}
+}
+
+// WithModules replaces the default set of modules. The map is copied so later
+// changes to it don't affect the instance.
+func WithModules(modules map[ModuleName]Module) Option {
+	return func(r *RPITX) {
+		r.modules = maps.Clone(modules)
+		if r.modules == nil {
+			r.modules = map[ModuleName]Module{}
+		}
+	}
+}
+
+// WithLogger sets the logger used by the instance instead of the global
+// logrus logger. Pass NopLogger() to silence it.
+func WithLogger(logger Logger) Option {
+	return func(r *RPITX) {
+		r.logger = logger
+	}
+}
+
+// WithHamBandWarnings enables a warning, logged before each execution, when
+// the module frequency is outside the ham bands of the given IARU region.
+// The warning is advisory and never blocks the transmission.
+func WithHamBandWarnings(region Region) Option {
+	return func(r *RPITX) {
+		r.hamBandRegion = region
+	}
+}
+
+// WithHarmonicWarnings enables a warning, logged before each execution, for
+// every harmonic of the module frequency that lands in a sensitive band such
+// as aviation, GNSS or cellular. The warning never blocks the transmission.
+func WithHarmonicWarnings() Option {
+	return func(r *RPITX) {
+		r.harmonicWarnings = true
+	}
+}
+
+// WithFrequencyPolicy restricts the frequencies the instance may transmit
+// on. Executions on a forbidden frequency fail with ErrFreqNotAllowed before
+// anything is started.
+func WithFrequencyPolicy(policy FrequencyPolicy) Option {
+	return func(r *RPITX) {
+		r.frequencyPolicy = &policy
+	}
+}
+
+// WithLastOutputSize sets how many stderr lines are kept for LastOutput and
+// ExecError, 100 by default.
+func WithLastOutputSize(lines int) Option {
+	return func(r *RPITX) {
+		r.lastOutputLines = lines
+	}
+}
+
+// WithStreamBuffer sets how many lines of each output stream are buffered
+// for a consumer, 100 by default. A consumer can fall that many lines behind
+// without slowing the module down or losing output, e.g. a logger that
+// briefly stalls on a disk write. Further behind, the oldest buffered lines
+// are dropped with a warning, and the stream goes on.
+func WithStreamBuffer(lines int) Option {
+	return func(r *RPITX) {
+		r.streamBuffer = lines
+	}
+}
+
+// WithAutoRestart relaunches the module with the same args when its process
+// exits unexpectedly, waiting backoff before each attempt, e.g. to keep an
+// unattended beacon on the air. Stops, timeouts and successful exits are
+// never restarted. After maxRetries restarts the last error is returned.
+func WithAutoRestart(maxRetries int, backoff time.Duration) Option {
+	return func(r *RPITX) {
+		r.autoRestartRetries = maxRetries
+		r.autoRestartBackoff = backoff
+	}
+}
+
+// WithMaxTransmitDuration caps every execution at d whatever timeout the
+// caller passes, NoTimeout included, so a buggy caller can't leave the
+// transmitter keyed. Executions cut short by the cap are stopped like on a
+// timeout and fail with ErrMaxDurationExceeded. Zero disables the cap.
+func WithMaxTransmitDuration(d time.Duration) Option {
+	return func(r *RPITX) {
+		r.maxTransmitDuration = d
+	}
+}
+
+// WithTransmitCooldown makes executions started less than d after the last
+// transmission ended fail with ErrCoolingDown, e.g. to respect the duty
+// cycle of an amplifier. Queued jobs wait for the cooldown instead.
+func WithTransmitCooldown(d time.Duration) Option {
+	return func(r *RPITX) {
+		r.transmitCooldown = d
+	}
+}
+
+// WithTransmissionLogFile appends every logged transmission to the file as
+// a JSON line, e.g. to keep the station log some licenses require. The file
+// is created if needed and New fails if it can't be opened for appending.
+func WithTransmissionLogFile(path string) Option {
+	return func(r *RPITX) {
+		r.transmissions.file = path
+	}
+}
+
+// WithMetrics reports execution measurements to the recorder, e.g. the
+// Prometheus collector from the metrics subpackage.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(r *RPITX) {
+		r.metricsRecorder = recorder
+	}
+}
+
+// WithBinaryCheck has New run CheckBinaries and log a warning for every
+// module whose binary is missing or not executable, so a partial rpitx
+// build shows up at startup rather than when a module is first run.
+func WithBinaryCheck() Option {
+	return func(r *RPITX) {
+		r.binaryCheck = true
+	}
+}
+
+// WithRequireRoot controls the root check New does in production mode, on
+// by default. Pass false when the process is granted the access rpitx needs,
+// e.g. CAP_SYS_RAWIO, without running as uid 0.
+func WithRequireRoot(require bool) Option {
+	return func(r *RPITX) {
+		r.skipRootCheck = !require
+	}
+}
+
+// WithDryRun runs the dev mode mock instead of the rpitx binaries whatever
+// the environment, so the whole Exec, stream and stop lifecycle can be
+// exercised on a production box without keying a radio. Args are still
+// parsed and validated, and root isn't required.
+func WithDryRun() Option {
+	return func(r *RPITX) {
+		r.dryRun = true
+	}
+}
+
+// New returns a new RPITX configured from the environment and then from the
+// given options. Unlike GetInstance it can be called any number of times,
+// e.g. to run several configurations in one process.
+func New(opts ...Option) (*RPITX, error) {
+	config, err := parseConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RPITX{
+		config:    config,
+		commander: commander.New(),
+		modules:   defaultModules(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.optionErr != nil {
+		return nil, r.optionErr
+	}
+
+	if err := r.config.validate(); err != nil {
+		return nil, ctxerrors.Wrap(err, "invalid config")
+	}
+
+	if r.frequencyPolicy == nil {
+		r.frequencyPolicy = r.config.FrequencyPolicy
+	}
+
+	if r.frequencyPolicy != nil {
+		if err := r.frequencyPolicy.validate(); err != nil {
+			return nil, ctxerrors.Wrap(err, "invalid frequency policy")
+		}
+	}
+
+	if r.logger == nil && r.config.LogLevel != "" {
+		level, _ := logrus.ParseLevel(r.config.LogLevel)
+		logger := logrus.New()
+		logger.SetLevel(level)
+		r.logger = logger
+	}
+
+	if r.config.Mock.Interval < 0 || r.config.Mock.Iterations < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"mock interval and iterations must not be negative, "+
+				"got %s and %d",
+			r.config.Mock.Interval, r.config.Mock.Iterations,
+		)
+	}
+
+	if r.lastOutputLines < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"last output size must not be negative, got %d",
+			r.lastOutputLines,
+		)
+	}
+
+	if r.streamBuffer < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"stream buffer must not be negative, got %d",
+			r.streamBuffer,
+		)
+	}
+
+	if r.autoRestartRetries < 0 || r.autoRestartBackoff < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"auto restart retries and backoff must not be negative, "+
+				"got %d and %s",
+			r.autoRestartRetries, r.autoRestartBackoff,
+		)
+	}
+
+	if r.maxTransmitDuration < 0 || r.transmitCooldown < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"max transmit duration and cooldown must not be negative, "+
+				"got %s and %s",
+			r.maxTransmitDuration, r.transmitCooldown,
+		)
+	}
+
+	if r.transmissions.file != "" {
+		if err := checkTransmissionLogFile(r.transmissions.file); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.checkRoot(); err != nil {
+		return nil, err
+	}
+
+	if r.binaryCheck {
+		r.warnMissingBinaries()
+	}
+
+	return r, nil
+}
+
+// mockMode reports whether executions run the mock rather than the rpitx
+// binaries: in dev mode or with WithDryRun.
+func (r *RPITX) mockMode() bool {
+	return r.dryRun || env.IsDev()
+}
+
+// geteuid returns the effective user ID, replaced in tests.
+//
+//nolint:gochecknoglobals
+var geteuid = os.Geteuid
+
+// checkRoot returns ErrRootRequired when the binaries would run without root
+// in production mode, unless the check is disabled with WithRequireRoot.
+func (r *RPITX) checkRoot() error {
+	if r.skipRootCheck || r.mockMode() || geteuid() == 0 {
+		return nil
+	}
+
+	return ctxerrors.Wrapf(
+		ErrRootRequired,
+		"running as uid %d, run as root or disable the check with "+
+			"WithRequireRoot(false)",
+		geteuid(),
+	)
+}
+
+func defaultModules() map[ModuleName]Module {
+	return map[ModuleName]Module{
+		ModuleNamePIFMRDS:            &PIFMRDS{},
+		ModuleNameTUNE:               &TUNE{},
+		ModuleNameMORSE:              &MORSE{},
+		ModuleNameSPECTRUMPAINT:      &SPECTRUMPAINT{},
+		ModuleNamePICHIRP:            &PICHIRP{},
+		ModuleNamePOCSAG:             &POCSAG{},
+		ModuleNameFT8:                &FT8{},
+		ModuleNamePISSSTV:            &PISSTV{},
+		ModuleNamePIRTTY:             &PIRTTY{},
+		ModuleNameFSK:                &FSK{},
+		ModuleNameAudioSockBroadcast: &AudioSockBroadcast{},
+		ModuleNameSENDIQ:             &SENDIQ{},
+		ModuleNamePIDTMF:             &PIDTMF{},
+		ModuleNameSSB:                &SSB{},
+	}
+}
+
+// rpitxPath returns the directory holding the rpitx binaries with the home
+// directory expanded.
+func (r *RPITX) rpitxPath() string {
+	return expandPath(r.config.Path)
+}
+
+// scriptDir returns the directory the embedded scripts are deployed in.
+func (r *RPITX) scriptDir() string {
+	if r.config.ScriptDir == "" {
+		return defaultScriptDir
+	}
+
+	return expandPath(r.config.ScriptDir)
+}
+
+// log returns the instance logger, falling back to the global logrus logger.
+//
+//nolint:ireturn // Logger is an interface by design
+func (r *RPITX) log() Logger {
+	if r.logger == nil {
+		return logrus.StandardLogger()
+	}
+
+	return r.logger
+}
@@ -0,0 +1,252 @@
+package gorpitx
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	mockCommander := commander.NewMock()
+	logger := logrus.New()
+	custom := map[ModuleName]Module{ModuleNameTUNE: &TUNE{}}
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		check func(t *testing.T, r *RPITX)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Len(t, r.modules, 14)
+				assert.NotNil(t, r.commander)
+				assert.Equal(t, logrus.StandardLogger(), r.log())
+			},
+		},
+		{
+			name: "with path",
+			opts: []Option{WithPath("/opt/rpitx")},
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Equal(t, "/opt/rpitx", r.config.Path)
+			},
+		},
+		{
+			name: "with commander",
+			opts: []Option{WithCommander(mockCommander)},
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Same(t, mockCommander, r.commander)
+			},
+		},
+		{
+			name: "with modules",
+			opts: []Option{WithModules(custom)},
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Equal(t, []ModuleName{ModuleNameTUNE},
+					r.GetSupportedModules())
+			},
+		},
+		{
+			name: "with nil modules",
+			opts: []Option{WithModules(nil)},
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Empty(t, r.GetSupportedModules())
+				require.NoError(t, r.RegisterModule("CUSTOM", &TUNE{}))
+			},
+		},
+		{
+			name: "with logger",
+			opts: []Option{WithLogger(logger)},
+			check: func(t *testing.T, r *RPITX) {
+				t.Helper()
+				assert.Same(t, logger, r.log())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.opts...)
+			require.NoError(t, err)
+			tt.check(t, r)
+		})
+	}
+}
+
+func TestNew_IndependentInstances(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	modules := map[ModuleName]Module{ModuleNameTUNE: &TUNE{}}
+
+	r1, err := New(WithModules(modules))
+	require.NoError(t, err)
+
+	r2, err := New(WithModules(modules))
+	require.NoError(t, err)
+
+	assert.NotSame(t, r1, r2)
+
+	// Changes to the option map or to one instance don't leak elsewhere.
+	modules[ModuleNameMORSE] = &MORSE{}
+
+	require.NoError(t, r1.RegisterModule("CUSTOM", &TUNE{}))
+	assert.Len(t, r1.GetSupportedModules(), 2)
+	assert.Len(t, r2.GetSupportedModules(), 1)
+}
+
+// chanHook forwards logged messages to a channel.
+type chanHook chan string
+
+func (h chanHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h chanHook) Fire(entry *logrus.Entry) error {
+	h <- entry.Message
+
+	return nil
+}
+
+func TestNew_LoggerUsedByHooks(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	messages := make(chanHook, 1)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(messages)
+
+	r, err := New(WithLogger(logger))
+	require.NoError(t, err)
+
+	r.SetHooks(Hooks{OnError: func(error) { panic("boom") }})
+
+	d := r.newHookDispatcher()
+	d.failed(assert.AnError)
+
+	select {
+	case msg := <-messages:
+		assert.Contains(t, msg, "boom")
+	case <-time.After(time.Second):
+		t.Fatal("hook panic was not logged")
+	}
+}
+
+func TestNew_RootCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		uid         int
+		opts        []Option
+		expectedErr error
+	}{
+		{
+			name:        "non-root in production",
+			env:         env.EnvTypeProd,
+			uid:         1000,
+			expectedErr: ErrRootRequired,
+		},
+		{
+			name: "root in production",
+			env:  env.EnvTypeProd,
+		},
+		{
+			name: "non-root in production with the check disabled",
+			env:  env.EnvTypeProd,
+			uid:  1000,
+			opts: []Option{WithRequireRoot(false)},
+		},
+		{
+			name:        "check re-enabled by a later option",
+			env:         env.EnvTypeProd,
+			uid:         1000,
+			opts:        []Option{WithRequireRoot(false), WithRequireRoot(true)},
+			expectedErr: ErrRootRequired,
+		},
+		{
+			name: "non-root in dev mode",
+			env:  env.EnvTypeDev,
+			uid:  1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(env.EnvVarName, tt.env)
+
+			geteuid = func() int { return tt.uid }
+			t.Cleanup(func() { geteuid = os.Geteuid })
+
+			_, err := New(tt.opts...)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Contains(t, err.Error(), "uid 1000")
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNew_WithDryRun(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	// Not root either, a dry run doesn't need it
+	geteuid = func() int { return 1000 }
+	t.Cleanup(func() { geteuid = os.Geteuid })
+
+	rpitx, err := New(
+		WithDryRun(),
+		WithPath("/nonexistent/rpitx"),
+		WithMock(MockConfig{Interval: 50 * time.Millisecond}),
+		WithModules(map[ModuleName]Module{ModuleNameMORSE: &MORSE{}}),
+		WithLogger(NopLogger()),
+	)
+	require.NoError(t, err)
+
+	args := []byte(`{"frequency": 434000000, "rate": 20, "message": "CQ"}`)
+
+	cmdName, _, _, err := rpitx.PreviewCommand(ModuleNameMORSE, args)
+	require.NoError(t, err)
+	assert.NotEqual(t, "stdbuf", cmdName)
+
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	stdout := make(chan string, 10)
+	execution.Stream(stdout, nil)
+
+	select {
+	case line := <-stdout:
+		assert.Contains(t, line, "mocking execution of morse")
+	case <-time.After(3 * time.Second):
+		t.Fatal("no output received from the dry run")
+	}
+
+	assert.True(t, rpitx.Status().DevMode)
+
+	_ = execution.Stop(ctx)
+
+	select {
+	case <-execution.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("dry run did not finish after stop")
+	}
+}
@@ -0,0 +1,178 @@
+package gorpitx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// procDir is where the running processes are listed.
+	procDir = "/proc"
+
+	// orphanPollInterval is how often CleanupOrphans checks whether a
+	// process it sent SIGTERM to has exited.
+	orphanPollInterval = 50 * time.Millisecond
+)
+
+// orphan is a leftover rpitx process found by CleanupOrphans.
+type orphan struct {
+	pid  int
+	name string
+}
+
+// CleanupOrphans kills rpitx processes left over by a previous run, e.g.
+// a pifmrds still keeping the carrier up after the host program crashed
+// without calling Stop, and returns the PIDs it reaped. Only processes
+// named after the binary of a registered module are touched, plus sendiq
+// for the script modules, and each gets gracefulStopTimeout to exit after
+// SIGTERM before it is killed. It takes the execution slot while it runs,
+// returning ErrExecuting when something is running. Nothing is touched in
+// dev mode or with WithDryRun. PIDs that couldn't be killed, e.g. for lack
+// of permission, are left out and their errors joined.
+func (r *RPITX) CleanupOrphans() ([]int, error) {
+	if !r.isExecuting.CompareAndSwap(false, true) {
+		return nil, r.busyError()
+	}
+
+	defer r.isExecuting.Store(false)
+
+	if r.mockMode() {
+		r.log().Debugf("not cleaning up orphaned processes in mock mode")
+
+		return nil, nil
+	}
+
+	orphans, err := findOrphans(r.binaryNames())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		reaped []int
+		errs   []error
+	)
+
+	for _, o := range orphans {
+		if err := stopOrphan(o.pid); err != nil {
+			errs = append(errs, ctxerrors.Wrapf(
+				err, "failed to kill orphaned %s process %d", o.name, o.pid,
+			))
+
+			continue
+		}
+
+		r.log().Warnf("killed orphaned %s process %d", o.name, o.pid)
+
+		reaped = append(reaped, o.pid)
+	}
+
+	return reaped, errors.Join(errs...)
+}
+
+// binaryNames returns the process names of the binaries the registered
+// modules run.
+func (r *RPITX) binaryNames() []string {
+	var names []string
+
+	for _, name := range r.GetSupportedModules() {
+		binary := name
+
+		switch override, ok := r.config.BinaryPaths[name]; {
+		case r.IsScriptModule(name):
+			binary = sendiqBinaryName
+		case ok:
+			binary = filepath.Base(expandPath(override))
+		}
+
+		if !slices.Contains(names, binary) {
+			names = append(names, binary)
+		}
+	}
+
+	return names
+}
+
+// findOrphans lists the processes with one of the names, other than the
+// current one.
+func findOrphans(names []string) ([]orphan, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to list processes in %s", procDir)
+	}
+
+	var orphans []orphan
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		// The process may be gone already
+		comm, err := os.ReadFile(filepath.Join(procDir, entry.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+
+		name := strings.TrimSpace(string(comm))
+		if slices.Contains(names, name) {
+			orphans = append(orphans, orphan{pid: pid, name: name})
+		}
+	}
+
+	return orphans, nil
+}
+
+// stopOrphan sends SIGTERM to the process, so e.g. pifmrds can release its
+// DMA, and SIGKILL if it hasn't exited after gracefulStopTimeout.
+func stopOrphan(pid int) error {
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+
+		return ctxerrors.Wrap(err, "failed to send SIGTERM")
+	}
+
+	deadline := time.Now().Add(gracefulStopTimeout)
+	for time.Now().Before(deadline) {
+		if !processAlive(pid) {
+			return nil
+		}
+
+		time.Sleep(orphanPollInterval)
+	}
+
+	err := syscall.Kill(pid, syscall.SIGKILL)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		return ctxerrors.Wrap(err, "failed to send SIGKILL")
+	}
+
+	return nil
+}
+
+// processAlive reports whether the process is still running. Zombies
+// waiting to be reaped by their parent have exited already.
+func processAlive(pid int) bool {
+	stat, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+
+	// The state follows the command name, which is in parentheses and may
+	// contain spaces itself
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 || end+2 >= len(stat) {
+		return false
+	}
+
+	return stat[end+2] != 'Z'
+}
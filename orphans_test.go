@@ -0,0 +1,153 @@
+package gorpitx
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startNamedSleep runs sleep under the given name so its process is listed
+// with that name, and returns the command with its exit error channel.
+func startNamedSleep(t *testing.T, name string) (*exec.Cmd, chan error) {
+	t.Helper()
+
+	sleepPath, err := exec.LookPath("sleep")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(sleepPath)
+	require.NoError(t, err)
+
+	binary := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(binary, data, 0o700))
+
+	cmd := exec.Command(binary, "30")
+	require.NoError(t, cmd.Start())
+
+	exited := make(chan error, 1)
+
+	go func() { exited <- cmd.Wait() }()
+
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	return cmd, exited
+}
+
+// orphanTestModule is a custom module named so no real process matches it.
+const orphanTestModule ModuleName = "gorpitx-orphan"
+
+func TestRPITX_CleanupOrphans(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	rpitx := &RPITX{
+		config: Config{Path: t.TempDir()},
+		modules: map[ModuleName]Module{
+			orphanTestModule: &TUNE{},
+		},
+	}
+
+	orphan, orphanExited := startNamedSleep(t, orphanTestModule)
+	other, otherExited := startNamedSleep(t, "gorpitx-test-other")
+
+	reaped, err := rpitx.CleanupOrphans()
+	require.NoError(t, err)
+	assert.Equal(t, []int{orphan.Process.Pid}, reaped)
+
+	select {
+	case err := <-orphanExited:
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+
+		status, ok := exitErr.Sys().(syscall.WaitStatus)
+		require.True(t, ok)
+		assert.Equal(t, syscall.SIGTERM, status.Signal())
+	case <-time.After(time.Second):
+		t.Fatal("orphan still running")
+	}
+
+	// Processes with other names are left alone
+	select {
+	case err := <-otherExited:
+		t.Fatalf("unrelated process exited: %v", err)
+	default:
+	}
+
+	assert.False(t, rpitx.isExecuting.Load())
+	require.NoError(t, other.Process.Kill())
+}
+
+func TestRPITX_CleanupOrphans_Executing(t *testing.T) {
+	rpitx := &RPITX{}
+	rpitx.isExecuting.Store(true)
+
+	_, err := rpitx.CleanupOrphans()
+	require.ErrorIs(t, err, ErrExecuting)
+}
+
+func TestRPITX_CleanupOrphans_MockMode(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			orphanTestModule: &TUNE{},
+		},
+	}
+
+	orphan, orphanExited := startNamedSleep(t, orphanTestModule)
+
+	reaped, err := rpitx.CleanupOrphans()
+	require.NoError(t, err)
+	assert.Empty(t, reaped)
+
+	select {
+	case err := <-orphanExited:
+		t.Fatalf("process killed in mock mode: %v", err)
+	default:
+	}
+
+	require.NoError(t, orphan.Process.Kill())
+}
+
+func TestRPITX_BinaryNames(t *testing.T) {
+	rpitx := &RPITX{
+		config: Config{
+			ScriptDir: t.TempDir(),
+			BinaryPaths: map[ModuleName]string{
+				ModuleNamePOCSAG: "/usr/local/bin/pocsag-v2",
+				// Script modules run sendiq whatever the override
+				ModuleNameFSK: "/usr/local/bin/fsk",
+			},
+		},
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:   &TUNE{},
+			ModuleNamePOCSAG: &POCSAG{},
+			ModuleNameFSK:    &FSK{},
+			ModuleNamePIDTMF: &PIDTMF{},
+		},
+	}
+
+	assert.ElementsMatch(
+		t,
+		[]string{ModuleNameTUNE, "pocsag-v2", sendiqBinaryName},
+		rpitx.binaryNames(),
+	)
+
+	// Without a registered script module nothing runs sendiq
+	rpitx.modules = map[ModuleName]Module{
+		ModuleNameTUNE: &TUNE{},
+		"fsk-custom":   &TUNE{},
+	}
+	rpitx.config.BinaryPaths = nil
+
+	assert.ElementsMatch(
+		t,
+		[]string{ModuleNameTUNE, "fsk-custom"},
+		rpitx.binaryNames(),
+	)
+}
@@ -0,0 +1,211 @@
+package gorpitx
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/psyb0t/commander"
+)
+
+// outputLine is a line printed by a process.
+type outputLine struct {
+	stderr bool
+	text   string
+}
+
+// hubStream is a stream attached to an outputHub, nil channels not wanted.
+type hubStream struct {
+	stdout chan<- string
+	stderr chan<- string
+}
+
+// outputHub reads the output of a process from the moment it starts and
+// broadcasts it to the attached streams. Lines printed before the first
+// stream attaches are kept, up to limit, and sent to it first, so a stream
+// attached right after the execution starts misses nothing. Captures, like
+// the one of ExecOutput, get every line from the start without taking the
+// kept lines from the first stream. The channels of the streams and
+// captures are closed once the output ends.
+type outputHub struct {
+	mu       sync.Mutex
+	limit    int
+	early    []outputLine
+	streams  []hubStream
+	captures []hubStream
+	attached bool
+	ended    bool
+	done     chan struct{}
+}
+
+// newOutputHub starts reading the output of the process, with the streams
+// and captures attached before the first line.
+func newOutputHub(
+	process commander.Process,
+	limit int,
+	streams []hubStream,
+	captures []hubStream,
+) *outputHub {
+	hub := &outputHub{
+		limit:    limit,
+		streams:  streams,
+		captures: captures,
+		attached: len(streams) > 0,
+		done:     make(chan struct{}),
+	}
+
+	stdout := make(chan string, limit)
+	stderr := make(chan string, limit)
+	process.Stream(stdout, stderr)
+
+	go hub.run(stdout, stderr)
+
+	return hub
+}
+
+// run dispatches the lines of the process until both streams end.
+func (h *outputHub) run(stdout, stderr <-chan string) {
+	for stdout != nil || stderr != nil {
+		select {
+		case text, ok := <-stdout:
+			if !ok {
+				stdout = nil
+
+				continue
+			}
+
+			h.dispatch(outputLine{text: text})
+		case text, ok := <-stderr:
+			if !ok {
+				stderr = nil
+
+				continue
+			}
+
+			h.dispatch(outputLine{stderr: true, text: text})
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ended = true
+
+	for _, stream := range slices.Concat(h.streams, h.captures) {
+		stream.close()
+	}
+
+	h.streams = nil
+	h.captures = nil
+
+	close(h.done)
+}
+
+// dispatch sends the line to the attached streams, or keeps it for the
+// first one while none is attached, dropping the oldest over the limit.
+func (h *outputHub) dispatch(line outputLine) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, capture := range h.captures {
+		capture.send(line)
+	}
+
+	if !h.attached {
+		if len(h.early) == h.limit {
+			h.early = h.early[1:]
+		}
+
+		h.early = append(h.early, line)
+
+		return
+	}
+
+	for _, stream := range h.streams {
+		stream.send(line)
+	}
+}
+
+// attach adds a stream, sending it the kept lines if it is the first one.
+// The stream is closed right away once the output has ended.
+func (h *outputHub) attach(stream hubStream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.attached {
+		for _, line := range h.early {
+			stream.send(line)
+		}
+
+		h.early = nil
+		h.attached = true
+	}
+
+	if h.ended {
+		stream.close()
+
+		return
+	}
+
+	h.streams = append(h.streams, stream)
+}
+
+// send sends the line to the channel of its stream, if wanted. The
+// channels are read right away by relayLines.
+func (s hubStream) send(line outputLine) {
+	ch := s.stdout
+	if line.stderr {
+		ch = s.stderr
+	}
+
+	if ch != nil {
+		ch <- line.text
+	}
+}
+
+// close closes the channels of the stream.
+func (s hubStream) close() {
+	if s.stdout != nil {
+		close(s.stdout)
+	}
+
+	if s.stderr != nil {
+		close(s.stderr)
+	}
+}
+
+// hubProcess is a process whose streams are attached to its outputHub.
+type hubProcess struct {
+	commander.Process
+
+	hub *outputHub
+}
+
+// Stream attaches the channels to the output hub of the process.
+func (p *hubProcess) Stream(stdout, stderr chan<- string) {
+	p.hub.attach(hubStream{stdout: stdout, stderr: stderr})
+}
+
+// waitCaptured blocks until the output of the process has been read to the
+// end when it is captured, e.g. for ExecOutput, as Wait closes the pipes and
+// would cut off the lines of a process exiting right after printing them.
+func (p *hubProcess) waitCaptured() {
+	p.hub.mu.Lock()
+	captured := len(p.hub.captures) > 0
+	p.hub.mu.Unlock()
+
+	if captured {
+		<-p.hub.done
+	}
+}
+
+// rawProcess returns the process under a hubProcess, e.g. for internal
+// captures that must not take the kept lines from the first stream.
+//
+//nolint:ireturn // commander.Process is an interface by design
+func rawProcess(process commander.Process) commander.Process {
+	if p, ok := process.(*hubProcess); ok {
+		return p.Process
+	}
+
+	return process
+}
@@ -0,0 +1,175 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamProcess hands the channels registered with Stream to the test.
+type fakeStreamProcess struct {
+	commander.Process
+
+	stdout chan<- string
+	stderr chan<- string
+}
+
+func (p *fakeStreamProcess) Stream(stdout, stderr chan<- string) {
+	p.stdout = stdout
+	p.stderr = stderr
+}
+
+// end closes the output of the process.
+func (p *fakeStreamProcess) end() {
+	close(p.stdout)
+	close(p.stderr)
+}
+
+// readAll reads the channel until it is closed.
+func readAll(t *testing.T, ch <-chan string) []string {
+	t.Helper()
+
+	var lines []string
+
+	for {
+		select {
+		case line, ok := <-ch:
+			if !ok {
+				return lines
+			}
+
+			lines = append(lines, line)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("channel not closed, got %v", lines)
+		}
+	}
+}
+
+func TestOutputHub_KeepsEarlyLinesForFirstStream(t *testing.T) {
+	process := &fakeStreamProcess{}
+	hub := newOutputHub(process, 2, nil, nil)
+
+	process.stdout <- "one"
+	process.stdout <- "two"
+	process.stdout <- "three"
+
+	// Dispatched before the first stream attaches
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+
+		return len(hub.early) == 2 && hub.early[1].text == "three"
+	}, time.Second, time.Millisecond)
+
+	first := &RPITX{logger: NopLogger()}
+	firstStdout := make(chan string, 10)
+	firstStderr := make(chan string, 10)
+	first.forwardStreamAsync(
+		context.Background(), &hubProcess{Process: process, hub: hub},
+		firstStdout, firstStderr,
+	)
+
+	second := make(chan string, 10)
+	first.forwardStreamAsync(
+		context.Background(), &hubProcess{Process: process, hub: hub},
+		second, nil,
+	)
+
+	process.stdout <- "four"
+	process.stderr <- "oops"
+	process.end()
+
+	// The oldest kept line is dropped over the limit
+	assert.Equal(t, []string{"two", "three", "four"}, readAll(t, firstStdout))
+	assert.Equal(t, []string{"oops"}, readAll(t, firstStderr))
+
+	// Later streams start from the moment they attach
+	assert.Equal(t, []string{"four"}, readAll(t, second))
+}
+
+func TestOutputHub_PendingStreams(t *testing.T) {
+	rpitx := &RPITX{logger: NopLogger()}
+
+	stdout := make(chan string, 10)
+	stream, _ := rpitx.startRelays(context.Background(), stdout, nil)
+
+	process := &fakeStreamProcess{}
+	newOutputHub(process, 10, []hubStream{stream}, nil)
+
+	process.stdout <- "first"
+	process.end()
+
+	assert.Equal(t, []string{"first"}, readAll(t, stdout))
+}
+
+func TestOutputHub_CaptureLeavesEarlyLinesToFirstStream(t *testing.T) {
+	rpitx := &RPITX{logger: NopLogger()}
+	capture := &outputCapture{}
+
+	process := &fakeStreamProcess{}
+	hub := newOutputHub(process, 10, nil, []hubStream{capture.stream()})
+
+	process.stdout <- "early"
+	process.stderr <- "oops"
+
+	// The capture doesn't count as the first stream
+	stdout := make(chan string, 10)
+	rpitx.forwardStreamAsync(
+		context.Background(), &hubProcess{Process: process, hub: hub},
+		stdout, nil,
+	)
+
+	process.stdout <- "late"
+	process.end()
+
+	assert.Equal(t, []string{"early", "late"}, readAll(t, stdout))
+
+	captured, stderr := capture.output()
+	assert.Equal(t, "early\nlate\n", string(captured))
+	assert.Equal(t, "oops\n", string(stderr))
+}
+
+func TestOutputHub_AttachAfterEnd(t *testing.T) {
+	rpitx := &RPITX{logger: NopLogger()}
+	process := &fakeStreamProcess{}
+	hub := newOutputHub(process, 10, nil, nil)
+
+	process.stdout <- "only"
+	process.end()
+
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+
+		return hub.ended
+	}, time.Second, time.Millisecond)
+
+	// The kept lines are still delivered before the channel is closed
+	stdout := make(chan string, 10)
+	rpitx.forwardStreamAsync(
+		context.Background(), &hubProcess{Process: process, hub: hub},
+		stdout, nil,
+	)
+
+	assert.Equal(t, []string{"only"}, readAll(t, stdout))
+}
+
+func TestRPITX_StreamOutputsAsync_BeforeExec(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 3)
+
+	// Set up before anything runs, every line of the execution arrives
+	stdout := make(chan string, 10)
+	rpitx.StreamOutputsAsync(stdout, nil)
+
+	require.NoError(t, rpitx.Exec(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), 0,
+	))
+
+	assert.Len(t, readAll(t, stdout), 3)
+	assert.Empty(t, rpitx.pendingStreams)
+}
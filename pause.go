@@ -0,0 +1,87 @@
+package gorpitx
+
+import (
+	"context"
+	"syscall"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Pause suspends the running process with SIGSTOP without tearing it down.
+// Returns ErrNotExecuting when nothing is running. Pausing an already paused
+// process is a no-op.
+func (r *RPITX) Pause(ctx context.Context) error {
+	return r.setPaused(ctx, true)
+}
+
+// Resume continues a process suspended with Pause by sending SIGCONT.
+// Returns ErrNotExecuting when nothing is running. Resuming a process that
+// isn't paused is a no-op.
+func (r *RPITX) Resume(ctx context.Context) error {
+	return r.setPaused(ctx, false)
+}
+
+// setPaused signals the running process to pause or resume.
+func (r *RPITX) setPaused(ctx context.Context, paused bool) error {
+	if err := ctx.Err(); err != nil {
+		return ctxerrors.Wrap(err, "context done")
+	}
+
+	if !r.isExecuting.Load() {
+		return ErrNotExecuting
+	}
+
+	r.processMu.Lock()
+	defer r.processMu.Unlock()
+
+	if r.process == nil {
+		return ErrNotExecuting
+	}
+
+	if r.isPaused.Load() == paused {
+		return nil
+	}
+
+	sig := syscall.SIGCONT
+	if paused {
+		sig = syscall.SIGSTOP
+	}
+
+	if err := signalProcessGroup(r.process, sig); err != nil {
+		return err
+	}
+
+	r.isPaused.Store(paused)
+
+	r.log().Debugf("sent %s to module %s", sig, r.currentModule)
+
+	return nil
+}
+
+// resumeIfPaused continues the process if it is currently paused.
+func (r *RPITX) resumeIfPaused(process commander.Process) {
+	if !r.isPaused.CompareAndSwap(true, false) {
+		return
+	}
+
+	if err := signalProcessGroup(process, syscall.SIGCONT); err != nil {
+		r.log().Warnf("failed to resume paused process: %v", err)
+	}
+}
+
+// signalProcessGroup sends the signal to the process group of the process,
+// which commander places every started command in, so children like the
+// programs spawned by script modules are signalled too.
+func signalProcessGroup(process commander.Process, sig syscall.Signal) error {
+	pid := process.PID()
+	if pid <= 0 {
+		return ctxerrors.Wrap(ErrNotExecuting, "process has no PID")
+	}
+
+	if err := syscall.Kill(-pid, sig); err != nil {
+		return ctxerrors.Wrapf(err, "failed to send %s to process %d", sig, pid)
+	}
+
+	return nil
+}
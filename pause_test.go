@@ -0,0 +1,113 @@
+package gorpitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// processState returns the state letter of the process from /proc.
+func processState(t *testing.T, pid int) string {
+	t.Helper()
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	require.NoError(t, err)
+
+	// The state follows the command name, which is wrapped in parentheses
+	fields := strings.Fields(string(data[strings.LastIndex(string(data), ")")+1:]))
+	require.NotEmpty(t, fields)
+
+	return fields[0]
+}
+
+// discardBuffered discards the lines already buffered in the channel.
+func discardBuffered(lines <-chan string) {
+	for {
+		select {
+		case <-lines:
+		default:
+			return
+		}
+	}
+}
+
+func TestRPITX_PauseResume_NotExecuting(t *testing.T) {
+	rpitx := &RPITX{}
+	ctx := context.Background()
+
+	require.ErrorIs(t, rpitx.Pause(ctx), ErrNotExecuting)
+	require.ErrorIs(t, rpitx.Resume(ctx), ErrNotExecuting)
+}
+
+func TestRPITX_PauseResume(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("procfs not available")
+	}
+
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	pid := execution.process.PID()
+
+	// Wait for the mock loop to be up before suspending it
+	stdout := make(chan string, 10)
+	execution.Stream(stdout, nil)
+
+	select {
+	case <-stdout:
+	case <-time.After(3 * time.Second):
+		t.Fatal("no output received from execution")
+	}
+
+	require.NoError(t, rpitx.Pause(ctx))
+	require.NoError(t, rpitx.Pause(ctx), "pausing twice is a no-op")
+	assert.True(t, rpitx.Status().Paused)
+
+	// The shell shows D instead of T when it was stopped while starting
+	// sleep, waiting on the stopped child, so the output going quiet is
+	// what tells the whole group is paused
+	assert.Eventually(t, func() bool {
+		state := processState(t, pid)
+
+		return state == "T" || state == "D"
+	}, time.Second, 10*time.Millisecond)
+
+	// Let a line echoed just before the signal through the stream
+	time.Sleep(100 * time.Millisecond)
+	discardBuffered(stdout)
+
+	assert.Never(t, func() bool {
+		return len(stdout) > 0
+	}, 300*time.Millisecond, 10*time.Millisecond)
+
+	require.NoError(t, rpitx.Resume(ctx))
+	assert.False(t, rpitx.Status().Paused)
+
+	select {
+	case <-stdout:
+	case <-time.After(3 * time.Second):
+		t.Fatal("no output received after resuming")
+	}
+
+	// Stopping a paused process resumes it so it can terminate
+	require.NoError(t, rpitx.Pause(ctx))
+	_ = execution.Stop(ctx)
+
+	select {
+	case <-execution.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("paused execution did not stop")
+	}
+
+	assert.False(t, rpitx.isPaused.Load())
+	require.ErrorIs(t, rpitx.Resume(ctx), ErrNotExecuting)
+}
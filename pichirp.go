@@ -11,20 +11,30 @@ import (
 
 const (
 	ModuleNamePICHIRP ModuleName = "pichirp"
+
+	pichirpDirectionUp   = "up"
+	pichirpDirectionDown = "down"
 )
 
 type PICHIRP struct {
 	// Frequency specifies the center frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// Bandwidth specifies the frequency sweep bandwidth in Hz. Required parameter.
 	// Must be positive value.
-	Bandwidth float64 `json:"bandwidth"`
+	Bandwidth float64 `json:"bandwidth" schema:"exclusiveMinimum=0"`
 
 	// Time specifies the sweep duration in seconds. Required parameter.
 	// Must be positive value.
-	Time float64 `json:"time"`
+	Time float64 `json:"time" schema:"exclusiveMinimum=0"`
+
+	// Repeat specifies how many identical chirps are sent back to back.
+	// Optional, must be positive. Defaults to a single chirp.
+	Repeat *int `json:"repeat,omitempty" schema:"minimum=1"`
+
+	// Descending sweeps the frequency down instead of up. Optional.
+	Descending *bool `json:"descending,omitempty"`
 }
 
 func (m *PICHIRP) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -40,7 +50,8 @@ func (m *PICHIRP) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
 }
 
 // buildArgs converts the struct fields into command-line arguments for pichirp
-// binary.
+// binary. The repeat count and direction follow the three required args
+// only when set, the direction needing the repeat count before it.
 func (m *PICHIRP) buildArgs() []string {
 	var args []string
 
@@ -56,24 +67,44 @@ func (m *PICHIRP) buildArgs() []string {
 	args = append(args,
 		strconv.FormatFloat(m.Time, 'f', -1, 64))
 
-	return args
-}
-
-// validate validates all PICHIRP parameters.
-func (m *PICHIRP) validate() error {
-	if err := m.validateFrequency(); err != nil {
-		return err
+	if m.Repeat == nil && m.Descending == nil {
+		return args
 	}
 
-	if err := m.validateBandwidth(); err != nil {
-		return err
+	// Add repeat argument (optional)
+	repeat := 1
+	if m.Repeat != nil {
+		repeat = *m.Repeat
 	}
 
-	if err := m.validateTime(); err != nil {
-		return err
+	args = append(args, strconv.Itoa(repeat))
+
+	// Add direction argument (optional)
+	if m.Descending != nil {
+		direction := pichirpDirectionUp
+		if *m.Descending {
+			direction = pichirpDirectionDown
+		}
+
+		args = append(args, direction)
 	}
 
-	return nil
+	return args
+}
+
+// validate validates all PICHIRP parameters.
+func (m *PICHIRP) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"bandwidth", m.validateBandwidth},
+		fieldCheck{"time", m.validateTime},
+		fieldCheck{"repeat", m.validateRepeat},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *PICHIRP) frequencyHz() float64 {
+	return m.Frequency
 }
 
 // validateFrequency validates the frequency parameter.
@@ -91,7 +122,7 @@ func (m *PICHIRP) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -123,3 +154,16 @@ func (m *PICHIRP) validateTime() error {
 
 	return nil
 }
+
+// validateRepeat validates the optional repeat parameter.
+func (m *PICHIRP) validateRepeat() error {
+	if m.Repeat != nil && *m.Repeat <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"repeat must be positive, got: %d",
+			*m.Repeat,
+		)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +56,37 @@ func TestPICHIRP_ParseArgs(t *testing.T) {
 			expectError: false,
 			expectArgs:  []string{"1296000000", "1000000", "0.5"},
 		},
+		{
+			name: "repeated descending chirps",
+			input: map[string]any{
+				"frequency":  7000000.0,
+				"bandwidth":  500000.0,
+				"time":       2.0,
+				"repeat":     10,
+				"descending": true,
+			},
+			expectArgs: []string{"7000000", "500000", "2", "10", "down"},
+		},
+		{
+			name: "repeated chirps",
+			input: map[string]any{
+				"frequency": 7000000.0,
+				"bandwidth": 500000.0,
+				"time":      2.0,
+				"repeat":    3,
+			},
+			expectArgs: []string{"7000000", "500000", "2", "3"},
+		},
+		{
+			name: "zero repeat",
+			input: map[string]any{
+				"frequency": 7000000.0,
+				"bandwidth": 500000.0,
+				"time":      2.0,
+				"repeat":    0,
+			},
+			expectError: true,
+		},
 		{
 			name: "missing frequency",
 			input: map[string]any{
@@ -206,6 +238,9 @@ func TestPICHIRP_BuildArgs(t *testing.T) {
 		{expectArgs: []string{"144500000", "50000", "10.5"}},
 		{expectArgs: []string{"1296000000", "1000000", "0.5"}},
 		{expectArgs: []string{"28070000", "1000", "1"}},
+		{expectArgs: []string{"7000000", "500000", "2", "1", "down"}},
+		{expectArgs: []string{"7000000", "500000", "2", "1", "up"}},
+		{expectArgs: []string{"7000000", "500000", "2", "5"}},
 	}
 
 	testNames := []string{
@@ -213,6 +248,9 @@ func TestPICHIRP_BuildArgs(t *testing.T) {
 		"VHF chirp with decimal time",
 		"microwave wideband chirp",
 		"HF narrowband chirp",
+		"descending without repeat",
+		"explicitly ascending",
+		"repeat without direction",
 	}
 
 	chirpConfigs := []PICHIRP{
@@ -220,6 +258,18 @@ func TestPICHIRP_BuildArgs(t *testing.T) {
 		{Frequency: 144500000.0, Bandwidth: 50000.0, Time: 10.5},
 		{Frequency: 1296000000.0, Bandwidth: 1000000.0, Time: 0.5},
 		{Frequency: 28070000.0, Bandwidth: 1000.0, Time: 1.0},
+		{
+			Frequency: 7000000.0, Bandwidth: 500000.0, Time: 2.0,
+			Descending: boolPtr(true),
+		},
+		{
+			Frequency: 7000000.0, Bandwidth: 500000.0, Time: 2.0,
+			Descending: boolPtr(false),
+		},
+		{
+			Frequency: 7000000.0, Bandwidth: 500000.0, Time: 2.0,
+			Repeat: intPtr(5),
+		},
 	}
 
 	for i, tt := range tests {
@@ -334,6 +384,16 @@ func TestPICHIRP_Validate(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "invalid repeat",
+			pichirp: PICHIRP{
+				Frequency: 434000000.0,
+				Bandwidth: 100000.0,
+				Time:      5.0,
+				Repeat:    intPtr(-1),
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -350,3 +410,40 @@ func TestPICHIRP_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPICHIRP_ValidateRepeat(t *testing.T) {
+	tests := []struct {
+		name        string
+		repeat      *int
+		expectedErr error
+	}{
+		{name: "unset"},
+		{name: "one", repeat: intPtr(1)},
+		{name: "many", repeat: intPtr(100)},
+		{
+			name:        "zero",
+			repeat:      intPtr(0),
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "negative",
+			repeat:      intPtr(-1),
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &PICHIRP{Repeat: tt.repeat}
+
+			err := m.validateRepeat()
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
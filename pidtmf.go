@@ -0,0 +1,160 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	ModuleNamePIDTMF ModuleName = "pidtmf"
+)
+
+const (
+	defaultDTMFToneMs = 100
+	defaultDTMFGapMs  = 100
+
+	// dtmfDigits are the characters of the DTMF keypad.
+	dtmfDigits = "0123456789ABCD*#"
+)
+
+type PIDTMF struct {
+	// Frequency specifies the carrier frequency in Hz. Required parameter.
+	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
+
+	// Digits specifies the DTMF sequence to send. Required parameter.
+	// Only 0-9, A-D, * and # are allowed.
+	Digits string `json:"digits" schema:"pattern=^[0-9A-D*#]+$"`
+
+	// ToneMs specifies how long each tone lasts in milliseconds. Optional
+	// parameter. Must be positive if specified. Default: 100 ms
+	ToneMs *int `json:"toneMs,omitempty" schema:"exclusiveMinimum=0"`
+
+	// GapMs specifies the silence between tones in milliseconds. Optional
+	// parameter. Must not be negative if specified. Default: 100 ms
+	GapMs *int `json:"gapMs,omitempty" schema:"minimum=0"`
+}
+
+func (m *PIDTMF) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
+	if err := json.Unmarshal(args, m); err != nil {
+		return nil, nil, ctxerrors.Wrap(err, "failed to unmarshal args")
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return m.buildArgs(), nil, nil
+}
+
+// buildArgs converts the struct fields into command-line arguments for the
+// DTMF script.
+func (m *PIDTMF) buildArgs() []string {
+	var args []string
+
+	// Add frequency argument (required)
+	args = append(args,
+		strconv.FormatFloat(m.Frequency, 'f', 0, 64))
+
+	// Add digits argument (required)
+	args = append(args, m.Digits)
+
+	// Add tone and gap arguments (optional, defaulted)
+	toneMs := defaultDTMFToneMs
+	if m.ToneMs != nil {
+		toneMs = *m.ToneMs
+	}
+
+	gapMs := defaultDTMFGapMs
+	if m.GapMs != nil {
+		gapMs = *m.GapMs
+	}
+
+	args = append(args, strconv.Itoa(toneMs), strconv.Itoa(gapMs))
+
+	return args
+}
+
+// validate validates all PIDTMF parameters.
+func (m *PIDTMF) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"digits", m.validateDigits},
+		fieldCheck{"toneMs", m.validateTiming},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *PIDTMF) frequencyHz() float64 {
+	return m.Frequency
+}
+
+// validateFrequency validates the frequency parameter.
+func (m *PIDTMF) validateFrequency() error {
+	if m.Frequency <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency must be positive, got: %f",
+			m.Frequency,
+		)
+	}
+
+	// Validate frequency range using Hz-based validation
+	if !isValidFreqHz(m.Frequency) {
+		return ctxerrors.Wrapf(
+			ErrFreqOutOfRange,
+			"(%d kHz to %.0f MHz), got: %f Hz",
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
+		)
+	}
+
+	return nil
+}
+
+// validateDigits validates the digits parameter.
+func (m *PIDTMF) validateDigits() error {
+	if m.Digits == "" {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"digits cannot be empty",
+		)
+	}
+
+	for i, digit := range m.Digits {
+		if !strings.ContainsRune(dtmfDigits, digit) {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"invalid DTMF digit %q at position %d, allowed: %s",
+				digit, i, dtmfDigits,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateTiming validates the tone and gap durations.
+func (m *PIDTMF) validateTiming() error {
+	if m.ToneMs != nil && *m.ToneMs <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"toneMs must be positive, got: %d",
+			*m.ToneMs,
+		)
+	}
+
+	if m.GapMs != nil && *m.GapMs < 0 {
+		return fieldError("gapMs", ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"gapMs must not be negative, got: %d",
+			*m.GapMs,
+		))
+	}
+
+	return nil
+}
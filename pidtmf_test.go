@@ -0,0 +1,133 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"testing"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIDTMF_ParseArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       map[string]any
+		expectedErr error
+		expectArgs  []string
+	}{
+		{
+			name: "valid complete args",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "*123#",
+				"toneMs":    80,
+				"gapMs":     40,
+			},
+			expectArgs: []string{"145500000", "*123#", "80", "40"},
+		},
+		{
+			name: "defaults",
+			input: map[string]any{
+				"frequency": 433500000.0,
+				"digits":    "0123456789ABCD*#",
+			},
+			expectArgs: []string{
+				"433500000", "0123456789ABCD*#", "100", "100",
+			},
+		},
+		{
+			name: "no gap",
+			input: map[string]any{
+				"frequency": 433500000.0,
+				"digits":    "1",
+				"gapMs":     0,
+			},
+			expectArgs: []string{"433500000", "1", "100", "0"},
+		},
+		{
+			name: "empty digits",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "invalid digit",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "12E4",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "lowercase letter",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "a",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "space",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "1 2",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "zero tone",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "1",
+				"toneMs":    0,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "negative gap",
+			input: map[string]any{
+				"frequency": 145500000.0,
+				"digits":    "1",
+				"gapMs":     -1,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "missing frequency",
+			input: map[string]any{
+				"digits": "1",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "frequency too high",
+			input: map[string]any{
+				"frequency": 2000000000.0,
+				"digits":    "1",
+			},
+			expectedErr: ErrFreqOutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pidtmf := &PIDTMF{}
+			inputBytes, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+
+			args, stdin, err := pidtmf.ParseArgs(inputBytes)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Nil(t, stdin)
+			assert.Equal(t, tt.expectArgs, args)
+		})
+	}
+}
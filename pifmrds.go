@@ -1,12 +1,18 @@
 package gorpitx
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"os"
+	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 
+	"github.com/psyb0t/commander"
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
 )
@@ -17,12 +23,22 @@ const (
 	piCodeLength = 4  // PI code must be 4 hex digits
 	psMaxLength  = 8  // PS text maximum 8 characters
 	rtMaxLength  = 64 // RT text maximum 64 characters
+
+	resamplerSox    = "sox"
+	resamplerFFmpeg = "ffmpeg"
+
+	controlPipePerm = 0o600
+
+	// Defaults of the pifmrds binary for the RDS fields left unset
+	pifmrdsDefaultPI = "1234"
+	pifmrdsDefaultPS = "Pi-FmRds"
+	pifmrdsDefaultRT = "PiFmRds: live FM-RDS transmission from the RaspberryPi"
 )
 
 type PIFMRDS struct {
 	// `-freq` specifies the carrier frequency (in MHz). Example: `-freq 107.9`.
 	// This is what frequency people tune to on their radios.
-	Freq float64 `json:"freq,omitempty"`
+	Freq float64 `json:"freq,omitempty" schema:"required;freq=mhz"`
 
 	// `-audio` specifies an audio file to play as audio. The sample rate does
 	// not matter: Pi-FM-RDS will resample and filter it. If a stereo file is
@@ -30,24 +46,26 @@ type PIFMRDS struct {
 	// `-audio sound.wav`. The supported formats depend on `libsndfile`. This
 	// includes WAV and Ogg/Vorbis (among others) but not MP3. Specify `-` as
 	// the file name to read audio data on standard input.
-	Audio string `json:"audio,omitempty"`
+	Audio string `json:"audio,omitempty" schema:"required"`
 
 	// `-pi` specifies the PI-code of the RDS broadcast. 4 hexadecimal digits.
 	// Example: `-pi FFFF`. This is the internal station ID that RDS radios use
 	// to identify your station.
-	PI string `json:"pi,omitempty"`
+	PI string `json:"pi,omitempty" schema:"pattern=^[0-9A-Fa-f]{4}$"`
 
 	// `-ps` specifies the station name (Program Service name, PS) of the RDS
 	// broadcast. Limit: 8 characters. Example: `-ps RASP-PI`. This is the
 	// STATION NAME that appears on car radios and RDS displays. By default the
 	// PS changes back and forth between `Pi-FmRds` and a sequence number,
 	// starting at `00000000`. The PS changes around one time per second.
-	PS string `json:"ps,omitempty"`
+	// Only printable 7-bit ASCII shows up right on receivers.
+	PS string `json:"ps,omitempty" schema:"maxLength=8;pattern=^[ -~]*$"`
 
 	// `-rt` specifies the radiotext (RT) to be transmitted. Limit: 64
 	// characters. Example: `-rt 'Hello, world!'`. This is the scrolling text
-	// message shown on RDS displays.
-	RT string `json:"rt,omitempty"`
+	// message shown on RDS displays. The binary has no RT+ tagging, so
+	// "now playing" metadata can only go in the text itself.
+	RT string `json:"rt,omitempty" schema:"maxLength=64"`
 
 	// `-ppm` specifies your Raspberry Pi's oscillator error in parts per
 	// million (ppm).
@@ -58,6 +76,29 @@ type PIFMRDS struct {
 	// change PS and RT at run-time. Create with "mkfifo /tmp/rds_ctl" then
 	// echo commands like "PS New Name".
 	ControlPipe *string `json:"controlPipe,omitempty"`
+
+	// PSRotation cycles the PS through these segments over the control
+	// pipe during the transmission, the way stations show names longer than
+	// 8 characters. Each segment follows the PS rules. The first one is
+	// sent as the PS when PS isn't set. Needs ControlPipe.
+	PSRotation []string `json:"psRotation,omitempty" schema:"minItems=1"`
+
+	// PSRotationIntervalMs is how long each PSRotation segment is shown,
+	// 3000 by default. Must be positive if specified.
+	PSRotationIntervalMs *int `json:"psRotationIntervalMs,omitempty" schema:"exclusiveMinimum=0"` //nolint:lll
+
+	// Schedule changes the PS and RT over the control pipe at set offsets
+	// from the start of the transmission, e.g. the station ID, then the
+	// song title, then a promo. The offsets must not go backwards and each
+	// value follows the rules of its field. PS updates can't be combined
+	// with PSRotation. Needs ControlPipe.
+	Schedule []RDSUpdate `json:"schedule,omitempty" schema:"minItems=1"`
+
+	// ResampleTo converts the audio file to this sample rate in Hz before
+	// transmitting, using sox or ffmpeg, whichever is installed. The
+	// converted file is removed once the transmission ends. Optional
+	// parameter. Must be positive if specified.
+	ResampleTo *int `json:"resampleTo,omitempty" schema:"exclusiveMinimum=0"`
 }
 
 func (m *PIFMRDS) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -92,9 +133,12 @@ func (m *PIFMRDS) buildArgs() []string {
 		args = append(args, "-pi", m.PI)
 	}
 
-	// Add PS argument
-	if m.PS != "" {
+	// Add PS argument, the rotation starting from its first segment
+	switch {
+	case m.PS != "":
 		args = append(args, "-ps", m.PS)
+	case len(m.PSRotation) > 0:
+		args = append(args, "-ps", m.PSRotation[0])
 	}
 
 	// Add RT argument
@@ -116,37 +160,64 @@ func (m *PIFMRDS) buildArgs() []string {
 	return args
 }
 
-// validate validates all PIFMRDSArgs parameters.
-func (m *PIFMRDS) validate() error {
-	if err := m.validateFreq(); err != nil {
-		return err
+// ResolvedArgs returns the args parsed by the last ParseArgs with the
+// defaults the pifmrds binary uses for the fields left unset filled in, e.g.
+// for compliance logs of the exact PI, PS and RT that went out. The PI code
+// is upper-cased. A PSRotation reports its first segment as the PS. Without
+// any PS the binary alternates Pi-FmRds with a sequence number, which is
+// reported as Pi-FmRds.
+func (m *PIFMRDS) ResolvedArgs() PIFMRDS {
+	resolved := *m
+
+	if resolved.PS == "" && len(m.PSRotation) > 0 {
+		resolved.PS = m.PSRotation[0]
 	}
 
-	if err := m.validateAudio(); err != nil {
-		return err
+	resolved.PI = strings.ToUpper(strings.TrimSpace(m.PI))
+	if resolved.PI == "" {
+		resolved.PI = pifmrdsDefaultPI
 	}
 
-	if err := m.validatePI(); err != nil {
-		return err
+	if resolved.PS == "" {
+		resolved.PS = pifmrdsDefaultPS
 	}
 
-	if err := m.validatePS(); err != nil {
-		return err
+	if resolved.RT == "" {
+		resolved.RT = pifmrdsDefaultRT
 	}
 
-	if err := m.validateRT(); err != nil {
-		return err
+	if resolved.PPM == nil {
+		resolved.PPM = new(float64)
 	}
 
-	if err := m.validatePPM(); err != nil {
-		return err
-	}
+	return resolved
+}
 
-	if err := m.validateControlPipe(); err != nil {
-		return err
-	}
+// validate validates all PIFMRDSArgs parameters.
+func (m *PIFMRDS) validate() error {
+	return validateFields(
+		fieldCheck{"freq", m.validateFreq},
+		fieldCheck{"audio", m.validateAudio},
+		fieldCheck{"pi", m.validatePI},
+		fieldCheck{"ps", m.validatePS},
+		fieldCheck{"rt", m.validateRT},
+		fieldCheck{"ppm", m.validatePPM},
+		fieldCheck{"controlPipe", m.validateControlPipe},
+		fieldCheck{"resampleTo", m.validateResampleTo},
+		fieldCheck{"psRotation", m.validatePSRotation},
+		fieldCheck{"schedule", m.validateSchedule},
+	)
+}
 
-	return nil
+// frequencyHz returns the carrier frequency in Hz.
+func (m *PIFMRDS) frequencyHz() float64 {
+	return mHzToHz(m.Freq)
+}
+
+// limitsFreqStep is true as pifmrds takes the frequency in MHz and only
+// tunes reliably in 0.1 MHz steps, the FM broadcast channel spacing.
+func (m *PIFMRDS) limitsFreqStep() bool {
+	return true
 }
 
 // validateFreq validates the frequency parameter.
@@ -174,20 +245,12 @@ func (m *PIFMRDS) validateFreq() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Freq,
+			minFreqKHz, MaxFrequencyMHz(), m.Freq,
 		)
 	}
 
 	// Validate frequency precision (pifmrds works best with 1 decimal place)
-	if !hasValidFreqPrecision(m.Freq) {
-		return ctxerrors.Wrapf(
-			ErrFreqPrecision,
-			"(0.1 MHz precision), got: %f",
-			m.Freq,
-		)
-	}
-
-	return nil
+	return validateFreqPrecision(m, m.Freq)
 }
 
 // validateAudio validates the audio parameter.
@@ -235,18 +298,47 @@ func (m *PIFMRDS) validatePI() error {
 func (m *PIFMRDS) validatePS() error {
 	// Validate PS (Program Service name - 8 chars max) if not empty
 	if m.PS != "" {
-		if len(m.PS) > psMaxLength {
-			return ctxerrors.Wrapf(
-				ErrPSTooLong,
-				"got: %d chars",
-				len(m.PS),
-			)
-		}
+		return validatePSText(m.PS)
+	}
 
-		if strings.TrimSpace(m.PS) == "" {
-			return ctxerrors.Wrap(
+	return nil
+}
+
+// validatePSText validates a Program Service name sent to the receivers.
+func validatePSText(ps string) error {
+	// Checked first, an accented name being too long in bytes otherwise
+	if err := validateRDSCharset(ps); err != nil {
+		return ctxerrors.Wrap(err, "invalid PS text")
+	}
+
+	if len(ps) > psMaxLength {
+		return ctxerrors.Wrapf(
+			ErrPSTooLong,
+			"got: %d chars",
+			len(ps),
+		)
+	}
+
+	if strings.TrimSpace(ps) == "" {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"PS text cannot be empty when specified",
+		)
+	}
+
+	return nil
+}
+
+// validateRDSCharset checks that the text is printable 7-bit ASCII. RDS
+// receivers use their own character table, so anything else shows up as
+// garbage on the display.
+func validateRDSCharset(text string) error {
+	for i := range len(text) {
+		if c := text[i]; c < ' ' || c > '~' {
+			return ctxerrors.Wrapf(
 				commonerrors.ErrInvalidValue,
-				"PS text cannot be empty when specified",
+				"only printable 7-bit ASCII is allowed, got %q at byte %d",
+				c, i,
 			)
 		}
 	}
@@ -258,13 +350,20 @@ func (m *PIFMRDS) validatePS() error {
 func (m *PIFMRDS) validateRT() error {
 	// Validate RT (Radio Text - 64 chars max) if not empty
 	if m.RT != "" {
-		if len(m.RT) > rtMaxLength {
-			return ctxerrors.Wrapf(
-				commonerrors.ErrInvalidValue,
-				"RT text must be 64 characters or less, got: %d chars",
-				len(m.RT),
-			)
-		}
+		return validateRTText(m.RT)
+	}
+
+	return nil
+}
+
+// validateRTText validates a Radio Text sent to the receivers.
+func validateRTText(rt string) error {
+	if len(rt) > rtMaxLength {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"RT text must be 64 characters or less, got: %d chars",
+			len(rt),
+		)
 	}
 
 	return nil
@@ -289,15 +388,180 @@ func (m *PIFMRDS) validateControlPipe() error {
 			)
 		}
 
-		// Check if the control pipe exists (must be created with mkfifo first)
-		if _, err := os.Stat(pipe); os.IsNotExist(err) {
-			return ctxerrors.Wrapf(
-				commonerrors.ErrFileNotFound,
-				"control pipe does not exist: %s (create with: mkfifo %s)",
-				pipe, pipe,
-			)
-		}
+		return checkControlPipe(pipe)
+	}
+
+	return nil
+}
+
+// checkControlPipe checks that the control pipe exists and is a FIFO.
+// pifmrds can't read commands from a regular file, so pointing it at one
+// would silently ignore them.
+func checkControlPipe(pipe string) error {
+	info, err := os.Stat(pipe)
+	if os.IsNotExist(err) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"control pipe does not exist: %s (create with: mkfifo %s)",
+			pipe, pipe,
+		)
+	}
+
+	if err != nil {
+		return ctxerrors.Wrapf(err, "failed to stat control pipe: %s", pipe)
+	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"control pipe is not a FIFO: %s (create with: mkfifo %s)",
+			pipe, pipe,
+		)
+	}
+
+	return nil
+}
+
+// CreateControlPipe creates the FIFO PIFMRDS.ControlPipe reads commands
+// from, like mkfifo. An existing FIFO at path is left as is, anything else
+// there is rejected with ErrInvalidValue.
+func CreateControlPipe(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "path")
+	}
+
+	err := syscall.Mkfifo(path, controlPipePerm)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, os.ErrExist) {
+		return checkControlPipe(path)
+	}
+
+	return ctxerrors.Wrapf(err, "failed to create control pipe: %s", path)
+}
+
+// validateResampleTo validates the resample rate parameter and that a
+// resampler is installed to apply it.
+func (m *PIFMRDS) validateResampleTo() error {
+	if m.ResampleTo == nil {
+		return nil
+	}
+
+	if *m.ResampleTo <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"resampleTo must be positive, got: %d",
+			*m.ResampleTo,
+		)
+	}
+
+	if _, err := findResampler(); err != nil {
+		return err
 	}
 
 	return nil
 }
+
+// prepareInput resamples the audio and starts the PS rotation and the RDS
+// schedule, all of which are undone by the returned cleanup.
+func (m *PIFMRDS) prepareInput(
+	ctx context.Context,
+	cmd commander.Commander,
+	args []string,
+) ([]string, func(), error) {
+	args, removeResampled, err := m.resampleAudio(ctx, cmd, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stopRotation := m.startPSRotation(ctx)
+	stopSchedule := m.startSchedule(ctx)
+
+	return args, func() {
+		stopSchedule()
+		stopRotation()
+		removeResampled()
+	}, nil
+}
+
+// resampleAudio resamples the audio file to a temp WAV file when ResampleTo
+// is set and points the -audio argument at it.
+func (m *PIFMRDS) resampleAudio(
+	ctx context.Context,
+	cmd commander.Commander,
+	args []string,
+) ([]string, func(), error) {
+	noop := func() {}
+
+	audioIdx := slices.Index(args, "-audio") + 1
+	if m.ResampleTo == nil || audioIdx == 0 || audioIdx >= len(args) {
+		return args, noop, nil
+	}
+
+	resampler, err := findResampler()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "gorpitx-pifmrds-*.wav")
+	if err != nil {
+		return nil, nil, ctxerrors.Wrap(err, "failed to create resampled file")
+	}
+
+	_ = tmp.Close()
+	cleanup := func() { _ = os.Remove(tmp.Name()) }
+
+	input := args[audioIdx]
+
+	output, err := cmd.CombinedOutput(
+		ctx,
+		resampler,
+		resamplerArgs(resampler, input, tmp.Name(), *m.ResampleTo),
+	)
+	if err != nil {
+		cleanup()
+
+		return nil, nil, ctxerrors.Wrapf(
+			err,
+			"failed to resample %s to %d Hz with %s: %s",
+			input, *m.ResampleTo, resampler, strings.TrimSpace(string(output)),
+		)
+	}
+
+	resampled := slices.Clone(args)
+	resampled[audioIdx] = tmp.Name()
+
+	return resampled, cleanup, nil
+}
+
+// findResampler returns the first installed audio resampler, sox or
+// ffmpeg.
+func findResampler() (string, error) {
+	for _, resampler := range []string{resamplerSox, resamplerFFmpeg} {
+		if _, err := exec.LookPath(resampler); err == nil {
+			return resampler, nil
+		}
+	}
+
+	return "", ctxerrors.Wrap(
+		ErrNoResampler,
+		"install sox or ffmpeg to use resampleTo",
+	)
+}
+
+// resamplerArgs returns the arguments converting input to a WAV file at
+// rate Hz with the given resampler.
+func resamplerArgs(resampler, input, output string, rate int) []string {
+	if resampler == resamplerFFmpeg {
+		return []string{
+			"-y", "-loglevel", "error",
+			"-i", input,
+			"-ar", strconv.Itoa(rate),
+			output,
+		}
+	}
+
+	return []string{input, "-r", strconv.Itoa(rate), output}
+}
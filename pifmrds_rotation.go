@@ -0,0 +1,172 @@
+package gorpitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const defaultPSRotationInterval = 3 * time.Second
+
+// RotatePS cycles the PS of a running pifmrds through the segments by
+// writing PS commands to its control pipe, one segment per interval,
+// starting right away. It returns once ctx is done.
+func RotatePS(
+	ctx context.Context,
+	pipe string,
+	segments []string,
+	interval time.Duration,
+) error {
+	if len(segments) == 0 {
+		return ctxerrors.Wrap(
+			commonerrors.ErrRequiredFieldNotSet,
+			"no PS segments to rotate",
+		)
+	}
+
+	if interval <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"rotation interval must be positive, got %s",
+			interval,
+		)
+	}
+
+	for i, segment := range segments {
+		if err := validatePSText(segment); err != nil {
+			return ctxerrors.Wrapf(err, "PS segment %d", i)
+		}
+	}
+
+	f, err := openControlPipe(pipe)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i = (i + 1) % len(segments) {
+		if _, err := fmt.Fprintf(f, "PS %s\n", segments[i]); err != nil {
+			return ctxerrors.Wrap(err, "failed to write to control pipe")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// openControlPipe opens the control pipe for writing commands. It is opened
+// for reading and writing so the open doesn't block until pifmrds opens it.
+func openControlPipe(pipe string) (*os.File, error) {
+	if err := checkControlPipe(pipe); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(pipe, os.O_RDWR, 0)
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to open control pipe %s", pipe)
+	}
+
+	return f, nil
+}
+
+// validatePSRotation validates the PS rotation parameters.
+func (m *PIFMRDS) validatePSRotation() error {
+	if m.PSRotationIntervalMs != nil && *m.PSRotationIntervalMs <= 0 {
+		return fieldError("psRotationIntervalMs", ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"psRotationIntervalMs must be positive, got: %d",
+			*m.PSRotationIntervalMs,
+		))
+	}
+
+	if m.PSRotation == nil {
+		if m.PSRotationIntervalMs != nil {
+			return fieldError("psRotationIntervalMs", ctxerrors.Wrap(
+				commonerrors.ErrRequiredFieldNotSet,
+				"psRotationIntervalMs needs psRotation",
+			))
+		}
+
+		return nil
+	}
+
+	if len(m.PSRotation) == 0 {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"psRotation cannot be empty when specified",
+		)
+	}
+
+	var errs ValidationErrors
+
+	for i, segment := range m.PSRotation {
+		if err := validatePSText(segment); err != nil {
+			errs = append(errs, newValidationError(
+				fmt.Sprintf("psRotation[%d]", i),
+				ctxerrors.Wrapf(err, "psRotation[%d]", i),
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if m.ControlPipe == nil {
+		return ctxerrors.Wrap(
+			commonerrors.ErrRequiredFieldNotSet,
+			"psRotation needs controlPipe",
+		)
+	}
+
+	return nil
+}
+
+// psRotationInterval returns how long each PS segment is shown.
+func (m *PIFMRDS) psRotationInterval() time.Duration {
+	if m.PSRotationIntervalMs == nil {
+		return defaultPSRotationInterval
+	}
+
+	return time.Duration(*m.PSRotationIntervalMs) * time.Millisecond
+}
+
+// startPSRotation rotates the PS in the background until the returned func
+// is called, which waits for the rotation to end.
+func (m *PIFMRDS) startPSRotation(ctx context.Context) func() {
+	if len(m.PSRotation) == 0 || m.ControlPipe == nil {
+		return func() {}
+	}
+
+	// Copied so later changes to the module don't reach the goroutine
+	pipe := strings.TrimSpace(*m.ControlPipe)
+	segments := append([]string(nil), m.PSRotation...)
+	interval := m.psRotationInterval()
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// The pipe was checked by validate, a failed write ends the rotation
+		_ = RotatePS(ctx, pipe, segments, interval)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,261 @@
+package gorpitx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readControlPipe returns the lines written to the control pipe.
+func readControlPipe(t *testing.T, pipe string) <-chan string {
+	t.Helper()
+
+	f, err := os.OpenFile(pipe, os.O_RDWR, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	lines := make(chan string, 100)
+
+	go func() {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	return lines
+}
+
+// nextLine returns the next line read from the control pipe.
+func nextLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing written to the control pipe")
+
+		return ""
+	}
+}
+
+func TestRotatePS(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	lines := readControlPipe(t, pipe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- RotatePS(
+			ctx, pipe, []string{"RADIO", "GORPITX"}, 20*time.Millisecond,
+		)
+	}()
+
+	for _, expected := range []string{
+		"PS RADIO", "PS GORPITX", "PS RADIO", "PS GORPITX",
+	} {
+		assert.Equal(t, expected, nextLine(t, lines))
+	}
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("RotatePS did not return after cancel")
+	}
+}
+
+func TestRotatePS_InvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	pipe := filepath.Join(dir, "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	tests := []struct {
+		name        string
+		pipe        string
+		segments    []string
+		interval    time.Duration
+		expectedErr error
+	}{
+		{
+			name:        "no segments",
+			pipe:        pipe,
+			interval:    time.Second,
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name:        "zero interval",
+			pipe:        pipe,
+			segments:    []string{"RADIO"},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "segment too long",
+			pipe:        pipe,
+			segments:    []string{"RADIO", "TOO LONG PS"},
+			interval:    time.Second,
+			expectedErr: ErrPSTooLong,
+		},
+		{
+			name:        "missing pipe",
+			pipe:        filepath.Join(dir, "missing.pipe"),
+			segments:    []string{"RADIO"},
+			interval:    time.Second,
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RotatePS(
+				context.Background(), tt.pipe, tt.segments, tt.interval,
+			)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestPIFMRDS_ParseArgs_PSRotation(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	tests := []struct {
+		name        string
+		args        map[string]any
+		expectedPS  string
+		expectedErr error
+	}{
+		{
+			name: "first segment sent as PS",
+			args: map[string]any{
+				"psRotation":           []string{"RADIO", "GORPITX"},
+				"psRotationIntervalMs": 2000,
+				"controlPipe":          pipe,
+			},
+			expectedPS: "RADIO",
+		},
+		{
+			name: "PS kept",
+			args: map[string]any{
+				"ps":          "STATION",
+				"psRotation":  []string{"RADIO", "GORPITX"},
+				"controlPipe": pipe,
+			},
+			expectedPS: "STATION",
+		},
+		{
+			name: "without control pipe",
+			args: map[string]any{
+				"psRotation": []string{"RADIO"},
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "empty rotation",
+			args: map[string]any{
+				"psRotation":  []string{},
+				"controlPipe": pipe,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "invalid segment",
+			args: map[string]any{
+				"psRotation":  []string{"RADIO", "CAFÉ"},
+				"controlPipe": pipe,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "interval without rotation",
+			args: map[string]any{
+				"psRotationIntervalMs": 2000,
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "zero interval",
+			args: map[string]any{
+				"psRotation":           []string{"RADIO"},
+				"psRotationIntervalMs": 0,
+				"controlPipe":          pipe,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["freq"] = 107.9
+			tt.args["audio"] = ".fixtures/test.wav"
+
+			raw, err := json.Marshal(tt.args)
+			require.NoError(t, err)
+
+			module := &PIFMRDS{}
+
+			args, _, err := module.ParseArgs(raw)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Subset(t, args, []string{"-ps", tt.expectedPS})
+			assert.Equal(t, tt.expectedPS, module.ResolvedArgs().PS)
+		})
+	}
+}
+
+func TestPIFMRDS_PrepareInput_PSRotation(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	lines := readControlPipe(t, pipe)
+	interval := 20
+
+	module := &PIFMRDS{
+		PSRotation:           []string{"RADIO", "GORPITX"},
+		PSRotationIntervalMs: &interval,
+		ControlPipe:          &pipe,
+	}
+
+	args := []string{"-freq", "107.9", "-audio", "song.wav"}
+
+	prepared, cleanup, err := module.prepareInput(
+		context.Background(), commander.NewMock(), args,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, args, prepared)
+
+	assert.Equal(t, "PS RADIO", nextLine(t, lines))
+	assert.Equal(t, "PS GORPITX", nextLine(t, lines))
+
+	// The rotation is over once the cleanup returns, so after reading what
+	// was already written nothing else comes
+	cleanup()
+	time.Sleep(50 * time.Millisecond)
+
+	for len(lines) > 0 {
+		<-lines
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, lines)
+}
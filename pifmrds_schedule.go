@@ -0,0 +1,243 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// RDSField is an RDS field changed over the control pipe.
+type RDSField string
+
+const (
+	// RDSFieldPS is the station name, up to 8 characters.
+	RDSFieldPS RDSField = "ps"
+	// RDSFieldRT is the radio text, up to 64 characters.
+	RDSFieldRT RDSField = "rt"
+)
+
+// RDSUpdate changes an RDS field at a point of the transmission.
+type RDSUpdate struct {
+	// AtOffset is when the update is sent, from the start of the
+	// transmission, right away by default. In JSON it is a Go duration
+	// string such as "90s".
+	AtOffset time.Duration `json:"atOffset,omitempty"`
+
+	// Field is the RDS field to change.
+	Field RDSField `json:"field" schema:"enum=ps|rt"`
+
+	// Value is the new text, following the rules of the field.
+	Value string `json:"value"`
+}
+
+// UnmarshalJSON decodes an RDS update, taking AtOffset as a Go duration
+// string such as "90s".
+func (u *RDSUpdate) UnmarshalJSON(data []byte) error {
+	type plainRDSUpdate RDSUpdate
+
+	aux := struct {
+		*plainRDSUpdate
+
+		AtOffset string `json:"atOffset"`
+	}{plainRDSUpdate: (*plainRDSUpdate)(u)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return ctxerrors.Wrap(err, "failed to unmarshal RDS update")
+	}
+
+	if aux.AtOffset == "" {
+		u.AtOffset = 0
+
+		return nil
+	}
+
+	offset, err := time.ParseDuration(aux.AtOffset)
+	if err != nil {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"invalid atOffset %q",
+			aux.AtOffset,
+		)
+	}
+
+	u.AtOffset = offset
+
+	return nil
+}
+
+// command returns the control pipe command applying the update.
+func (u RDSUpdate) command() string {
+	return fmt.Sprintf("%s %s\n", strings.ToUpper(string(u.Field)), u.Value)
+}
+
+// validateRDSUpdate checks the field and value of an update.
+func validateRDSUpdate(update RDSUpdate) error {
+	switch update.Field {
+	case RDSFieldPS:
+		return validatePSText(update.Value)
+	case RDSFieldRT:
+		return validateRTText(update.Value)
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"field must be '%s' or '%s', got: %s",
+			RDSFieldPS, RDSFieldRT, update.Field,
+		)
+	}
+}
+
+// ApplyRDSSchedule sends the updates to a running pifmrds over its control
+// pipe, each once its offset has elapsed from the call. The updates must be
+// in offset order. It returns once the last update is sent or ctx is done.
+func ApplyRDSSchedule(
+	ctx context.Context,
+	pipe string,
+	updates []RDSUpdate,
+) error {
+	for i, update := range updates {
+		if err := validateRDSUpdate(update); err != nil {
+			return ctxerrors.Wrapf(err, "RDS update %d", i)
+		}
+
+		if i > 0 && update.AtOffset < updates[i-1].AtOffset {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"RDS update %d at %s comes before the previous one at %s",
+				i, update.AtOffset, updates[i-1].AtOffset,
+			)
+		}
+	}
+
+	f, err := openControlPipe(pipe)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	start := time.Now()
+
+	for _, update := range updates {
+		timer := time.NewTimer(time.Until(start.Add(update.AtOffset)))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		}
+
+		if _, err := f.WriteString(update.command()); err != nil {
+			return ctxerrors.Wrap(err, "failed to write to control pipe")
+		}
+	}
+
+	return nil
+}
+
+// validateSchedule validates the RDS schedule.
+func (m *PIFMRDS) validateSchedule() error {
+	if m.Schedule == nil {
+		return nil
+	}
+
+	if len(m.Schedule) == 0 {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"schedule cannot be empty when specified",
+		)
+	}
+
+	var errs ValidationErrors
+
+	for i := range m.Schedule {
+		if err := m.validateScheduledUpdate(i); err != nil {
+			errs = append(errs, newValidationError(
+				fmt.Sprintf("schedule[%d]", i), err,
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if m.ControlPipe == nil {
+		return ctxerrors.Wrap(
+			commonerrors.ErrRequiredFieldNotSet,
+			"schedule needs controlPipe",
+		)
+	}
+
+	return nil
+}
+
+// validateScheduledUpdate validates the update at the index of the
+// schedule.
+func (m *PIFMRDS) validateScheduledUpdate(index int) error {
+	update := m.Schedule[index]
+
+	if update.AtOffset < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"schedule[%d].atOffset must not be negative, got: %s",
+			index, update.AtOffset,
+		)
+	}
+
+	if index > 0 && update.AtOffset < m.Schedule[index-1].AtOffset {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"schedule[%d].atOffset %s comes before the previous update at %s",
+			index, update.AtOffset, m.Schedule[index-1].AtOffset,
+		)
+	}
+
+	if err := validateRDSUpdate(update); err != nil {
+		return ctxerrors.Wrapf(err, "schedule[%d]", index)
+	}
+
+	// Both would keep overwriting each other's PS
+	if update.Field == RDSFieldPS && m.PSRotation != nil {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"schedule[%d] PS updates cannot be combined with psRotation",
+			index,
+		)
+	}
+
+	return nil
+}
+
+// startSchedule applies the RDS schedule in the background until the
+// returned func is called, which waits for the scheduler to end.
+func (m *PIFMRDS) startSchedule(ctx context.Context) func() {
+	if len(m.Schedule) == 0 || m.ControlPipe == nil {
+		return func() {}
+	}
+
+	// Copied so later changes to the module don't reach the goroutine
+	pipe := strings.TrimSpace(*m.ControlPipe)
+	updates := append([]RDSUpdate(nil), m.Schedule...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// The pipe was checked by validate, a failed write ends the schedule
+		_ = ApplyRDSSchedule(ctx, pipe, updates)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
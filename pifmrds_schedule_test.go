@@ -0,0 +1,291 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRDSSchedule(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	lines := readControlPipe(t, pipe)
+	start := time.Now()
+
+	err := ApplyRDSSchedule(context.Background(), pipe, []RDSUpdate{
+		{Field: RDSFieldPS, Value: "GORPITX"},
+		{AtOffset: 50 * time.Millisecond, Field: RDSFieldRT, Value: "Song"},
+		{AtOffset: 100 * time.Millisecond, Field: RDSFieldRT, Value: "Promo"},
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	for _, expected := range []string{"PS GORPITX", "RT Song", "RT Promo"} {
+		assert.Equal(t, expected, nextLine(t, lines))
+	}
+}
+
+func TestApplyRDSSchedule_Cancel(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 50*time.Millisecond,
+	)
+	defer cancel()
+
+	start := time.Now()
+
+	err := ApplyRDSSchedule(ctx, pipe, []RDSUpdate{
+		{AtOffset: time.Minute, Field: RDSFieldRT, Value: "Never"},
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestApplyRDSSchedule_InvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	pipe := filepath.Join(dir, "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	tests := []struct {
+		name        string
+		pipe        string
+		updates     []RDSUpdate
+		expectedErr error
+	}{
+		{
+			name: "PS too long",
+			pipe: pipe,
+			updates: []RDSUpdate{
+				{Field: RDSFieldPS, Value: "TOO LONG PS"},
+			},
+			expectedErr: ErrPSTooLong,
+		},
+		{
+			name: "unknown field",
+			pipe: pipe,
+			updates: []RDSUpdate{
+				{Field: "pty", Value: "10"},
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "offsets going backwards",
+			pipe: pipe,
+			updates: []RDSUpdate{
+				{AtOffset: time.Second, Field: RDSFieldRT, Value: "Song"},
+				{Field: RDSFieldRT, Value: "Promo"},
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "missing pipe",
+			pipe: filepath.Join(dir, "missing.pipe"),
+			updates: []RDSUpdate{
+				{Field: RDSFieldRT, Value: "Song"},
+			},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ApplyRDSSchedule(context.Background(), tt.pipe, tt.updates)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestPIFMRDS_ParseArgs_Schedule(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		expectedSchedule []RDSUpdate
+		expectedField    string
+		expectedErr      error
+	}{
+		{
+			name: "valid schedule",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule": []map[string]any{
+					{"field": "ps", "value": "GORPITX"},
+					{"atOffset": "90s", "field": "rt", "value": "Song"},
+					{"atOffset": "90s", "field": "ps", "value": "SONG"},
+				},
+			},
+			expectedSchedule: []RDSUpdate{
+				{Field: RDSFieldPS, Value: "GORPITX"},
+				{AtOffset: 90 * time.Second, Field: RDSFieldRT, Value: "Song"},
+				{AtOffset: 90 * time.Second, Field: RDSFieldPS, Value: "SONG"},
+			},
+		},
+		{
+			name: "without control pipe",
+			args: map[string]any{
+				"schedule": []map[string]any{
+					{"field": "rt", "value": "Song"},
+				},
+			},
+			expectedField: "schedule",
+			expectedErr:   commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "empty schedule",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule":    []map[string]any{},
+			},
+			expectedField: "schedule",
+			expectedErr:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "offsets going backwards",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule": []map[string]any{
+					{"atOffset": "1m", "field": "rt", "value": "Song"},
+					{"atOffset": "30s", "field": "rt", "value": "Promo"},
+				},
+			},
+			expectedField: "schedule[1]",
+			expectedErr:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "negative offset",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule": []map[string]any{
+					{"atOffset": "-1s", "field": "rt", "value": "Song"},
+				},
+			},
+			expectedField: "schedule[0]",
+			expectedErr:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "PS too long",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule": []map[string]any{
+					{"field": "ps", "value": "TOO LONG PS"},
+				},
+			},
+			expectedField: "schedule[0]",
+			expectedErr:   ErrPSTooLong,
+		},
+		{
+			name: "RT too long",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"schedule": []map[string]any{
+					{"field": "rt", "value": string(make([]byte, 65))},
+				},
+			},
+			expectedField: "schedule[0]",
+			expectedErr:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "PS updates with PS rotation",
+			args: map[string]any{
+				"controlPipe": pipe,
+				"psRotation":  []string{"RADIO"},
+				"schedule": []map[string]any{
+					{"field": "ps", "value": "SONG"},
+				},
+			},
+			expectedField: "schedule[0]",
+			expectedErr:   commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["freq"] = 107.9
+			tt.args["audio"] = ".fixtures/test.wav"
+
+			raw, err := json.Marshal(tt.args)
+			require.NoError(t, err)
+
+			module := &PIFMRDS{}
+
+			_, _, err = module.ParseArgs(raw)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				var errs ValidationErrors
+				require.ErrorAs(t, err, &errs)
+				assert.Equal(t, tt.expectedField, errs[0].Field)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSchedule, module.Schedule)
+		})
+	}
+}
+
+func TestPIFMRDS_ParseArgs_ScheduleInvalidOffset(t *testing.T) {
+	_, _, err := (&PIFMRDS{}).ParseArgs([]byte(`{
+		"freq": 107.9,
+		"audio": ".fixtures/test.wav",
+		"schedule": [{"atOffset": "soon", "field": "rt", "value": "Song"}]
+	}`))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestPIFMRDS_PrepareInput_Schedule(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "rds.pipe")
+	require.NoError(t, CreateControlPipe(pipe))
+
+	lines := readControlPipe(t, pipe)
+
+	module := &PIFMRDS{
+		ControlPipe: &pipe,
+		Schedule: []RDSUpdate{
+			{Field: RDSFieldPS, Value: "GORPITX"},
+			{AtOffset: 20 * time.Millisecond, Field: RDSFieldRT, Value: "Song"},
+			{AtOffset: time.Minute, Field: RDSFieldRT, Value: "Promo"},
+		},
+	}
+
+	args := []string{"-freq", "107.9", "-audio", "song.wav"}
+
+	prepared, cleanup, err := module.prepareInput(
+		context.Background(), commander.NewMock(), args,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, args, prepared)
+
+	assert.Equal(t, "PS GORPITX", nextLine(t, lines))
+	assert.Equal(t, "RT Song", nextLine(t, lines))
+
+	// Stopping the execution runs the cleanup, which ends the schedule
+	// before the last update instead of blocking for a minute
+	done := make(chan struct{})
+
+	go func() {
+		cleanup()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cleanup did not stop the schedule")
+	}
+
+	assert.Empty(t, lines)
+}
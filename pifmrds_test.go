@@ -1,10 +1,18 @@
 package gorpitx
 
 import (
+	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPIFMRDS_ParseArgs(t *testing.T) {
@@ -137,6 +145,56 @@ func TestPIFMRDS_buildArgs(t *testing.T) {
 	assert.Equal(t, expected, args)
 }
 
+func TestPIFMRDS_ResolvedArgs(t *testing.T) {
+	ppm := 2.5
+
+	tests := []struct {
+		name     string
+		args     string
+		expected PIFMRDS
+	}{
+		{
+			name: "binary defaults",
+			args: `{"freq": 107.9, "audio": ".fixtures/test.wav"}`,
+			expected: PIFMRDS{
+				Freq:  107.9,
+				Audio: ".fixtures/test.wav",
+				PI:    "1234",
+				PS:    "Pi-FmRds",
+				RT: "PiFmRds: live FM-RDS transmission from the " +
+					"RaspberryPi",
+				PPM: new(float64),
+			},
+		},
+		{
+			name: "set fields kept",
+			args: `{"freq": 107.9, "audio": ".fixtures/test.wav",
+				"pi": " abcd ", "ps": "TestPS", "rt": "Hello", "ppm": 2.5}`,
+			expected: PIFMRDS{
+				Freq:  107.9,
+				Audio: ".fixtures/test.wav",
+				PI:    "ABCD",
+				PS:    "TestPS",
+				RT:    "Hello",
+				PPM:   &ppm,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := &PIFMRDS{}
+			_, _, err := module.ParseArgs(json.RawMessage(tt.args))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, module.ResolvedArgs())
+
+			// The parsed args stay as given
+			assert.NotEqual(t, tt.expected, *module)
+		})
+	}
+}
+
 func TestPIFMRDS_validateFreq(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -206,6 +264,10 @@ func TestPIFMRDS_validatePS(t *testing.T) {
 		{"empty PS", "", false}, // empty is valid, gets default
 		{"too long PS", "123456789", true},
 		{"whitespace only", "   ", true},
+		{"punctuation", "R-P!@~ 1", false},
+		{"accented", "CAFÉ FM", true},
+		{"control character", "TEST\tFM", true},
+		{"DEL", "TEST\x7f", true},
 	}
 
 	for _, tt := range tests {
@@ -221,3 +283,192 @@ func TestPIFMRDS_validatePS(t *testing.T) {
 		})
 	}
 }
+
+// installResamplers puts fake executables with the given names on a PATH
+// of their own so findResampler only sees them.
+func installResamplers(t *testing.T, names ...string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), 0o700))
+	}
+
+	t.Setenv("PATH", dir)
+}
+
+func TestPIFMRDS_ParseArgs_ResampleTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		resamplers  []string
+		resampleTo  int
+		expectedErr error
+	}{
+		{
+			name:       "sox installed",
+			resamplers: []string{"sox"},
+			resampleTo: 48000,
+		},
+		{
+			name:       "ffmpeg installed",
+			resamplers: []string{"ffmpeg"},
+			resampleTo: 48000,
+		},
+		{
+			name:        "no resampler",
+			resampleTo:  48000,
+			expectedErr: ErrNoResampler,
+		},
+		{
+			name:        "zero rate",
+			resamplers:  []string{"sox"},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installResamplers(t, tt.resamplers...)
+
+			args, _, err := (&PIFMRDS{}).ParseArgs(json.RawMessage(
+				`{"freq": 107.9, "audio": ".fixtures/test.wav", ` +
+					`"resampleTo": ` + strconv.Itoa(tt.resampleTo) + `}`,
+			))
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			// The audio is only swapped for the resampled file at launch
+			assert.Equal(t, []string{
+				"-freq", "107.9", "-audio", ".fixtures/test.wav",
+			}, args)
+		})
+	}
+}
+
+func TestPIFMRDS_PrepareInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		resampler  string
+		expectArgs func(output string) []string
+	}{
+		{
+			name:      "sox",
+			resampler: "sox",
+			expectArgs: func(output string) []string {
+				return []string{"in.wav", "-r", "48000", output}
+			},
+		},
+		{
+			name:      "ffmpeg",
+			resampler: "ffmpeg",
+			expectArgs: func(output string) []string {
+				return []string{
+					"-y", "-loglevel", "error", "-i", "in.wav",
+					"-ar", "48000", output,
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installResamplers(t, tt.resampler)
+			t.Setenv("TMPDIR", t.TempDir())
+
+			// The temp file name is random, so match any args and check
+			// them from the call order
+			matchers := make([]commander.ArgumentMatcher, len(tt.expectArgs("")))
+			for i := range matchers {
+				matchers[i] = commander.Any()
+			}
+
+			mockCommander := commander.NewMock()
+			mockCommander.ExpectWithMatchers(tt.resampler, matchers...)
+
+			module := &PIFMRDS{ResampleTo: intPtr(48000)}
+			args, cleanup, err := module.prepareInput(
+				context.Background(),
+				mockCommander,
+				[]string{"-oL", "pifmrds", "-freq", "107.9", "-audio", "in.wav"},
+			)
+			require.NoError(t, err)
+
+			output := args[5]
+			assert.Equal(t, []string{
+				"-oL", "pifmrds", "-freq", "107.9", "-audio", output,
+			}, args)
+			assert.Equal(t, os.Getenv("TMPDIR"), filepath.Dir(output))
+			assert.Equal(t, []string{
+				tt.resampler + " " + strings.Join(tt.expectArgs(output), " "),
+			}, mockCommander.CallOrder())
+			assert.FileExists(t, output)
+
+			cleanup()
+			assert.NoFileExists(t, output)
+		})
+	}
+}
+
+func TestPIFMRDS_PrepareInput_Errors(t *testing.T) {
+	installResamplers(t, "sox")
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	cmdArgs := []string{"-freq", "107.9", "-audio", "in.wav"}
+
+	// Nothing to do without ResampleTo
+	args, cleanup, err := (&PIFMRDS{}).prepareInput(
+		context.Background(), commander.NewMock(), cmdArgs,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, cmdArgs, args)
+	cleanup()
+
+	// A failed conversion leaves no temp file behind
+	mockCommander := commander.NewMock()
+	mockCommander.ExpectWithMatchers(
+		"sox", commander.Any(), commander.Any(), commander.Any(),
+		commander.Any(),
+	).ReturnError(commonerrors.ErrFailed)
+
+	module := &PIFMRDS{ResampleTo: intPtr(48000)}
+	_, _, err = module.prepareInput(
+		context.Background(), mockCommander, cmdArgs,
+	)
+	require.ErrorIs(t, err, commonerrors.ErrFailed)
+	assert.Contains(t, err.Error(), "failed to resample in.wav to 48000 Hz")
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCreateControlPipe(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rds.pipe")
+
+	require.NoError(t, CreateControlPipe(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeNamedPipe)
+
+	// An existing FIFO is reused
+	require.NoError(t, CreateControlPipe(path))
+
+	regularFile := filepath.Join(dir, "regular")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0o600))
+	require.ErrorIs(
+		t, CreateControlPipe(regularFile), commonerrors.ErrInvalidValue,
+	)
+
+	require.ErrorIs(t, CreateControlPipe(" "), commonerrors.ErrRequiredFieldNotSet)
+	require.Error(t, CreateControlPipe(filepath.Join(dir, "missing", "rds.pipe")))
+}
@@ -3,6 +3,8 @@ package gorpitx
 import (
 	"encoding/json"
 	"io"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -16,20 +18,45 @@ const (
 
 const (
 	defaultPIRTTYSpaceFrequency = 170
+	defaultPIRTTYShift          = 170
 )
 
+// validPIRTTYShifts lists the mark/space shifts in Hz commonly used for RTTY.
+//
+//nolint:gochecknoglobals
+var validPIRTTYShifts = []int{170, 425, 850}
+
 type PIRTTY struct {
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// SpaceFrequency specifies the space frequency in Hz. Optional parameter.
-	// Default: 170 Hz (mark frequency will be space + 170)
-	SpaceFrequency *int `json:"spaceFrequency,omitempty"`
+	// Default: 170 Hz (mark frequency will be space + shift)
+	SpaceFrequency *int `json:"spaceFrequency,omitempty" schema:"exclusiveMinimum=0"` //nolint:lll
+
+	// Shift specifies the mark/space shift in Hz. Optional parameter.
+	// Valid values: 170, 425, 850. Default: 170 Hz
+	Shift *int `json:"shift,omitempty" schema:"enum=170|425|850"`
+
+	// Message specifies the text message to transmit in RTTY. Required
+	// unless MessageFile is set. Cannot be empty or whitespace only.
+	Message string `json:"message" schema:"optional"`
+
+	// MessageFile specifies a text file whose contents are transmitted
+	// instead of Message, trailing newlines removed. Cannot be specified
+	// together with Message.
+	MessageFile string `json:"messageFile,omitempty"`
 
-	// Message specifies the text message to transmit in RTTY. Required parameter.
-	// Cannot be empty or whitespace only.
-	Message string `json:"message"`
+	// UnshiftOnSpace tells pirtty whether the receiver returns to letters
+	// after a space (USOS). When true, FIGS is sent again before figures
+	// that follow a space, so "QTH 73 88" decodes right on USOS receivers.
+	// Passed as a trailing argument after the shift. Optional parameter.
+	// Default: the binary's behavior
+	UnshiftOnSpace *bool `json:"unshiftOnSpace,omitempty"`
+
+	// fileMessage holds the contents of MessageFile once validated.
+	fileMessage string
 }
 
 func (m *PIRTTY) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -54,34 +81,79 @@ func (m *PIRTTY) buildArgs() []string {
 		strconv.FormatFloat(m.Frequency, 'f', 0, 64))
 
 	// Add space frequency argument (default if not specified)
-	spaceFreq := defaultPIRTTYSpaceFrequency
-	if m.SpaceFrequency != nil {
-		spaceFreq = *m.SpaceFrequency
-	}
-
-	args = append(args, strconv.Itoa(spaceFreq))
+	args = append(args, strconv.Itoa(m.spaceFrequency()))
 
 	// Add message argument (required)
-	args = append(args, m.Message)
+	args = append(args, m.message())
+
+	// Add shift argument after the message so the positional layout stays
+	// compatible with the stock pirtty binary. USOS comes after it, so the
+	// shift is given whenever USOS is.
+	if m.Shift != nil || m.UnshiftOnSpace != nil {
+		args = append(args, strconv.Itoa(m.shift()))
+	}
+
+	if m.UnshiftOnSpace != nil {
+		args = append(args, m.unshiftOnSpaceArg())
+	}
 
 	return args
 }
 
-// validate validates all PIRTTY parameters.
-func (m *PIRTTY) validate() error {
-	if err := m.validateFrequency(); err != nil {
-		return err
+// unshiftOnSpaceArg returns the USOS argument, "1" for on and "0" for off.
+func (m *PIRTTY) unshiftOnSpaceArg() string {
+	if *m.UnshiftOnSpace {
+		return "1"
+	}
+
+	return "0"
+}
+
+// message returns the text to transmit, from Message or MessageFile.
+func (m *PIRTTY) message() string {
+	if m.MessageFile != "" {
+		return m.fileMessage
 	}
 
-	if err := m.validateSpaceFrequency(); err != nil {
-		return err
+	return m.Message
+}
+
+// MarkFrequency returns the mark tone in Hz, computed as space + shift.
+func (m *PIRTTY) MarkFrequency() int {
+	return m.spaceFrequency() + m.shift()
+}
+
+// spaceFrequency returns the space tone in Hz, falling back to the default.
+func (m *PIRTTY) spaceFrequency() int {
+	if m.SpaceFrequency != nil {
+		return *m.SpaceFrequency
 	}
 
-	if err := m.validateMessage(); err != nil {
-		return err
+	return defaultPIRTTYSpaceFrequency
+}
+
+// shift returns the mark/space shift in Hz, falling back to the default.
+func (m *PIRTTY) shift() int {
+	if m.Shift != nil {
+		return *m.Shift
 	}
 
-	return nil
+	return defaultPIRTTYShift
+}
+
+// validate validates all PIRTTY parameters.
+func (m *PIRTTY) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"spaceFrequency", m.validateSpaceFrequency},
+		fieldCheck{"shift", m.validateShift},
+		fieldCheck{"message", m.validateMessage},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *PIRTTY) frequencyHz() float64 {
+	return m.Frequency
 }
 
 // validateFrequency validates the frequency parameter.
@@ -99,7 +171,7 @@ func (m *PIRTTY) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -119,11 +191,71 @@ func (m *PIRTTY) validateSpaceFrequency() error {
 	return nil
 }
 
-// validateMessage validates the message parameter.
+// validateShift validates the shift parameter.
+func (m *PIRTTY) validateShift() error {
+	if m.Shift == nil {
+		return nil
+	}
+
+	if !slices.Contains(validPIRTTYShifts, *m.Shift) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"shift must be one of %v Hz, got: %d",
+			validPIRTTYShifts, *m.Shift,
+		)
+	}
+
+	return nil
+}
+
+// validateMessage validates the message parameter, reading MessageFile
+// when it is set.
 func (m *PIRTTY) validateMessage() error {
+	if m.MessageFile != "" {
+		return fieldError("messageFile", m.loadMessageFile())
+	}
+
 	if strings.TrimSpace(m.Message) == "" {
 		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "message")
 	}
 
 	return nil
 }
+
+// loadMessageFile reads the message from MessageFile.
+func (m *PIRTTY) loadMessageFile() error {
+	if m.Message != "" {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"message and messageFile cannot both be specified",
+		)
+	}
+
+	data, err := os.ReadFile(m.MessageFile)
+	if os.IsNotExist(err) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"message file: %s",
+			m.MessageFile,
+		)
+	}
+
+	if err != nil {
+		return ctxerrors.Wrapf(
+			err, "failed to read message file: %s", m.MessageFile,
+		)
+	}
+
+	message := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(message) == "" {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrRequiredFieldNotSet,
+			"message file is empty: %s",
+			m.MessageFile,
+		)
+	}
+
+	m.fileMessage = message
+
+	return nil
+}
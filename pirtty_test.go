@@ -2,6 +2,8 @@ package gorpitx
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
@@ -57,6 +59,17 @@ func TestPIRTTY_ParseArgs_Success(t *testing.T) {
 			expectedArgs:  []string{"14070000", "170", "DEFAULT SPACE TEST"},
 			expectedStdin: false,
 		},
+		{
+			name: "PIRTTY with 850 Hz shift",
+			input: PIRTTY{
+				Frequency:      14070000.0, // 14.070 MHz
+				SpaceFrequency: intPtr(1275),
+				Shift:          intPtr(850),
+				Message:        "WIDE SHIFT",
+			},
+			expectedArgs:  []string{"14070000", "1275", "WIDE SHIFT", "850"},
+			expectedStdin: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -333,6 +346,36 @@ func TestPIRTTY_buildArgs(t *testing.T) {
 			},
 			expectedArgs: []string{"14070000", "170", "DEFAULT TEST"},
 		},
+		{
+			name: "unshift on space with default shift",
+			pirtty: PIRTTY{
+				Frequency:      14070000.0,
+				Message:        "USOS",
+				UnshiftOnSpace: boolPtr(true),
+			},
+			expectedArgs: []string{"14070000", "170", "USOS", "170", "1"},
+		},
+		{
+			name: "unshift on space off with shift",
+			pirtty: PIRTTY{
+				Frequency:      14070000.0,
+				Message:        "NO USOS",
+				Shift:          intPtr(850),
+				UnshiftOnSpace: boolPtr(false),
+			},
+			expectedArgs: []string{
+				"14070000", "170", "NO USOS", "850", "0",
+			},
+		},
+		{
+			name: "message from file",
+			pirtty: PIRTTY{
+				Frequency:   14070000.0,
+				MessageFile: "message.txt",
+				fileMessage: "FROM FILE",
+			},
+			expectedArgs: []string{"14070000", "170", "FROM FILE"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -342,3 +385,146 @@ func TestPIRTTY_buildArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestPIRTTY_validateShift(t *testing.T) {
+	tests := []struct {
+		name        string
+		shift       *int
+		expectError bool
+	}{
+		{name: "nil shift", shift: nil},
+		{name: "170 Hz", shift: intPtr(170)},
+		{name: "425 Hz", shift: intPtr(425)},
+		{name: "850 Hz", shift: intPtr(850)},
+		{name: "unsupported shift", shift: intPtr(200), expectError: true},
+		{name: "zero shift", shift: intPtr(0), expectError: true},
+		{name: "negative shift", shift: intPtr(-170), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &PIRTTY{Shift: tt.shift}
+
+			err := m.validateShift()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPIRTTY_MarkFrequency(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    PIRTTY
+		expected int
+	}{
+		{
+			name:     "defaults",
+			input:    PIRTTY{},
+			expected: 340,
+		},
+		{
+			name:     "space only",
+			input:    PIRTTY{SpaceFrequency: intPtr(1955)},
+			expected: 2125,
+		},
+		{
+			name: "space and 425 Hz shift",
+			input: PIRTTY{
+				SpaceFrequency: intPtr(1700),
+				Shift:          intPtr(425),
+			},
+			expected: 2125,
+		},
+		{
+			name:     "shift only",
+			input:    PIRTTY{Shift: intPtr(850)},
+			expected: 1020,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.input.MarkFrequency())
+		})
+	}
+}
+
+func TestPIRTTY_ParseArgs_MessageFile(t *testing.T) {
+	dir := t.TempDir()
+
+	messageFile := filepath.Join(dir, "message.txt")
+	require.NoError(t, os.WriteFile(
+		messageFile, []byte("CQ CQ DE TEST\n"), 0o600,
+	))
+
+	emptyFile := filepath.Join(dir, "empty.txt")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0o600))
+
+	tests := []struct {
+		name         string
+		input        map[string]any
+		expectedArgs []string
+		expectedErr  error
+	}{
+		{
+			name: "message from file",
+			input: map[string]any{
+				"frequency":   14070000.0,
+				"messageFile": messageFile,
+			},
+			expectedArgs: []string{"14070000", "170", "CQ CQ DE TEST"},
+		},
+		{
+			name: "missing file",
+			input: map[string]any{
+				"frequency":   14070000.0,
+				"messageFile": filepath.Join(dir, "missing.txt"),
+			},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+		{
+			name: "empty file",
+			input: map[string]any{
+				"frequency":   14070000.0,
+				"messageFile": emptyFile,
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "message and file together",
+			input: map[string]any{
+				"frequency":   14070000.0,
+				"message":     "TEST",
+				"messageFile": messageFile,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+
+			pirtty := &PIRTTY{}
+			args, _, err := pirtty.ParseArgs(input)
+
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
@@ -12,6 +13,26 @@ import (
 
 const (
 	ModuleNamePISSSTV ModuleName = "pisstv"
+
+	sstvImageWidth   = 320 // Martin 1 pictures are 320 pixels wide
+	rgbBytesPerPixel = 3   // Raw RGB files store 3 bytes per pixel
+	sstvHeightMartin = 256 // Martin 1 uses 256 lines
+
+	// sstvMartin1LineDuration is the time Martin 1 takes to send one
+	// picture line, sync and color scans included
+	sstvMartin1LineDuration = 446446 * time.Microsecond
+
+	// sstvVISDuration is the calibration header sent before the picture:
+	// two 300ms leader tones around a 10ms break and 10 VIS bits of 30ms
+	sstvVISDuration = 910 * time.Millisecond
+)
+
+// SSTVMode identifies the SSTV protocol used for the transmission.
+type SSTVMode = string
+
+const (
+	// SSTVModeMartin1 is the only mode the rpitx pisstv binary sends.
+	SSTVModeMartin1 SSTVMode = "martin1"
 )
 
 type PISSTV struct {
@@ -22,7 +43,13 @@ type PISSTV struct {
 
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
+
+	// Mode specifies the SSTV mode. Optional parameter. The rpitx pisstv
+	// binary only sends Martin 1, so "martin1" is the only mode accepted.
+	// When set, the picture must be the 320x256 Martin 1 resolution. When
+	// empty, any height is accepted.
+	Mode SSTVMode `json:"mode,omitempty" schema:"enum=martin1"`
 }
 
 func (m *PISSTV) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -48,20 +75,18 @@ func (m *PISSTV) buildArgs() []string {
 	// Add frequency argument (required)
 	args = append(args, strconv.FormatFloat(m.Frequency, 'f', 0, 64))
 
+	// The mode isn't passed, the binary always sends Martin 1
+
 	return args
 }
 
 // validate validates all PISSTV parameters.
 func (m *PISSTV) validate() error {
-	if err := m.validatePictureFile(); err != nil {
-		return err
-	}
-
-	if err := m.validateFrequency(); err != nil {
-		return err
-	}
-
-	return nil
+	return validateFields(
+		fieldCheck{"pictureFile", m.validatePictureFile},
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"mode", m.validateMode},
+	)
 }
 
 // validatePictureFile validates the picture file parameter.
@@ -82,6 +107,11 @@ func (m *PISSTV) validatePictureFile() error {
 	return nil
 }
 
+// frequencyHz returns the carrier frequency in Hz.
+func (m *PISSTV) frequencyHz() float64 {
+	return m.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (m *PISSTV) validateFrequency() error {
 	if m.Frequency <= 0 {
@@ -97,9 +127,67 @@ func (m *PISSTV) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
+		)
+	}
+
+	return nil
+}
+
+// validateMode validates the SSTV mode and cross-checks the picture file
+// size against the Martin 1 resolution.
+func (m *PISSTV) validateMode() error {
+	// Mode is optional
+	if m.Mode == "" {
+		return nil
+	}
+
+	if m.Mode != SSTVModeMartin1 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unsupported SSTV mode: %s, the rpitx pisstv binary only "+
+				"sends %s",
+			m.Mode, SSTVModeMartin1,
+		)
+	}
+
+	// A missing picture is reported by validatePictureFile
+	if m.validatePictureFile() != nil {
+		return nil
+	}
+
+	info, err := os.Stat(m.PictureFile)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "failed to stat picture file: %s",
+			m.PictureFile)
+	}
+
+	expectedSize := int64(sstvImageWidth * sstvHeightMartin * rgbBytesPerPixel)
+	if info.Size() != expectedSize {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"SSTV mode %s expects a %dx%d RGB picture (%d bytes), got: %d bytes",
+			m.Mode, sstvImageWidth, sstvHeightMartin, expectedSize, info.Size(),
 		)
 	}
 
 	return nil
 }
+
+// validSSTVModes returns all supported SSTV modes.
+func validSSTVModes() []SSTVMode {
+	return []SSTVMode{SSTVModeMartin1}
+}
+
+// estimatedDuration returns how long the picture takes to send: the VIS
+// header and one Martin 1 scan line time per picture line.
+func (m *PISSTV) estimatedDuration() (time.Duration, bool) {
+	info, err := os.Stat(m.PictureFile)
+	if err != nil {
+		return 0, false
+	}
+
+	lines := info.Size() / (sstvImageWidth * rgbBytesPerPixel)
+
+	return sstvVISDuration + time.Duration(lines)*sstvMartin1LineDuration, true
+}
@@ -3,6 +3,7 @@ package gorpitx
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/stretchr/testify/assert"
@@ -329,3 +330,137 @@ func TestPISSTVModule_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPISSTVModule_ValidateMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		pisstv      PISSTV
+		expectError bool
+		errorType   error
+	}{
+		{
+			name: "empty mode accepts any height",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/test_320x100.rgb",
+			},
+			expectError: false,
+		},
+		{
+			name: "martin1 with 320x256 picture",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/martin1.rgb",
+				Mode:        SSTVModeMartin1,
+			},
+			expectError: false,
+		},
+		{
+			name: "scottie1 not sent by the binary",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/martin1.rgb",
+				Mode:        "scottie1",
+			},
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "martin1 with 320x100 picture",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/test_320x100.rgb",
+				Mode:        SSTVModeMartin1,
+			},
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "unknown mode",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/martin1.rgb",
+				Mode:        "pd120",
+			},
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pisstv.validateMode()
+
+			if tt.expectError {
+				assert.Error(t, err)
+
+				if tt.errorType != nil {
+					assert.ErrorIs(t, err, tt.errorType)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPISSTVModule_ParseArgs_WithMode(t *testing.T) {
+	pisstv := &PISSTV{}
+	input := []byte(`{
+		"pictureFile": ".fixtures/martin1.rgb",
+		"frequency": 144500000,
+		"mode": "martin1"
+	}`)
+
+	// The binary always sends Martin 1 and takes no mode argument
+	args, stdin, err := pisstv.ParseArgs(input)
+	require.NoError(t, err)
+	assert.Nil(t, stdin)
+	assert.Equal(t, []string{".fixtures/martin1.rgb", "144500000"}, args)
+}
+
+func TestPISSTVModule_EstimatedDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		pisstv     PISSTV
+		expected   time.Duration
+		expectedOK bool
+	}{
+		{
+			name: "martin1",
+			pisstv: PISSTV{
+				PictureFile: ".fixtures/martin1.rgb",
+				Mode:        SSTVModeMartin1,
+			},
+			expected:   910*time.Millisecond + 256*446446*time.Microsecond,
+			expectedOK: true,
+		},
+		{
+			name:       "no mode uses martin1 timing",
+			pisstv:     PISSTV{PictureFile: ".fixtures/test_320x100.rgb"},
+			expected:   910*time.Millisecond + 100*446446*time.Microsecond,
+			expectedOK: true,
+		},
+		{
+			name:   "missing picture",
+			pisstv: PISSTV{PictureFile: ".fixtures/missing.rgb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := tt.pisstv.estimatedDuration()
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestRPITX_ValidateArgs_PISSTVModeNotCarriedOver(t *testing.T) {
+	rpitx := &RPITX{modules: defaultModules()}
+
+	require.NoError(t, rpitx.ValidateArgs(ModuleNamePISSSTV, []byte(
+		`{"pictureFile": ".fixtures/martin1.rgb", "frequency": 144500000, `+
+			`"mode": "martin1"}`,
+	)))
+
+	// Without a mode any height is accepted, whatever the previous args
+	require.NoError(t, rpitx.ValidateArgs(ModuleNamePISSSTV, []byte(
+		`{"pictureFile": ".fixtures/test_320x100.rgb", "frequency": 144500000}`,
+	)))
+}
@@ -1,11 +1,15 @@
 package gorpitx
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
+	"os"
 	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
 	"github.com/psyb0t/ctxerrors"
@@ -13,26 +17,54 @@ import (
 
 const (
 	ModuleNamePOCSAG ModuleName = "pocsag"
+
+	// pocsagNumericCharset lists the characters a numeric pager can display.
+	pocsagNumericCharset = "0123456789U -[]"
+
+	// pocsagFunctionLetters are the pager labels of function bits 0-3.
+	pocsagFunctionLetters = "ABCD"
+
+	// defaultPOCSAGRepeatCount is the pocsag binary's own repeat count.
+	defaultPOCSAGRepeatCount = 4
+
+	// defaultPOCSAGMaxMessageLen is the longest alphanumeric message given
+	// in Messages accepted by default, what most alphanumeric pagers
+	// display before truncating.
+	defaultPOCSAGMaxMessageLen = 80
 )
 
 type POCSAG struct {
 	// `-f` specifies the frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// `-r` specifies the baud rate. Optional, must be 512, 1200, or 2400.
 	// Defaults to 1200 baud.
-	BaudRate *int `json:"baudRate,omitempty"`
+	BaudRate *int `json:"baudRate,omitempty" schema:"enum=512|1200|2400"`
 
-	// `-b` specifies the function bits. Optional, must be 0-3.
-	// Defaults to 3.
-	FunctionBits *int `json:"functionBits,omitempty"`
+	// `-b` specifies the function bits. Optional, must be 0-3 or, in JSON,
+	// the pager letters "A"-"D". Defaults to 3.
+	FunctionBits *int `json:"functionBits,omitempty" schema:"minimum=0;maximum=3"`
 
 	// `-n` flag enables numeric mode. Optional, defaults to false.
 	NumericMode *bool `json:"numericMode,omitempty"`
 
 	// `-t` specifies the repeat count. Optional, defaults to 4.
-	RepeatCount *int `json:"repeatCount,omitempty"`
+	RepeatCount *int `json:"repeatCount,omitempty" schema:"exclusiveMinimum=0"`
+
+	// GapMs specifies the pause between repeats in milliseconds. Optional,
+	// must not be negative. When set, each repeat is a separate run of the
+	// binary with `-t 1`, so the carrier is off during the gap instead of
+	// being held between repeats.
+	GapMs *int `json:"gapMs,omitempty" schema:"minimum=0"`
+
+	// MaxMessageLen specifies the longest message text accepted, in
+	// characters. Optional, must not be negative, 0 disables the check.
+	// When set it applies to every message. When unset only alphanumeric
+	// messages given in Messages are limited, to 80 characters, the display
+	// limit of most alphanumeric pagers, so numeric pages and messages from
+	// MessagesFile stay unlimited as they were before the check existed.
+	MaxMessageLen *int `json:"maxMessageLen,omitempty" schema:"minimum=0"`
 
 	// `-i` flag inverts polarity. Optional, defaults to false.
 	InvertPolarity *bool `json:"invertPolarity,omitempty"`
@@ -41,19 +73,30 @@ type POCSAG struct {
 	Debug *bool `json:"debug,omitempty"`
 
 	// Messages array specifies the address:message pairs to transmit.
-	// Required, must have at least one message.
-	Messages []POCSAGMessage `json:"messages"`
+	// Required unless MessagesFile is set, must have at least one message.
+	Messages []POCSAGMessage `json:"messages" schema:"optional;minItems=1"`
+
+	// MessagesFile specifies a file with one address:message pair per line,
+	// the same format sent to the pocsag binary on stdin. Blank lines are
+	// ignored. Optional, cannot be combined with Messages.
+	MessagesFile string `json:"messagesFile,omitempty"`
 }
 
 type POCSAGMessage struct {
 	// Address specifies the pager address. Required.
-	Address int `json:"address"`
+	Address int `json:"address" schema:"minimum=0"`
 
 	// Message specifies the message text to transmit. Required.
 	Message string `json:"message"`
 
 	// FunctionBits optionally overrides the global function bits for this message.
-	FunctionBits *int `json:"functionBits,omitempty"`
+	// Like the global one it takes 0-3 or "A"-"D" in JSON.
+	FunctionBits *int `json:"functionBits,omitempty" schema:"minimum=0;maximum=3"`
+
+	// Numeric optionally overrides the global numeric mode for this message.
+	// The binary sends every message of a run in the same mode, so all
+	// messages must end up with the same type.
+	Numeric *bool `json:"numeric,omitempty"`
 }
 
 func (m *POCSAG) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
@@ -61,6 +104,10 @@ func (m *POCSAG) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
 		return nil, nil, ctxerrors.Wrap(err, "failed to unmarshal args")
 	}
 
+	if err := m.loadMessagesFile(); err != nil {
+		return nil, nil, err
+	}
+
 	if err := m.validate(); err != nil {
 		return nil, nil, err
 	}
@@ -71,6 +118,110 @@ func (m *POCSAG) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
 	return cmdArgs, stdin, nil
 }
 
+// UnmarshalJSON decodes a POCSAG config, taking FunctionBits as a number or
+// a pager letter.
+func (m *POCSAG) UnmarshalJSON(data []byte) error {
+	type plainPOCSAG POCSAG
+
+	aux := struct {
+		*plainPOCSAG
+
+		FunctionBits json.RawMessage `json:"functionBits,omitempty"`
+	}{plainPOCSAG: (*plainPOCSAG)(m)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return ctxerrors.Wrap(err, "failed to unmarshal POCSAG")
+	}
+
+	bits, err := parsePOCSAGFunctionBits(aux.FunctionBits)
+	if err != nil {
+		return ctxerrors.Wrap(err, "functionBits")
+	}
+
+	m.FunctionBits = bits
+
+	return nil
+}
+
+// UnmarshalJSON decodes a POCSAG message, taking FunctionBits as a number or
+// a pager letter.
+func (msg *POCSAGMessage) UnmarshalJSON(data []byte) error {
+	type plainPOCSAGMessage POCSAGMessage
+
+	aux := struct {
+		*plainPOCSAGMessage
+
+		FunctionBits json.RawMessage `json:"functionBits,omitempty"`
+	}{plainPOCSAGMessage: (*plainPOCSAGMessage)(msg)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return ctxerrors.Wrap(err, "failed to unmarshal message")
+	}
+
+	bits, err := parsePOCSAGFunctionBits(aux.FunctionBits)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "message %d functionBits", msg.Address)
+	}
+
+	msg.FunctionBits = bits
+
+	return nil
+}
+
+// parsePOCSAGFunctionBits decodes function bits given as a number or as one
+// of the pager letters "A"-"D", which map to 0-3. Numbers are range checked
+// by validation, letters outside "A"-"D" are rejected here.
+func parsePOCSAGFunctionBits(data json.RawMessage) (*int, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil //nolint:nilnil // function bits are optional
+	}
+
+	var bits int
+	if err := json.Unmarshal(data, &bits); err == nil {
+		return &bits, nil
+	}
+
+	var letter string
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"must be 0-3 or A-D, got: %s",
+			data,
+		)
+	}
+
+	bits = strings.Index(pocsagFunctionLetters, letter)
+	if len(letter) != 1 || bits < 0 {
+		return nil, ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"must be 0-3 or A-D, got: %q",
+			letter,
+		)
+	}
+
+	return &bits, nil
+}
+
+// usesStdin reports that the messages are fed to the binary through stdin.
+func (m *POCSAG) usesStdin() bool {
+	return true
+}
+
+// repeatSpacing returns how many times the binary runs and the gap between
+// runs when GapMs is set.
+func (m *POCSAG) repeatSpacing() (int, int, bool) {
+	if m.GapMs == nil {
+		return 0, 0, false
+	}
+
+	count := defaultPOCSAGRepeatCount
+	if m.RepeatCount != nil {
+		count = *m.RepeatCount
+	}
+
+	return count, *m.GapMs, true
+}
+
 // buildArgs converts the struct fields into command-line arguments for pocsag
 // binary.
 func (m *POCSAG) buildArgs() []string {
@@ -92,13 +243,16 @@ func (m *POCSAG) buildArgs() []string {
 			strconv.Itoa(*m.FunctionBits))
 	}
 
-	// Add numeric mode flag
-	if m.NumericMode != nil && *m.NumericMode {
+	// Add numeric mode flag, validation keeps every message the same type
+	if m.isNumeric() {
 		args = append(args, "-n")
 	}
 
-	// Add repeat count argument
-	if m.RepeatCount != nil {
+	// Add repeat count argument, repeats spaced by a gap are run one by one
+	switch {
+	case m.GapMs != nil:
+		args = append(args, "-t", "1")
+	case m.RepeatCount != nil:
 		args = append(args, "-t",
 			strconv.Itoa(*m.RepeatCount))
 	}
@@ -132,31 +286,114 @@ func (m *POCSAG) buildStdin() io.Reader {
 	return strings.NewReader(stdinContent)
 }
 
-// validate validates all POCSAG parameters.
-func (m *POCSAG) validate() error {
-	if err := m.validateFrequency(); err != nil {
-		return err
+// loadMessagesFile reads messages from MessagesFile into Messages.
+func (m *POCSAG) loadMessagesFile() error {
+	// Messages file is optional
+	if m.MessagesFile == "" {
+		return nil
 	}
 
-	if err := m.validateBaudRate(); err != nil {
-		return err
+	if len(m.Messages) > 0 {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"messages and messagesFile cannot both be specified",
+		)
 	}
 
-	if err := m.validateFunctionBits(); err != nil {
-		return err
-	}
+	file, err := os.Open(m.MessagesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrFileNotFound,
+				"messages file: %s",
+				m.MessagesFile,
+			)
+		}
 
-	if err := m.validateRepeatCount(); err != nil {
-		return err
+		return ctxerrors.Wrapf(
+			err,
+			"failed to open messages file: %s",
+			m.MessagesFile,
+		)
 	}
 
-	if err := m.validateMessages(); err != nil {
-		return err
+	defer func() { _ = file.Close() }()
+
+	messages, err := parsePOCSAGMessages(file)
+	if err != nil {
+		return ctxerrors.Wrapf(err, "messages file: %s", m.MessagesFile)
 	}
 
+	m.Messages = messages
+
 	return nil
 }
 
+// parsePOCSAGMessages parses address:message lines into POCSAG messages.
+func parsePOCSAGMessages(r io.Reader) ([]POCSAGMessage, error) {
+	var messages []POCSAGMessage
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Only split on the first colon, the message itself may contain more
+		addressStr, message, found := strings.Cut(line, ":")
+		if !found {
+			return nil, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"line %d: missing ':' between address and message",
+				lineNum,
+			)
+		}
+
+		address, err := strconv.Atoi(strings.TrimSpace(addressStr))
+		if err != nil {
+			return nil, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"line %d: address must be numeric, got: %s",
+				lineNum, addressStr,
+			)
+		}
+
+		messages = append(messages, POCSAGMessage{
+			Address: address,
+			Message: message,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to read messages")
+	}
+
+	return messages, nil
+}
+
+// validate validates all POCSAG parameters.
+func (m *POCSAG) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"baudRate", m.validateBaudRate},
+		fieldCheck{"functionBits", m.validateFunctionBits},
+		fieldCheck{"repeatCount", m.validateRepeatCount},
+		fieldCheck{"gapMs", m.validateGapMs},
+		fieldCheck{"maxMessageLen", m.validateMaxMessageLen},
+		fieldCheck{"messages", m.validateMessages},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *POCSAG) frequencyHz() float64 {
+	return m.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (m *POCSAG) validateFrequency() error {
 	if m.Frequency <= 0 {
@@ -172,7 +409,7 @@ func (m *POCSAG) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -235,6 +472,57 @@ func (m *POCSAG) validateRepeatCount() error {
 	return nil
 }
 
+// validateGapMs validates the gap between repeats.
+func (m *POCSAG) validateGapMs() error {
+	// Gap is optional
+	if m.GapMs == nil {
+		return nil
+	}
+
+	if *m.GapMs < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"gapMs must not be negative, got: %d",
+			*m.GapMs,
+		)
+	}
+
+	return nil
+}
+
+// validateMaxMessageLen validates the message length limit.
+func (m *POCSAG) validateMaxMessageLen() error {
+	// Limit is optional
+	if m.MaxMessageLen == nil {
+		return nil
+	}
+
+	if *m.MaxMessageLen < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"maxMessageLen must not be negative, got: %d",
+			*m.MaxMessageLen,
+		)
+	}
+
+	return nil
+}
+
+// maxMessageLen returns the length limit of a message, 0 meaning
+// unlimited. The default only covers alphanumeric messages not read from
+// MessagesFile.
+func (m *POCSAG) maxMessageLen(msg POCSAGMessage) int {
+	if m.MaxMessageLen != nil {
+		return *m.MaxMessageLen
+	}
+
+	if m.MessagesFile != "" || m.isNumericMessage(msg) {
+		return 0
+	}
+
+	return defaultPOCSAGMaxMessageLen
+}
+
 // validateMessages validates the messages array.
 func (m *POCSAG) validateMessages() error {
 	// Messages array is required
@@ -242,10 +530,37 @@ func (m *POCSAG) validateMessages() error {
 		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "messages")
 	}
 
-	// Validate each message
+	// Validate each message, reporting every invalid one
+	var errs ValidationErrors
+
 	for i, msg := range m.Messages {
 		if err := m.validateMessage(msg, i); err != nil {
-			return err
+			errs = append(errs, newValidationError(
+				fmt.Sprintf("messages[%d]", i), err,
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return m.validateMessageTypes()
+}
+
+// validateMessageTypes checks every message is sent in the same mode, the
+// binary switches numeric mode per run with `-n`, not per message.
+func (m *POCSAG) validateMessageTypes() error {
+	numeric := m.isNumericMessage(m.Messages[0])
+
+	for i, msg := range m.Messages[1:] {
+		if m.isNumericMessage(msg) != numeric {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"message[%d] and message[0] mix numeric and alphanumeric "+
+					"pages, send them as separate transmissions",
+				i+1,
+			)
 		}
 	}
 
@@ -272,6 +587,26 @@ func (m *POCSAG) validateMessage(msg POCSAGMessage, index int) error {
 		)
 	}
 
+	// Longer messages get truncated by the pager
+	maxLen := m.maxMessageLen(msg)
+	if length := utf8.RuneCountInString(msg.Message); maxLen > 0 &&
+		length > maxLen {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"message[%d].message is %d characters, max is %d",
+			index, length, maxLen,
+		)
+	}
+
+	// Numeric pagers can only display a restricted charset
+	if m.isNumericMessage(msg) && !isPOCSAGNumericText(msg.Message) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"message[%d].message must only contain %q in numeric mode, got: %s",
+			index, pocsagNumericCharset, msg.Message,
+		)
+	}
+
 	// Validate per-message function bits if specified
 	if msg.FunctionBits != nil {
 		if *msg.FunctionBits < 0 || *msg.FunctionBits > 3 {
@@ -285,3 +620,34 @@ func (m *POCSAG) validateMessage(msg POCSAGMessage, index int) error {
 
 	return nil
 }
+
+// isNumeric reports whether the batch is sent in numeric mode, taking the
+// type of the messages once validation made them all the same.
+func (m *POCSAG) isNumeric() bool {
+	if len(m.Messages) > 0 {
+		return m.isNumericMessage(m.Messages[0])
+	}
+
+	return m.NumericMode != nil && *m.NumericMode
+}
+
+// isNumericMessage reports whether a message is sent as a numeric page,
+// using the per-message override when set and the global mode otherwise.
+func (m *POCSAG) isNumericMessage(msg POCSAGMessage) bool {
+	if msg.Numeric != nil {
+		return *msg.Numeric
+	}
+
+	return m.NumericMode != nil && *m.NumericMode
+}
+
+// isPOCSAGNumericText checks if text only contains numeric pager characters.
+func isPOCSAGNumericText(text string) bool {
+	for _, r := range text {
+		if !strings.ContainsRune(pocsagNumericCharset, r) {
+			return false
+		}
+	}
+
+	return true
+}
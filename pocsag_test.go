@@ -3,6 +3,10 @@ package gorpitx
 import (
 	"encoding/json"
 	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	commonerrors "github.com/psyb0t/common-go/errors"
@@ -44,7 +48,7 @@ func TestPOCSAG_ParseArgs(t *testing.T) {
 				"messages": []map[string]any{
 					{
 						"address": 123456,
-						"message": "Alert message",
+						"message": "911-0042",
 					},
 				},
 			},
@@ -411,6 +415,21 @@ func TestPOCSAG_BuildArgs(t *testing.T) {
 			},
 			expectArgs: []string{"-f", "466230000"},
 		},
+		{
+			name: "gap runs repeats one by one",
+			pocsag: POCSAG{
+				Frequency:   466230000.0,
+				RepeatCount: intPtr(3),
+				GapMs:       intPtr(500),
+				Messages: []POCSAGMessage{
+					{
+						Address: 777,
+						Message: "Spaced message",
+					},
+				},
+			},
+			expectArgs: []string{"-f", "466230000", "-t", "1"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -692,6 +711,95 @@ func TestPOCSAG_ValidateFunctionBits(t *testing.T) {
 	}
 }
 
+func TestPOCSAG_FunctionBitLetters(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        string
+		expectArgs  []string
+		expectStdin string
+		expectedErr error
+	}{
+		{
+			name: "global letter",
+			args: `{"frequency": 466230000, "functionBits": "C", ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectArgs:  []string{"-f", "466230000", "-b", "2"},
+			expectStdin: "1:hi",
+		},
+		{
+			name: "number still accepted",
+			args: `{"frequency": 466230000, "functionBits": 1, ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectArgs:  []string{"-f", "466230000", "-b", "1"},
+			expectStdin: "1:hi",
+		},
+		{
+			name: "per-message letters",
+			args: `{"frequency": 466230000, "messages": [` +
+				`{"address": 1, "message": "a", "functionBits": "A"}, ` +
+				`{"address": 2, "message": "d", "functionBits": "D"}]}`,
+			expectArgs:  []string{"-f", "466230000"},
+			expectStdin: "1:a\n2:d",
+		},
+		{
+			name: "letter outside A-D",
+			args: `{"frequency": 466230000, "functionBits": "E", ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "lowercase letter",
+			args: `{"frequency": 466230000, "messages": [` +
+				`{"address": 1, "message": "hi", "functionBits": "b"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "more than one letter",
+			args: `{"frequency": 466230000, "functionBits": "AB", ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "empty string",
+			args: `{"frequency": 466230000, "functionBits": "", ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "wrong type",
+			args: `{"frequency": 466230000, "functionBits": true, ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "number out of range",
+			args: `{"frequency": 466230000, "functionBits": 4, ` +
+				`"messages": [{"address": 1, "message": "hi"}]}`,
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pocsag := &POCSAG{}
+
+			args, stdin, err := pocsag.ParseArgs(json.RawMessage(tt.args))
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectArgs, args)
+
+			stdinContent, err := io.ReadAll(stdin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectStdin, string(stdinContent))
+		})
+	}
+}
+
 func TestPOCSAG_ValidateRepeatCount(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -753,6 +861,152 @@ func TestPOCSAG_ValidateRepeatCount(t *testing.T) {
 	}
 }
 
+func TestPOCSAG_ValidateGapMs(t *testing.T) {
+	tests := []struct {
+		name        string
+		gapMs       *int
+		expectError bool
+	}{
+		{name: "nil gap (optional)"},
+		{name: "zero gap", gapMs: intPtr(0)},
+		{name: "positive gap", gapMs: intPtr(1500)},
+		{name: "negative gap", gapMs: intPtr(-1), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&POCSAG{GapMs: tt.gapMs}).validateGapMs()
+			if tt.expectError {
+				require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPOCSAG_RepeatSpacing(t *testing.T) {
+	tests := []struct {
+		name          string
+		pocsag        POCSAG
+		expectCount   int
+		expectGapMs   int
+		expectSpacing bool
+	}{
+		{
+			name:   "no gap",
+			pocsag: POCSAG{RepeatCount: intPtr(3)},
+		},
+		{
+			name:          "gap with default repeat count",
+			pocsag:        POCSAG{GapMs: intPtr(250)},
+			expectCount:   defaultPOCSAGRepeatCount,
+			expectGapMs:   250,
+			expectSpacing: true,
+		},
+		{
+			name:          "gap with repeat count",
+			pocsag:        POCSAG{RepeatCount: intPtr(2), GapMs: intPtr(0)},
+			expectCount:   2,
+			expectSpacing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, gapMs, ok := tt.pocsag.repeatSpacing()
+			assert.Equal(t, tt.expectSpacing, ok)
+			assert.Equal(t, tt.expectCount, count)
+			assert.Equal(t, tt.expectGapMs, gapMs)
+		})
+	}
+}
+
+func TestPOCSAG_MaxMessageLen(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxMessageLen *int
+		numeric       bool
+		message       string
+		expectError   bool
+		errorMsg      string
+	}{
+		{
+			name:    "default limit",
+			message: strings.Repeat("a", defaultPOCSAGMaxMessageLen),
+		},
+		{
+			name:        "over default limit",
+			message:     strings.Repeat("a", defaultPOCSAGMaxMessageLen+1),
+			expectError: true,
+			errorMsg:    "message[1].message is 81 characters, max is 80",
+		},
+		{
+			name:          "custom limit counts characters",
+			maxMessageLen: intPtr(5),
+			message:       "héllo",
+		},
+		{
+			name:          "over custom limit",
+			maxMessageLen: intPtr(5),
+			message:       "hello!",
+			expectError:   true,
+			errorMsg:      "is 6 characters, max is 5",
+		},
+		{
+			name:          "unlimited",
+			maxMessageLen: intPtr(0),
+			message:       strings.Repeat("a", 500),
+		},
+		{
+			name:    "numeric pages have no default limit",
+			numeric: true,
+			message: strings.Repeat("1", 200),
+		},
+		{
+			name:          "explicit limit covers numeric pages",
+			maxMessageLen: intPtr(5),
+			numeric:       true,
+			message:       "123456",
+			expectError:   true,
+			errorMsg:      "is 6 characters, max is 5",
+		},
+		{
+			name:          "negative limit",
+			maxMessageLen: intPtr(-1),
+			message:       "hello",
+			expectError:   true,
+			errorMsg:      "maxMessageLen must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pocsag := &POCSAG{
+				Frequency:     466230000.0,
+				MaxMessageLen: tt.maxMessageLen,
+				NumericMode:   boolPtr(tt.numeric),
+				Messages: []POCSAGMessage{
+					{Address: 100, Message: "12"},
+					{Address: 200, Message: tt.message},
+				},
+			}
+
+			err := pocsag.validate()
+			if tt.expectError {
+				require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestPOCSAG_ValidateMessages(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -886,6 +1140,76 @@ func TestPOCSAG_ValidateMessages(t *testing.T) {
 	}
 }
 
+func TestPOCSAG_ValidateMessages_NumericMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		numericMode *bool
+		message     string
+		expectError bool
+	}{
+		{
+			name:        "numeric mode with digits",
+			numericMode: boolPtr(true),
+			message:     "0123456789",
+			expectError: false,
+		},
+		{
+			name:        "numeric mode with full charset",
+			numericMode: boolPtr(true),
+			message:     "911 U-[42]",
+			expectError: false,
+		},
+		{
+			name:        "numeric mode with letters",
+			numericMode: boolPtr(true),
+			message:     "CALL 911",
+			expectError: true,
+		},
+		{
+			name:        "numeric mode with punctuation",
+			numericMode: boolPtr(true),
+			message:     "555.1234",
+			expectError: true,
+		},
+		{
+			name:        "numeric mode disabled accepts letters",
+			numericMode: boolPtr(false),
+			message:     "CALL 911",
+			expectError: false,
+		},
+		{
+			name:        "numeric mode unset accepts letters",
+			numericMode: nil,
+			message:     "CALL 911",
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pocsag := &POCSAG{
+				NumericMode: tt.numericMode,
+				Messages: []POCSAGMessage{
+					{Address: 100, Message: "123"},
+					{Address: 200, Message: tt.message},
+				},
+			}
+
+			err := pocsag.validateMessages()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+				assert.Contains(t, err.Error(), "message[1]")
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestPOCSAG_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -918,7 +1242,7 @@ func TestPOCSAG_Validate(t *testing.T) {
 				Messages: []POCSAGMessage{
 					{
 						Address: 123456,
-						Message: "Alert message",
+						Message: "911-0042",
 					},
 				},
 			},
@@ -1003,3 +1327,228 @@ func TestPOCSAG_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPOCSAG_ParseArgs_MessagesFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		fileContent   string
+		extraInput    map[string]any
+		expectError   bool
+		errorType     error
+		expectedStdin string
+	}{
+		{
+			name:          "valid messages file",
+			fileContent:   "123:Hello POCSAG\n456:Second message\n",
+			expectError:   false,
+			expectedStdin: "123:Hello POCSAG\n456:Second message",
+		},
+		{
+			name:          "blank lines and CRLF are ignored",
+			fileContent:   "123:First\r\n\r\n\n456:Second\r\n",
+			expectError:   false,
+			expectedStdin: "123:First\n456:Second",
+		},
+		{
+			name:          "message containing colons",
+			fileContent:   "789:Meet at 12:30",
+			expectError:   false,
+			expectedStdin: "789:Meet at 12:30",
+		},
+		{
+			name:          "long messages have no default limit",
+			fileContent:   "123:" + strings.Repeat("a", 200) + "\n",
+			expectError:   false,
+			expectedStdin: "123:" + strings.Repeat("a", 200),
+		},
+		{
+			name:        "explicit limit covers the file",
+			fileContent: "123:" + strings.Repeat("a", 200) + "\n",
+			extraInput:  map[string]any{"maxMessageLen": 80},
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "missing colon",
+			fileContent: "123:First\n456 Second\n",
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "non-numeric address",
+			fileContent: "abc:Message\n",
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "empty file",
+			fileContent: "\n\n",
+			expectError: true,
+			errorType:   commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name:        "messages file with inline messages",
+			fileContent: "123:Hello\n",
+			extraInput: map[string]any{
+				"messages": []map[string]any{
+					{"address": 1, "message": "Inline"},
+				},
+			},
+			expectError: true,
+			errorType:   commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "messages.txt")
+			err := os.WriteFile(path, []byte(tt.fileContent), 0o600)
+			require.NoError(t, err)
+
+			input := map[string]any{
+				"frequency":    466230000.0,
+				"messagesFile": path,
+			}
+			maps.Copy(input, tt.extraInput)
+
+			inputBytes, err := json.Marshal(input)
+			require.NoError(t, err)
+
+			pocsag := &POCSAG{}
+			_, stdin, err := pocsag.ParseArgs(inputBytes)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.errorType)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			stdinContent, err := io.ReadAll(stdin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStdin, string(stdinContent))
+		})
+	}
+}
+
+func TestPOCSAG_PerMessageNumeric(t *testing.T) {
+	tests := []struct {
+		name         string
+		numericMode  *bool
+		messages     []POCSAGMessage
+		expectError  bool
+		expectedArgs []string
+	}{
+		{
+			name: "numeric override on every message",
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "911-0042", Numeric: boolPtr(true)},
+				{Address: 200, Message: "555 1234", Numeric: boolPtr(true)},
+			},
+			expectError:  false,
+			expectedArgs: []string{"-f", "466230000", "-n"},
+		},
+		{
+			name:        "text override on every message in global numeric mode",
+			numericMode: boolPtr(true),
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "Call", Numeric: boolPtr(false)},
+				{Address: 200, Message: "Dispatch", Numeric: boolPtr(false)},
+			},
+			expectError:  false,
+			expectedArgs: []string{"-f", "466230000"},
+		},
+		{
+			name:        "override matching global numeric mode",
+			numericMode: boolPtr(true),
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "555 1234"},
+				{Address: 200, Message: "911", Numeric: boolPtr(true)},
+			},
+			expectError:  false,
+			expectedArgs: []string{"-f", "466230000", "-n"},
+		},
+		{
+			name: "mixed numeric and text pages",
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "911-0042", Numeric: boolPtr(true)},
+				{Address: 200, Message: "Call dispatch"},
+			},
+			expectError: true,
+		},
+		{
+			name:        "text override in global numeric mode",
+			numericMode: boolPtr(true),
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "555 1234"},
+				{Address: 200, Message: "Call dispatch", Numeric: boolPtr(false)},
+			},
+			expectError: true,
+		},
+		{
+			name: "numeric override with letters",
+			messages: []POCSAGMessage{
+				{Address: 100, Message: "Call 911", Numeric: boolPtr(true)},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pocsag := &POCSAG{
+				Frequency:   466230000,
+				NumericMode: tt.numericMode,
+				Messages:    tt.messages,
+			}
+
+			err := pocsag.validateMessages()
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedArgs, pocsag.buildArgs())
+
+			// The type only travels as the -n flag, stdin stays address:message
+			stdinContent, err := io.ReadAll(pocsag.buildStdin())
+			require.NoError(t, err)
+			assert.NotContains(t, string(stdinContent), "/")
+		})
+	}
+}
+
+func TestPOCSAG_ParseArgs_MessagesFileNotFound(t *testing.T) {
+	pocsag := &POCSAG{}
+	input := []byte(`{
+		"frequency": 466230000,
+		"messagesFile": "/tmp/nonexistent_pocsag_messages.txt"
+	}`)
+
+	_, _, err := pocsag.ParseArgs(input)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, commonerrors.ErrFileNotFound)
+}
+
+func TestRPITX_ValidateArgs_POCSAGMessagesFileTwice(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.txt")
+	err := os.WriteFile(path, []byte("123:From file\n"), 0o600)
+	require.NoError(t, err)
+
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{ModuleNamePOCSAG: &POCSAG{}},
+	}
+	input := []byte(`{"frequency": 466230000, "messagesFile": "` + path + `"}`)
+
+	// Each parse gets its own instance, so the messages read from the file
+	// don't trip the messages/messagesFile exclusivity check next time
+	for range 2 {
+		require.NoError(t, rpitx.ValidateArgs(ModuleNamePOCSAG, input))
+	}
+}
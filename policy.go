@@ -0,0 +1,128 @@
+package gorpitx
+
+import (
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// FrequencyRange is a frequency range in Hz, bounds inclusive.
+type FrequencyRange struct {
+	MinHz float64 `json:"minHz"`
+	MaxHz float64 `json:"maxHz"`
+}
+
+// Contains reports whether freqHz lies inside the range.
+func (fr FrequencyRange) Contains(freqHz float64) bool {
+	return freqHz >= fr.MinHz && freqHz <= fr.MaxHz
+}
+
+// FrequencyPolicy restricts the frequencies an RPITX may transmit on, on top
+// of the hardware limits, e.g. to lock a device to the bands permitted where
+// it is sold.
+type FrequencyPolicy struct {
+	// MinHz is the lowest allowed frequency. Zero keeps the hardware minimum.
+	MinHz float64 `json:"minHz,omitempty"`
+
+	// MaxHz is the highest allowed frequency. Zero keeps the hardware maximum.
+	MaxHz float64 `json:"maxHz,omitempty"`
+
+	// Blocked lists ranges that are never allowed, even inside MinHz-MaxHz.
+	Blocked []FrequencyRange `json:"blocked,omitempty"`
+}
+
+// AviationRanges returns the aeronautical radionavigation and VHF airband
+// ranges (108-137 MHz), ready to use in FrequencyPolicy.Blocked.
+func AviationRanges() []FrequencyRange {
+	return []FrequencyRange{
+		{MinHz: 108000000, MaxHz: 137000000},
+	}
+}
+
+// EmergencyRanges returns the distress and emergency beacon frequencies
+// (121.5 MHz, 243 MHz and the 406 MHz COSPAS-SARSAT band), ready to use in
+// FrequencyPolicy.Blocked.
+func EmergencyRanges() []FrequencyRange {
+	return []FrequencyRange{
+		{MinHz: 121450000, MaxHz: 121550000},
+		{MinHz: 242950000, MaxHz: 243050000},
+		{MinHz: 406000000, MaxHz: 406100000},
+	}
+}
+
+// validate checks that the policy ranges are well formed.
+func (p FrequencyPolicy) validate() error {
+	if p.MinHz < 0 || p.MaxHz < 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency policy limits must not be negative, got: %f-%f Hz",
+			p.MinHz, p.MaxHz,
+		)
+	}
+
+	if p.MaxHz > 0 && p.MinHz > p.MaxHz {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency policy minimum %f Hz is above maximum %f Hz",
+			p.MinHz, p.MaxHz,
+		)
+	}
+
+	for _, blocked := range p.Blocked {
+		if blocked.MinHz > blocked.MaxHz {
+			return ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"blocked range minimum %f Hz is above maximum %f Hz",
+				blocked.MinHz, blocked.MaxHz,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Check returns ErrFreqNotAllowed if the policy forbids freqHz.
+func (p FrequencyPolicy) Check(freqHz float64) error {
+	if freqHz < p.MinHz || (p.MaxHz > 0 && freqHz > p.MaxHz) {
+		return ctxerrors.Wrapf(
+			ErrFreqNotAllowed,
+			"%.0f Hz is outside the allowed range %.0f-%.0f Hz",
+			freqHz, p.MinHz, p.maxHz(),
+		)
+	}
+
+	for _, blocked := range p.Blocked {
+		if blocked.Contains(freqHz) {
+			return ctxerrors.Wrapf(
+				ErrFreqNotAllowed,
+				"%.0f Hz is inside the blocked range %.0f-%.0f Hz",
+				freqHz, blocked.MinHz, blocked.MaxHz,
+			)
+		}
+	}
+
+	return nil
+}
+
+// maxHz returns the effective maximum frequency of the policy.
+func (p FrequencyPolicy) maxHz() float64 {
+	if p.MaxHz == 0 {
+		return MaxFrequencyHz()
+	}
+
+	return p.MaxHz
+}
+
+// checkFrequencyPolicy rejects the execution if the module frequency is not
+// allowed by the configured frequency policy.
+func (r *RPITX) checkFrequencyPolicy(module Module) error {
+	if r.frequencyPolicy == nil {
+		return nil
+	}
+
+	reporter, ok := module.(frequencyReporter)
+	if !ok {
+		return nil
+	}
+
+	return r.frequencyPolicy.Check(reporter.frequencyHz())
+}
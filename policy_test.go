@@ -0,0 +1,108 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrequencyPolicy_Check(t *testing.T) {
+	euPolicy := FrequencyPolicy{
+		MinHz:   100000000,
+		MaxHz:   500000000,
+		Blocked: append(AviationRanges(), EmergencyRanges()...),
+	}
+
+	tests := []struct {
+		name        string
+		policy      FrequencyPolicy
+		freqHz      float64
+		expectedErr error
+	}{
+		{"empty policy allows anything", FrequencyPolicy{}, 14074000, nil},
+		{"inside limits", euPolicy, 433920000, nil},
+		{"lower edge", euPolicy, 100000000, nil},
+		{"upper edge", euPolicy, 500000000, nil},
+		{"below minimum", euPolicy, 14074000, ErrFreqNotAllowed},
+		{"above maximum", euPolicy, 868000000, ErrFreqNotAllowed},
+		{"aviation blocked", euPolicy, 118000000, ErrFreqNotAllowed},
+		{"emergency beacon blocked", euPolicy, 406050000, ErrFreqNotAllowed},
+		{
+			"only maximum set",
+			FrequencyPolicy{MaxHz: 30000000},
+			50000000,
+			ErrFreqNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.freqHz)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFrequencyPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    FrequencyPolicy
+		expectErr bool
+	}{
+		{"empty", FrequencyPolicy{}, false},
+		{"valid", FrequencyPolicy{MinHz: 1, MaxHz: 2}, false},
+		{"negative minimum", FrequencyPolicy{MinHz: -1}, true},
+		{"minimum above maximum", FrequencyPolicy{MinHz: 3, MaxHz: 2}, true},
+		{
+			"inverted blocked range",
+			FrequencyPolicy{Blocked: []FrequencyRange{{MinHz: 2, MaxHz: 1}}},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.validate()
+			if tt.expectErr {
+				assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRPITX_FrequencyPolicy(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	_, err := New(WithFrequencyPolicy(FrequencyPolicy{MinHz: 2, MaxHz: 1}))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	r, err := New(WithFrequencyPolicy(FrequencyPolicy{
+		Blocked: AviationRanges(),
+	}))
+	require.NoError(t, err)
+
+	blocked, err := json.Marshal(map[string]any{"frequency": 121500000.0})
+	require.NoError(t, err)
+
+	_, _, _, err = r.PreviewCommand(ModuleNameTUNE, blocked)
+	assert.ErrorIs(t, err, ErrFreqNotAllowed)
+
+	err = r.Exec(t.Context(), ModuleNameTUNE, blocked, 0)
+	assert.ErrorIs(t, err, ErrFreqNotAllowed)
+
+	allowed, err := json.Marshal(map[string]any{"frequency": 434000000.0})
+	require.NoError(t, err)
+
+	_, _, _, err = r.PreviewCommand(ModuleNameTUNE, allowed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,85 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/psyb0t/ctxerrors"
+)
+
+// readerInputPreview is the stdin PreviewCommand reports for a reader given
+// by the caller.
+const readerInputPreview = "<reader input>"
+
+// PreviewCommand runs the full parse/validate/build pipeline for the module
+// and returns the command that Exec would run, without starting anything or
+// touching the args of a running execution.
+// In dev mode the mock command is returned. The stdin data the process would
+// receive is returned as a string, empty when the module uses no stdin. A
+// reader given by the caller, like the FSK reader input, is never read, as
+// its data is meant for the transmitter and a live feed never ends, so
+// readerInputPreview is returned for it instead.
+func (r *RPITX) PreviewCommand(
+	name ModuleName,
+	args json.RawMessage,
+) (string, []string, string, error) {
+	module, err := r.newModule(name)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	cmdName, cmdArgs, stdin, err := r.prepareModuleCommand(name, module, args)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	if stdin == nil {
+		return cmdName, cmdArgs, "", nil
+	}
+
+	if input, ok := module.(readerInput); ok && input.readsReader() {
+		return cmdName, cmdArgs, readerInputPreview, nil
+	}
+
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", nil, "", ctxerrors.Wrap(err, "failed to read stdin")
+	}
+
+	return cmdName, cmdArgs, string(data), nil
+}
+
+// ValidateArgs parses and validates the args for the module and discards the
+// result. The errors are the same ones Exec would return for the args. It is
+// safe to call while a module is executing.
+func (r *RPITX) ValidateArgs(name ModuleName, args json.RawMessage) error {
+	module, err := r.newModule(name)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := r.parseModuleArgs(module, args); err != nil {
+		return ctxerrors.Wrap(err, "failed to parse args")
+	}
+
+	return nil
+}
+
+// BatchItem is one transmission of a schedule checked with ValidateBatch.
+type BatchItem struct {
+	Module ModuleName      `json:"module"`
+	Args   json.RawMessage `json:"args"`
+}
+
+// ValidateBatch validates every item like ValidateArgs, so a schedule can be
+// checked as a whole before its first transmission starts. The errors are
+// returned per item, in order, nil for the valid ones.
+func (r *RPITX) ValidateBatch(items []BatchItem) []error {
+	errs := make([]error, len(items))
+
+	for i, item := range items {
+		errs[i] = r.ValidateArgs(item.Module, item.Args)
+	}
+
+	return errs
+}
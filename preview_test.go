@@ -0,0 +1,349 @@
+package gorpitx
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_PreviewCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		envType       string
+		moduleName    ModuleName
+		args          string
+		expectedName  string
+		expectedArgv  []string
+		expectedStdin string
+		expectError   error
+	}{
+		{
+			name:         "production binary",
+			envType:      env.EnvTypeProd,
+			moduleName:   ModuleNameTUNE,
+			args:         `{"frequency": 434000000}`,
+			expectedName: "stdbuf",
+			expectedArgv: []string{
+				"-oL", "/opt/rpitx/tune", "-f", "434000000",
+			},
+		},
+		{
+			name:       "production with stdin",
+			envType:    env.EnvTypeProd,
+			moduleName: ModuleNamePOCSAG,
+			args: `{"frequency": 466230000, ` +
+				`"messages": [{"address": 123, "message": "HELLO"}]}`,
+			expectedName: "stdbuf",
+			expectedArgv: []string{
+				"-oL", "/opt/rpitx/pocsag", "-f", "466230000",
+			},
+			expectedStdin: "123:HELLO",
+		},
+		{
+			name:         "dev mock",
+			envType:      env.EnvTypeDev,
+			moduleName:   ModuleNameTUNE,
+			args:         `{"frequency": 434000000}`,
+			expectedName: "sh",
+		},
+		{
+			name:        "unknown module",
+			envType:     env.EnvTypeProd,
+			moduleName:  "nonexistent",
+			args:        `{}`,
+			expectError: ErrUnknownModule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(env.EnvVarName, tt.envType)
+
+			mockCommander := commander.NewMock()
+			rpitx := &RPITX{
+				config: Config{Path: "/opt/rpitx"},
+				modules: map[ModuleName]Module{
+					ModuleNameTUNE:   &TUNE{},
+					ModuleNamePOCSAG: &POCSAG{},
+				},
+				commander: mockCommander,
+			}
+
+			name, argv, stdin, err := rpitx.PreviewCommand(
+				tt.moduleName, []byte(tt.args),
+			)
+
+			assert.False(t, rpitx.isExecuting.Load())
+			assert.Empty(t, mockCommander.CallOrder())
+
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedStdin, stdin)
+
+			if tt.expectedArgv != nil {
+				assert.Equal(t, tt.expectedArgv, argv)
+
+				return
+			}
+
+			require.Len(t, argv, 2)
+			assert.Equal(t, "-c", argv[0])
+			assert.Contains(t, argv[1], "mocking execution of tune")
+		})
+	}
+}
+
+func TestRPITX_PreviewCommand_ReaderInput(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	rpitx := &RPITX{
+		config:    Config{Path: "/opt/rpitx", ScriptDir: t.TempDir()},
+		modules:   map[ModuleName]Module{ModuleNameFSK: &FSK{}},
+		commander: commander.New(),
+	}
+
+	args := []byte(`{"inputType": "reader", "frequency": 144500000}`)
+
+	_, _, stdin, err := rpitx.PreviewCommand(ModuleNameFSK, args)
+	require.NoError(t, err)
+	assert.Equal(t, readerInputPreview, stdin)
+
+	require.NoError(t, rpitx.ValidateArgs(ModuleNameFSK, args))
+
+	// The data is all there for the transmission
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	stdout, _, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameFSK, args, 500*time.Millisecond,
+		WithExecReader(strings.NewReader("T=21.5C")),
+	)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+	assert.Contains(t, string(stdout), "mocking stdin: T=21.5C")
+}
+
+func TestRPITX_ValidateArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		moduleName  ModuleName
+		args        string
+		expectError error
+	}{
+		{
+			name:       "valid args",
+			moduleName: ModuleNameTUNE,
+			args:       `{"frequency": 434000000}`,
+		},
+		{
+			name:        "missing frequency",
+			moduleName:  ModuleNameMORSE,
+			args:        `{"rate": 20, "message": "TEST"}`,
+			expectError: commonerrors.ErrInvalidValue,
+		},
+		{
+			name:        "frequency out of range",
+			moduleName:  ModuleNameTUNE,
+			args:        `{"frequency": 1}`,
+			expectError: ErrFreqOutOfRange,
+		},
+		{
+			name:        "unknown module",
+			moduleName:  "nonexistent",
+			args:        `{}`,
+			expectError: ErrUnknownModule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCommander := commander.NewMock()
+			rpitx := &RPITX{
+				modules: map[ModuleName]Module{
+					ModuleNameTUNE:  &TUNE{},
+					ModuleNameMORSE: &MORSE{},
+				},
+				commander: mockCommander,
+			}
+
+			err := rpitx.ValidateArgs(tt.moduleName, []byte(tt.args))
+
+			assert.False(t, rpitx.isExecuting.Load())
+			assert.Empty(t, mockCommander.CallOrder())
+
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRPITX_ValidateArgs_KeepsRegisteredModule(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	morse := &MORSE{}
+	rpitx := &RPITX{
+		config:  Config{Path: "/opt/rpitx"},
+		modules: map[ModuleName]Module{ModuleNameMORSE: morse},
+	}
+
+	args := []byte(`{"frequency": 434000000, "rate": 20, "message": "CQ"}`)
+
+	require.NoError(t, rpitx.ValidateArgs(ModuleNameMORSE, args))
+
+	_, _, _, err := rpitx.PreviewCommand(ModuleNameMORSE, args)
+	require.NoError(t, err)
+
+	// The args went to fresh instances, not the module an execution may
+	// be running with
+	assert.Equal(t, &MORSE{}, morse)
+}
+
+func TestRPITX_ValidateBatch(t *testing.T) {
+	mockCommander := commander.NewMock()
+	rpitx := &RPITX{
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:  &TUNE{},
+			ModuleNameMORSE: &MORSE{},
+		},
+		commander: mockCommander,
+	}
+
+	errs := rpitx.ValidateBatch([]BatchItem{
+		{Module: ModuleNameTUNE, Args: []byte(`{"frequency": 434000000}`)},
+		{Module: ModuleNameTUNE, Args: []byte(`{"frequency": 1}`)},
+		{
+			Module: ModuleNameMORSE,
+			Args: []byte(
+				`{"frequency": 434000000, "rate": 20, "message": "TEST"}`,
+			),
+		},
+		{Module: "nonexistent", Args: []byte(`{}`)},
+	})
+
+	require.Len(t, errs, 4)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], ErrFreqOutOfRange)
+	require.NoError(t, errs[2])
+	require.ErrorIs(t, errs[3], ErrUnknownModule)
+
+	assert.False(t, rpitx.isExecuting.Load())
+	assert.Empty(t, mockCommander.CallOrder())
+	assert.Empty(t, rpitx.ValidateBatch(nil))
+}
+
+func TestRPITX_PreviewCommand_FreshModulePerCall(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	iqFile := newIQFile(t)
+
+	tests := []struct {
+		name         string
+		moduleName   ModuleName
+		first        string
+		second       string
+		expectedArgv []string
+	}{
+		{
+			name:         "tune",
+			moduleName:   ModuleNameTUNE,
+			first:        `{"frequency": 434000000, "ppm": 2.5}`,
+			second:       `{"frequency": 434000000}`,
+			expectedArgv: []string{"-f", "434000000"},
+		},
+		{
+			name:       "audiosock",
+			moduleName: ModuleNameAudioSockBroadcast,
+			first: `{"socketPath": "localhost:7355", "sourceType": "tcp", ` +
+				`"frequency": 144500000}`,
+			second: `{"socketPath": "/tmp/audio_socket", ` +
+				`"frequency": 144500000}`,
+			expectedArgv: []string{
+				"144500000", "/tmp/audio_socket", "48000", "FM", "1", "unix",
+				"0",
+			},
+		},
+		{
+			name:       "fsk",
+			moduleName: ModuleNameFSK,
+			first: `{"inputType": "text", "text": "hi", "invert": true, ` +
+				`"frequency": 144500000}`,
+			second:       `{"inputType": "text", "text": "hi", "frequency": 144500000}`,
+			expectedArgv: []string{"50", "144500000"},
+		},
+		{
+			name:       "pichirp",
+			moduleName: ModuleNamePICHIRP,
+			first: `{"frequency": 7000000, "bandwidth": 1000, "time": 1, ` +
+				`"repeat": 4}`,
+			second:       `{"frequency": 7000000, "bandwidth": 1000, "time": 1}`,
+			expectedArgv: []string{"7000000", "1000", "1"},
+		},
+		{
+			name:       "pirtty",
+			moduleName: ModuleNamePIRTTY,
+			first: `{"frequency": 14070000, "message": "ONE", "shift": 850, ` +
+				`"unshiftOnSpace": true}`,
+			second:       `{"frequency": 14070000, "message": "TWO"}`,
+			expectedArgv: []string{"14070000", "170", "TWO"},
+		},
+		{
+			name:       "sendiq",
+			moduleName: ModuleNameSENDIQ,
+			first: `{"filePath": "` + iqFile + `", "frequency": 434000000, ` +
+				`"sampleRate": 96000, "format": "u8"}`,
+			second: `{"filePath": "` + iqFile + `", "frequency": 434000000}`,
+			expectedArgv: []string{
+				"-i", iqFile, "-f", "434000000",
+			},
+		},
+		{
+			name:       "pidtmf",
+			moduleName: ModuleNamePIDTMF,
+			first: `{"frequency": 145500000, "digits": "1", "toneMs": 50, ` +
+				`"gapMs": 0}`,
+			second:       `{"frequency": 145500000, "digits": "1"}`,
+			expectedArgv: []string{"145500000", "1", "100", "100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{
+				config: Config{
+					Path:      "/opt/rpitx",
+					ScriptDir: t.TempDir(),
+				},
+				modules: defaultModules(),
+			}
+
+			_, _, _, err := rpitx.PreviewCommand(tt.moduleName, []byte(tt.first))
+			require.NoError(t, err)
+
+			// Nothing from the first args carries over
+			_, argv, _, err := rpitx.PreviewCommand(
+				tt.moduleName, []byte(tt.second),
+			)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, len(argv), len(tt.expectedArgv))
+			assert.Equal(
+				t, tt.expectedArgv, argv[len(argv)-len(tt.expectedArgv):],
+			)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package gorpitx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// queueRetryInterval is how often a queued job retries to take the
+	// execution slot while something outside the queue is running.
+	queueRetryInterval = 100 * time.Millisecond
+
+	// maxFinishedJobs is how many finished jobs Jobs keeps listing.
+	maxFinishedJobs = 100
+)
+
+// JobState is the state of a job added with Enqueue.
+type JobState string
+
+const (
+	// JobStateQueued is a job waiting for the jobs added before it.
+	JobStateQueued JobState = "queued"
+	// JobStateRunning is the job at the head of the queue. It may still be
+	// waiting for an execution started outside the queue.
+	JobStateRunning JobState = "running"
+	// JobStateDone is a job whose module exited successfully.
+	JobStateDone JobState = "done"
+	// JobStateFailed is a job that couldn't start or didn't exit
+	// successfully, including timeouts.
+	JobStateFailed JobState = "failed"
+	// JobStateCanceled is a job stopped with CancelJob or its context.
+	JobStateCanceled JobState = "canceled"
+)
+
+// Job describes a job added with Enqueue.
+type Job struct {
+	// ID identifies the job for CancelJob.
+	ID string `json:"id"`
+
+	// Module is the name of the module the job executes.
+	Module ModuleName `json:"module"`
+
+	// State is the current state of the job.
+	State JobState `json:"state"`
+
+	// EnqueuedAt is the time the job was added to the queue.
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+
+	// StartedAt is the time the job left the queue. It is zero while the
+	// job is queued.
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is the time the job finished. It is zero until then.
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// Err is the result of a failed job, like the one Exec would have
+	// returned. Jobs canceled before they started get context.Canceled.
+	Err error `json:"-"`
+}
+
+// jobQueue holds the jobs added with Enqueue, in order.
+type jobQueue struct {
+	mu      sync.Mutex
+	jobs    []*queuedJob
+	lastID  uint64
+	running bool
+}
+
+// queuedJob is a job with what's needed to execute it.
+type queuedJob struct {
+	Job
+
+	ctx       context.Context //nolint:containedctx // the job outlives Enqueue
+	cancel    context.CancelFunc
+	args      []byte
+	timeout   time.Duration
+	execution *Execution
+	canceled  bool
+}
+
+// Enqueue adds a module execution to the queue and returns its job ID
+// without waiting for it. Jobs are executed one after the other in the order
+// they were added, and a job waits for any execution started outside the
+// queue instead of failing with ErrExecuting. Canceling ctx cancels the job
+// like CancelJob. Exec keeps failing fast while the queue is busy.
+func (r *RPITX) Enqueue(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	timeout time.Duration,
+) (string, error) {
+	if err := checkTimeout(timeout); err != nil {
+		return "", err
+	}
+
+	if !r.IsSupportedModule(name) {
+		return "", ctxerrors.Wrap(ErrUnknownModule, name)
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	r.queue.mu.Lock()
+	defer r.queue.mu.Unlock()
+
+	r.queue.lastID++
+
+	job := &queuedJob{
+		Job: Job{
+			ID:         "job-" + strconv.FormatUint(r.queue.lastID, 10),
+			Module:     name,
+			State:      JobStateQueued,
+			EnqueuedAt: time.Now(),
+		},
+		ctx:     jobCtx,
+		cancel:  cancel,
+		args:    args,
+		timeout: timeout,
+	}
+
+	r.queue.jobs = append(r.queue.jobs, job)
+
+	// Jobs whose context ends while they're queued never run
+	context.AfterFunc(jobCtx, func() {
+		r.queue.mu.Lock()
+		defer r.queue.mu.Unlock()
+
+		if job.State == JobStateQueued {
+			r.queue.finish(job, context.Canceled)
+		}
+	})
+
+	if !r.queue.running {
+		r.queue.running = true
+
+		go r.runQueue()
+	}
+
+	r.log().Debugf("enqueued job %s for module %s", job.ID, name)
+
+	return job.ID, nil
+}
+
+// CancelJob removes a queued job from the queue or stops it if it's
+// running. Returns ErrJobNotFound for unknown IDs and ErrJobFinished when
+// the job is already over.
+func (r *RPITX) CancelJob(id string) error {
+	r.queue.mu.Lock()
+
+	job := r.queue.find(id)
+	if job == nil {
+		r.queue.mu.Unlock()
+
+		return ctxerrors.Wrap(ErrJobNotFound, id)
+	}
+
+	if !job.FinishedAt.IsZero() {
+		r.queue.mu.Unlock()
+
+		return ctxerrors.Wrap(ErrJobFinished, id)
+	}
+
+	job.canceled = true
+
+	if job.State == JobStateQueued {
+		r.queue.finish(job, context.Canceled)
+		r.queue.mu.Unlock()
+
+		return nil
+	}
+
+	job.cancel()
+
+	execution := job.execution
+	r.queue.mu.Unlock()
+
+	if execution == nil {
+		return nil
+	}
+
+	// The process ending on the stop signal is what we asked for
+	err := execution.Stop(context.Background())
+	if err != nil && !errors.Is(err, ErrNotExecuting) &&
+		!errors.Is(err, commonerrors.ErrTerminated) {
+		return ctxerrors.Wrapf(err, "failed to stop job %s", id)
+	}
+
+	return nil
+}
+
+// Jobs lists the queued, running and recently finished jobs in the order
+// they were added.
+func (r *RPITX) Jobs() []Job {
+	r.queue.mu.Lock()
+	defer r.queue.mu.Unlock()
+
+	jobs := make([]Job, 0, len(r.queue.jobs))
+	for _, job := range r.queue.jobs {
+		jobs = append(jobs, job.Job)
+	}
+
+	return jobs
+}
+
+// runQueue executes the queued jobs one by one until the queue is empty.
+func (r *RPITX) runQueue() {
+	for {
+		job := r.nextJob()
+		if job == nil {
+			return
+		}
+
+		err := r.execJob(job)
+		r.finishJob(job, err)
+	}
+}
+
+// nextJob marks the first queued job as running and returns it. It returns
+// nil and marks the queue as idle when there's nothing left to run.
+func (r *RPITX) nextJob() *queuedJob {
+	r.queue.mu.Lock()
+	defer r.queue.mu.Unlock()
+
+	for _, job := range r.queue.jobs {
+		if job.State != JobStateQueued {
+			continue
+		}
+
+		// The job's context ended before the cancellation was recorded
+		if job.ctx.Err() != nil {
+			r.queue.finish(job, context.Canceled)
+
+			continue
+		}
+
+		job.State = JobStateRunning
+		job.StartedAt = time.Now()
+
+		return job
+	}
+
+	r.queue.running = false
+
+	return nil
+}
+
+// execJob executes the job, waiting for the execution slot if something
+// outside the queue holds it or the transmitter is cooling down.
+func (r *RPITX) execJob(job *queuedJob) error {
+	for {
+		execution, err := r.ExecAsync(job.ctx, job.Module, job.args, job.timeout)
+		if err == nil {
+			r.queue.mu.Lock()
+			job.execution = execution
+			canceled := job.canceled
+			r.queue.mu.Unlock()
+
+			// CancelJob ran before the execution could be stopped
+			if canceled {
+				_ = execution.Stop(context.Background())
+			}
+
+			return execution.Wait()
+		}
+
+		if !errors.Is(err, ErrExecuting) && !errors.Is(err, ErrCoolingDown) {
+			return err
+		}
+
+		select {
+		case <-job.ctx.Done():
+			return context.Canceled
+		case <-time.After(queueRetryInterval):
+		}
+	}
+}
+
+// finishJob records the result of an executed job.
+func (r *RPITX) finishJob(job *queuedJob, err error) {
+	r.queue.mu.Lock()
+	defer r.queue.mu.Unlock()
+
+	r.queue.finish(job, err)
+
+	r.log().Debugf("job %s finished as %s", job.ID, job.State)
+}
+
+// find returns the job with the given ID or nil.
+func (q *jobQueue) find(id string) *queuedJob {
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+
+	return nil
+}
+
+// finish marks the job as finished and drops the oldest finished jobs over
+// maxFinishedJobs. The queue lock must be held.
+func (q *jobQueue) finish(job *queuedJob, err error) {
+	switch {
+	case job.canceled || job.ctx.Err() != nil && err != nil:
+		job.State = JobStateCanceled
+	case err != nil:
+		job.State = JobStateFailed
+	default:
+		job.State = JobStateDone
+	}
+
+	job.cancel()
+	job.execution = nil
+	job.FinishedAt = time.Now()
+	job.Err = err
+
+	finished := 0
+
+	for i := len(q.jobs) - 1; i >= 0; i-- {
+		if q.jobs[i].FinishedAt.IsZero() {
+			continue
+		}
+
+		finished++
+		if finished > maxFinishedJobs {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+		}
+	}
+}
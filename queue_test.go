@@ -0,0 +1,179 @@
+package gorpitx
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForJobState waits until the job with the given ID reaches state and
+// returns it.
+func waitForJobState(
+	t *testing.T,
+	rpitx *RPITX,
+	id string,
+	state JobState,
+) Job {
+	t.Helper()
+
+	var found Job
+
+	require.Eventually(t, func() bool {
+		for _, job := range rpitx.Jobs() {
+			if job.ID == id && job.State == state {
+				found = job
+
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return found
+}
+
+func TestRPITX_Enqueue_RunsJobsInOrder(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	first, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 200*time.Millisecond)
+	require.NoError(t, err)
+
+	second, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	firstJob := waitForJobState(t, rpitx, first, JobStateFailed)
+	secondJob := waitForJobState(t, rpitx, second, JobStateFailed)
+
+	// Timed out jobs fail like Exec does
+	require.ErrorIs(t, firstJob.Err, commonerrors.ErrTimeout)
+	require.ErrorIs(t, secondJob.Err, commonerrors.ErrTimeout)
+	assert.Equal(t, ModuleNameMORSE, firstJob.Module)
+	assert.False(t, secondJob.StartedAt.Before(firstJob.FinishedAt))
+
+	jobs := rpitx.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, first, jobs[0].ID)
+	assert.Equal(t, second, jobs[1].ID)
+}
+
+func TestRPITX_Enqueue_WaitsForExec(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	id, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Exec keeps failing fast while the queue is waiting
+	require.ErrorIs(
+		t, rpitx.Exec(ctx, ModuleNameMORSE, args, 0), ErrExecuting,
+	)
+
+	// The job keeps retrying instead of failing with ErrExecuting
+	time.Sleep(3 * queueRetryInterval)
+	waitForJobState(t, rpitx, id, JobStateRunning)
+
+	_ = execution.Stop(ctx)
+	_ = execution.Wait()
+
+	job := waitForJobState(t, rpitx, id, JobStateFailed)
+	require.ErrorIs(t, job.Err, commonerrors.ErrTimeout)
+}
+
+func TestRPITX_CancelJob(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	running, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	queued, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	waitForJobState(t, rpitx, running, JobStateRunning)
+
+	// A queued job is dropped right away
+	require.NoError(t, rpitx.CancelJob(queued))
+
+	job := waitForJobState(t, rpitx, queued, JobStateCanceled)
+	require.ErrorIs(t, job.Err, context.Canceled)
+	assert.True(t, job.StartedAt.IsZero())
+
+	// A running job is stopped
+	require.NoError(t, rpitx.CancelJob(running))
+	waitForJobState(t, rpitx, running, JobStateCanceled)
+
+	require.ErrorIs(t, rpitx.CancelJob(running), ErrJobFinished)
+	require.ErrorIs(t, rpitx.CancelJob("job-404"), ErrJobNotFound)
+
+	require.Eventually(t, func() bool {
+		return !rpitx.isExecuting.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRPITX_Enqueue_ContextCanceled(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	running, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	queued, err := rpitx.Enqueue(jobCtx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	cancel()
+	waitForJobState(t, rpitx, queued, JobStateCanceled)
+
+	require.NoError(t, rpitx.CancelJob(running))
+	waitForJobState(t, rpitx, running, JobStateCanceled)
+}
+
+func TestRPITX_Enqueue_InvalidJob(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	_, err := rpitx.Enqueue(ctx, "nope", args, 0)
+	require.ErrorIs(t, err, ErrUnknownModule)
+
+	_, err = rpitx.Enqueue(ctx, ModuleNameMORSE, args, -time.Second)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	// Invalid args only fail once the job runs
+	id, err := rpitx.Enqueue(
+		ctx, ModuleNameMORSE, []byte(`{"frequency": 1}`), 0,
+	)
+	require.NoError(t, err)
+
+	job := waitForJobState(t, rpitx, id, JobStateFailed)
+	require.ErrorIs(t, job.Err, ErrFreqOutOfRange)
+}
+
+func TestJobQueue_Finish_TrimsFinishedJobs(t *testing.T) {
+	q := &jobQueue{}
+
+	for i := range maxFinishedJobs + 5 {
+		job := &queuedJob{
+			Job:    Job{ID: strconv.Itoa(i), State: JobStateRunning},
+			ctx:    context.Background(),
+			cancel: func() {},
+		}
+		q.jobs = append(q.jobs, job)
+		q.finish(job, nil)
+	}
+
+	require.Len(t, q.jobs, maxFinishedJobs)
+	assert.Equal(t, "5", q.jobs[0].ID)
+	assert.Equal(t, JobStateDone, q.jobs[0].State)
+}
@@ -0,0 +1,161 @@
+package gorpitx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// runExecution waits for the execution process to finish. With auto restart
+// enabled, a process that exits unexpectedly is relaunched with the same
+// args until the retries run out. A timeout applies to the execution as a
+// whole, restarts included.
+func (r *RPITX) runExecution(
+	ctx context.Context,
+	execution *Execution,
+	timeout time.Duration,
+) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for restarts := 0; ; restarts++ {
+		process, stderr := execution.current()
+
+		err := r.waitProcess(ctx, process, remaining(deadline))
+		err = newExecError(execution.module, err, stderr.wait())
+
+		if !r.shouldRestart(ctx, err, restarts) {
+			return err
+		}
+
+		r.log().Warnf(
+			"module %s exited unexpectedly, restarting in %s (%d/%d): %v",
+			execution.module, r.autoRestartBackoff,
+			restarts+1, r.autoRestartRetries, err,
+		)
+
+		if restartErr := r.restart(ctx, execution, deadline); restartErr != nil {
+			if errors.Is(restartErr, errStopRequested) {
+				return err
+			}
+
+			return restartErr
+		}
+	}
+}
+
+// errStopRequested is returned by restart when the execution was stopped
+// while waiting to relaunch the process.
+var errStopRequested = errors.New( //nolint:gochecknoglobals
+	"stop requested",
+)
+
+// shouldRestart tells whether the process that ended with err must be
+// relaunched. Successful exits, timeouts, stops and cancelled contexts are
+// never restarted.
+func (r *RPITX) shouldRestart(
+	ctx context.Context,
+	err error,
+	restarts int,
+) bool {
+	return err != nil &&
+		restarts < r.autoRestartRetries &&
+		!errors.Is(err, commonerrors.ErrTimeout) &&
+		!r.stopRequested() &&
+		ctx.Err() == nil
+}
+
+// restart waits for the backoff and relaunches the execution process.
+func (r *RPITX) restart(
+	ctx context.Context,
+	execution *Execution,
+	deadline time.Time,
+) error {
+	timer := time.NewTimer(r.autoRestartBackoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.stopChan():
+		return errStopRequested
+	case <-ctx.Done():
+		return ctxerrors.Wrap(ctx.Err(), "cancelled while waiting to restart")
+	}
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return commonerrors.ErrTimeout
+	}
+
+	process, _, err := r.launch(
+		ctx, execution.module, execution.args, execution.options,
+	)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to restart process")
+	}
+
+	execution.setProcess(process, r.captureStderr(process))
+
+	// Stop was requested while the process was being relaunched
+	if r.stopRequested() {
+		if err := process.Kill(ctx); err != nil {
+			r.log().Warnf("failed to kill restarted process: %v", err)
+		}
+
+		return errStopRequested
+	}
+
+	return nil
+}
+
+// remaining returns the time left until the deadline, or zero for no
+// deadline. A deadline that has passed leaves a minimal timeout so the
+// process is stopped right away.
+func remaining(deadline time.Time) time.Duration {
+	if deadline.IsZero() {
+		return 0
+	}
+
+	return max(time.Until(deadline), time.Nanosecond)
+}
+
+// requestStop marks the current execution as stopped on purpose so it
+// isn't restarted.
+func (r *RPITX) requestStop() {
+	r.processMu.Lock()
+	defer r.processMu.Unlock()
+
+	if r.stopCh == nil {
+		return
+	}
+
+	select {
+	case <-r.stopCh:
+	default:
+		close(r.stopCh)
+	}
+}
+
+// stopChan returns the channel closed when a stop of the current execution
+// is requested.
+func (r *RPITX) stopChan() <-chan struct{} {
+	r.processMu.RLock()
+	defer r.processMu.RUnlock()
+
+	return r.stopCh
+}
+
+// stopRequested tells whether a stop of the current execution was
+// requested.
+func (r *RPITX) stopRequested() bool {
+	select {
+	case <-r.stopChan():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,204 @@
+package gorpitx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRestartTestRPITX returns an RPITX running a fake "crashy" binary that
+// records each run and exits with code 1 until it has run succeedAfter
+// times, or runs until stopped when succeedAfter is negative.
+func newRestartTestRPITX(
+	t *testing.T,
+	succeedAfter int,
+	retries int,
+	backoff time.Duration,
+) (*RPITX, func() int) {
+	t.Helper()
+	t.Setenv(env.EnvVarName, env.EnvTypeProd)
+
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+
+	script := fmt.Sprintf("#!/bin/sh\necho run >> %s\n", runs)
+	if succeedAfter < 0 {
+		script += "while true; do sleep 0.05; done\n"
+	} else {
+		script += fmt.Sprintf(
+			"[ $(wc -l < %s) -ge %d ] && exit 0\nexit 1\n",
+			runs, succeedAfter,
+		)
+	}
+
+	//nolint:gosec // the fake binary must be executable
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "crashy"), []byte(script), 0o755,
+	))
+
+	rpitx := &RPITX{
+		config:             Config{Path: dir},
+		commander:          commander.New(),
+		autoRestartRetries: retries,
+		autoRestartBackoff: backoff,
+		modules: map[ModuleName]Module{
+			"crashy": &TUNE{},
+		},
+	}
+
+	countRuns := func() int {
+		data, err := os.ReadFile(runs) //nolint:gosec // test file
+		if err != nil {
+			return 0
+		}
+
+		return strings.Count(string(data), "\n")
+	}
+
+	return rpitx, countRuns
+}
+
+var restartTestArgs = []byte( //nolint:gochecknoglobals
+	`{"frequency": 434000000}`,
+)
+
+func TestRPITX_AutoRestart(t *testing.T) {
+	tests := []struct {
+		name         string
+		succeedAfter int
+		retries      int
+		expectErr    error
+		expectRuns   int
+	}{
+		{
+			name:         "disabled",
+			succeedAfter: 3,
+			retries:      0,
+			expectErr:    commonerrors.ErrFailed,
+			expectRuns:   1,
+		},
+		{
+			name:         "recovers",
+			succeedAfter: 3,
+			retries:      5,
+			expectRuns:   3,
+		},
+		{
+			name:         "gives up after max retries",
+			succeedAfter: 10,
+			retries:      2,
+			expectErr:    commonerrors.ErrFailed,
+			expectRuns:   3,
+		},
+		{
+			name:         "clean exit is not restarted",
+			succeedAfter: 1,
+			retries:      5,
+			expectRuns:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx, runs := newRestartTestRPITX(
+				t, tt.succeedAfter, tt.retries, 10*time.Millisecond,
+			)
+
+			err := rpitx.Exec(
+				context.Background(), "crashy", restartTestArgs, 0,
+			)
+			if tt.expectErr != nil {
+				var execErr *ExecError
+				require.ErrorAs(t, err, &execErr)
+				assert.Equal(t, 1, execErr.ExitCode)
+				assert.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectRuns, runs())
+			assert.False(t, rpitx.isExecuting.Load())
+		})
+	}
+}
+
+func TestRPITX_AutoRestart_Timeout(t *testing.T) {
+	rpitx, runs := newRestartTestRPITX(t, 1000, 1000, 50*time.Millisecond)
+
+	start := time.Now()
+	err := rpitx.Exec(
+		context.Background(), "crashy", restartTestArgs, 300*time.Millisecond,
+	)
+
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Greater(t, runs(), 1)
+}
+
+func TestRPITX_AutoRestart_StopNotRestarted(t *testing.T) {
+	rpitx, runs := newRestartTestRPITX(t, -1, 5, 10*time.Millisecond)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, "crashy", restartTestArgs, 0)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return runs() == 1 },
+		2*time.Second, 10*time.Millisecond)
+
+	_ = rpitx.Stop(ctx)
+
+	err = execution.Wait()
+	assert.ErrorIs(t, err, commonerrors.ErrTerminated)
+	assert.Equal(t, 1, runs())
+}
+
+func TestRPITX_AutoRestart_StopDuringBackoff(t *testing.T) {
+	rpitx, runs := newRestartTestRPITX(t, 10, 5, time.Minute)
+	ctx := context.Background()
+
+	execution, err := rpitx.ExecAsync(ctx, "crashy", restartTestArgs, 0)
+	require.NoError(t, err)
+
+	// Give the first run time to crash so the execution is backing off
+	require.Eventually(t, func() bool { return runs() == 1 },
+		2*time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+
+	_ = rpitx.Stop(ctx)
+
+	select {
+	case <-execution.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution did not finish after stop")
+	}
+
+	var execErr *ExecError
+	require.ErrorAs(t, execution.Wait(), &execErr)
+	assert.Equal(t, 1, execErr.ExitCode)
+	assert.Equal(t, 1, runs())
+}
+
+func TestWithAutoRestart(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New(WithAutoRestart(3, time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 3, r.autoRestartRetries)
+	assert.Equal(t, time.Second, r.autoRestartBackoff)
+
+	_, err = New(WithAutoRestart(-1, time.Second))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	_, err = New(WithAutoRestart(1, -time.Second))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
@@ -0,0 +1,264 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+	// schemaTagName is the struct tag holding extra schema constraints as
+	// semicolon separated entries, e.g. `schema:"freq=hz;required"`.
+	schemaTagName = "schema"
+
+	schemaFreqHz  = "hz"  // frequency constraint in Hz
+	schemaFreqMHz = "mhz" // frequency constraint in MHz
+)
+
+// ModuleSchema returns a JSON Schema describing the args accepted by the
+// module. It is generated from the module struct: the json tags give the
+// property names and required fields, the Go types give the property types
+// and the schema tags add constraints like ranges, lengths and enums.
+func (r *RPITX) ModuleSchema(name ModuleName) ([]byte, error) {
+	module, ok := r.module(name)
+	if !ok {
+		return nil, ctxerrors.Wrap(ErrUnknownModule, name)
+	}
+
+	schema, err := typeSchema(reflect.TypeOf(module))
+	if err != nil {
+		return nil, ctxerrors.Wrapf(err, "failed to build schema for %s", name)
+	}
+
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = name
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, ctxerrors.Wrap(err, "failed to marshal schema")
+	}
+
+	return data, nil
+}
+
+// typeSchema returns the schema for a Go type.
+func typeSchema(t reflect.Type) (map[string]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Durations are decoded from Go duration strings such as "90s"
+	if t == reflect.TypeFor[time.Duration]() {
+		return map[string]any{"type": "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]any{}, nil
+	}
+}
+
+// structSchema returns the object schema for a struct type.
+func structSchema(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := typeSchema(field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		isRequired := field.Type.Kind() != reflect.Pointer &&
+			!strings.Contains(opts, "omitempty")
+
+		isRequired, err = applySchemaTag(
+			prop,
+			field.Tag.Get(schemaTagName),
+			isRequired,
+		)
+		if err != nil {
+			return nil, ctxerrors.Wrapf(err, "field %s", name)
+		}
+
+		properties[name] = prop
+
+		if isRequired {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// applySchemaTag adds the constraints from a schema tag to the property and
+// returns whether the property is required.
+func applySchemaTag(
+	prop map[string]any,
+	tag string,
+	isRequired bool,
+) (bool, error) {
+	if tag == "" {
+		return isRequired, nil
+	}
+
+	for entry := range strings.SplitSeq(tag, ";") {
+		key, value, _ := strings.Cut(entry, "=")
+
+		switch key {
+		case "required":
+			isRequired = true
+		case "optional":
+			isRequired = false
+		case "freq":
+			if err := applyFreqConstraint(prop, value); err != nil {
+				return false, err
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, ctxerrors.Wrapf(
+					commonerrors.ErrInvalidValue,
+					"invalid %s %q",
+					key, value,
+				)
+			}
+
+			prop[key] = number
+		case "minLength", "maxLength", "minItems", "maxItems":
+			length, err := strconv.Atoi(value)
+			if err != nil {
+				return false, ctxerrors.Wrapf(
+					commonerrors.ErrInvalidValue,
+					"invalid %s %q",
+					key, value,
+				)
+			}
+
+			prop[key] = length
+		case "pattern":
+			prop[key] = value
+		case "enum":
+			enum, err := parseSchemaEnum(prop["type"], value)
+			if err != nil {
+				return false, err
+			}
+
+			prop[key] = enum
+		default:
+			return false, ctxerrors.Wrapf(
+				commonerrors.ErrInvalidValue,
+				"unknown schema tag entry %q",
+				entry,
+			)
+		}
+	}
+
+	return isRequired, nil
+}
+
+// applyFreqConstraint sets the RPiTX frequency range in the given unit.
+func applyFreqConstraint(prop map[string]any, unit string) error {
+	switch unit {
+	case schemaFreqHz:
+		prop["minimum"] = MinFrequencyHz()
+		prop["maximum"] = MaxFrequencyHz()
+	case schemaFreqMHz:
+		prop["minimum"] = hzToMHz(MinFrequencyHz())
+		prop["maximum"] = hzToMHz(MaxFrequencyHz())
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"unknown frequency unit %q",
+			unit,
+		)
+	}
+
+	return nil
+}
+
+// parseSchemaEnum parses pipe separated enum values for the property type.
+func parseSchemaEnum(propType any, value string) ([]any, error) {
+	values := strings.Split(value, "|")
+	enum := make([]any, 0, len(values))
+
+	for _, v := range values {
+		switch propType {
+		case "integer":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, ctxerrors.Wrapf(
+					commonerrors.ErrInvalidValue,
+					"invalid integer enum value %q",
+					v,
+				)
+			}
+
+			enum = append(enum, n)
+		case "number":
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, ctxerrors.Wrapf(
+					commonerrors.ErrInvalidValue,
+					"invalid number enum value %q",
+					v,
+				)
+			}
+
+			enum = append(enum, n)
+		default:
+			enum = append(enum, v)
+		}
+	}
+
+	return enum, nil
+}
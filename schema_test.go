@@ -0,0 +1,182 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSchema struct {
+	Schema     string                    `json:"$schema"`
+	Title      string                    `json:"title"`
+	Type       string                    `json:"type"`
+	Properties map[string]map[string]any `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+func moduleSchema(t *testing.T, name ModuleName) testSchema {
+	t.Helper()
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	instance = nil
+	once = sync.Once{}
+
+	data, err := GetInstance().ModuleSchema(name)
+	require.NoError(t, err)
+
+	var schema testSchema
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	return schema
+}
+
+// enumStrings returns the enum of a property as strings.
+func enumStrings(prop map[string]any) []string {
+	values, _ := prop["enum"].([]any)
+	enum := make([]string, 0, len(values))
+
+	for _, v := range values {
+		s, _ := v.(string)
+		enum = append(enum, s)
+	}
+
+	return enum
+}
+
+func TestRPITX_ModuleSchema_AllModules(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	instance = nil
+	once = sync.Once{}
+
+	rpitx := GetInstance()
+
+	for _, name := range rpitx.GetSupportedModules() {
+		t.Run(name, func(t *testing.T) {
+			schema := moduleSchema(t, name)
+
+			assert.Equal(t, jsonSchemaDraft, schema.Schema)
+			assert.Equal(t, name, schema.Title)
+			assert.Equal(t, "object", schema.Type)
+
+			// Every json field of the module struct must be described
+			module, _ := rpitx.module(name)
+			moduleType := reflect.TypeOf(module).Elem()
+
+			for i := range moduleType.NumField() {
+				if !moduleType.Field(i).IsExported() {
+					continue
+				}
+
+				tag := moduleType.Field(i).Tag.Get("json")
+				fieldName, _, _ := strings.Cut(tag, ",")
+				assert.Contains(t, schema.Properties, fieldName)
+			}
+
+			for _, req := range schema.Required {
+				assert.Contains(t, schema.Properties, req)
+			}
+		})
+	}
+}
+
+func TestRPITX_ModuleSchema_Constraints(t *testing.T) {
+	pifmrds := moduleSchema(t, ModuleNamePIFMRDS)
+	assert.ElementsMatch(t, []string{"freq", "audio"}, pifmrds.Required)
+	assert.Equal(t, "number", pifmrds.Properties["freq"]["type"])
+	assert.InDelta(t, hzToMHz(MinFrequencyHz()),
+		pifmrds.Properties["freq"]["minimum"], 0)
+	assert.InDelta(t, hzToMHz(MaxFrequencyHz()),
+		pifmrds.Properties["freq"]["maximum"], 0)
+	assert.InDelta(t, psMaxLength, pifmrds.Properties["ps"]["maxLength"], 0)
+	assert.Equal(t, "^[ -~]*$", pifmrds.Properties["ps"]["pattern"])
+	assert.InDelta(t, rtMaxLength, pifmrds.Properties["rt"]["maxLength"], 0)
+
+	updates, _ := pifmrds.Properties["schedule"]["items"].(map[string]any)
+	assert.ElementsMatch(t, []any{"field", "value"}, updates["required"])
+
+	updateProps, _ := updates["properties"].(map[string]any)
+	assert.Equal(
+		t, map[string]any{"type": "string"}, updateProps["atOffset"],
+	)
+
+	tune := moduleSchema(t, ModuleNameTUNE)
+	assert.Equal(t, []string{"frequency"}, tune.Required)
+	assert.InDelta(t, MinFrequencyHz(), tune.Properties["frequency"]["minimum"], 0)
+	assert.InDelta(t, MaxFrequencyHz(), tune.Properties["frequency"]["maximum"], 0)
+	assert.Equal(t, "boolean", tune.Properties["exitImmediate"]["type"])
+
+	ft8 := moduleSchema(t, ModuleNameFT8)
+	// Message is optional since a Messages sequence can replace it
+	assert.Empty(t, ft8.Required)
+	assert.Equal(t, FT8Bands(), enumStrings(ft8.Properties["band"]))
+	assert.InDelta(t, ft8OffsetMax, ft8.Properties["offset"]["maximum"], 0)
+
+	pisstv := moduleSchema(t, ModuleNamePISSSTV)
+	assert.Equal(t, validSSTVModes(), enumStrings(pisstv.Properties["mode"]))
+
+	pirtty := moduleSchema(t, ModuleNamePIRTTY)
+	shifts, _ := pirtty.Properties["shift"]["enum"].([]any)
+	require.Len(t, shifts, len(validPIRTTYShifts))
+
+	for i, shift := range validPIRTTYShifts {
+		assert.InDelta(t, shift, shifts[i], 0)
+	}
+
+	pocsag := moduleSchema(t, ModuleNamePOCSAG)
+	assert.Equal(t, "array", pocsag.Properties["messages"]["type"])
+	assert.NotContains(t, pocsag.Required, "messages")
+
+	items, _ := pocsag.Properties["messages"]["items"].(map[string]any)
+	assert.ElementsMatch(t, []any{"address", "message"}, items["required"])
+
+	bauds, _ := pocsag.Properties["baudRate"]["enum"].([]any)
+	require.NotEmpty(t, bauds)
+
+	for _, baud := range bauds {
+		rate := int(baud.(float64)) //nolint:forcetypeassert
+		m := &POCSAG{BaudRate: &rate}
+		assert.NoError(t, m.validateBaudRate(), "baud %d", rate)
+	}
+
+	audiosock := moduleSchema(t, ModuleNameAudioSockBroadcast)
+	assert.Equal(
+		t, ValidModulations(), enumStrings(audiosock.Properties["modulation"]),
+	)
+}
+
+func TestRPITX_ModuleSchema_UnknownModule(t *testing.T) {
+	rpitx := &RPITX{}
+
+	_, err := rpitx.ModuleSchema("nonexistent")
+	require.ErrorIs(t, err, ErrUnknownModule)
+}
+
+func TestApplySchemaTag_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+	}{
+		{name: "unknown entry", tag: "bogus=1"},
+		{name: "bad number", tag: "minimum=abc"},
+		{name: "bad length", tag: "maxLength=1.5"},
+		{name: "bad frequency unit", tag: "freq=ghz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := applySchemaTag(map[string]any{}, tt.tag, false)
+			require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+		})
+	}
+
+	_, err := parseSchemaEnum("integer", "1|x")
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
@@ -1,18 +1,24 @@
 package gorpitx
 
 import (
+	"crypto/sha256"
 	_ "embed"
 	"os"
 	"path/filepath"
 
 	"github.com/psyb0t/ctxerrors"
-	"github.com/sirupsen/logrus"
 )
 
 const (
-	fskScriptPath          = "/tmp/fsk.sh"
-	audioSockBroadcastPath = "/tmp/audiosock_broadcast.sh"
-	modulationPath         = "/tmp/modulation.sh"
+	defaultScriptDir = "/tmp"
+
+	fskScriptName          = "fsk.sh"
+	audioSockBroadcastName = "audiosock_broadcast.sh"
+	dtmfScriptName         = "dtmf.sh"
+	modulationName         = "modulation.sh"
+	repeatScriptName       = "repeat.sh"
+	sequenceScriptName     = "sequence.sh"
+	ssbScriptName          = "ssb.sh"
 
 	dirPerm    = 0o750
 	scriptPerm = 0o600
@@ -29,161 +35,192 @@ var fskScript string
 //go:embed scripts/audiosock_broadcast.sh
 var audioSockBroadcastScript string
 
+// dtmfScript contains the embedded DTMF script
+//
+//go:embed scripts/dtmf.sh
+var dtmfScript string
+
+// ssbScript contains the embedded SSB script
+//
+//go:embed scripts/ssb.sh
+var ssbScript string
+
 // modulationScript contains the embedded modulation script
 //
 //go:embed scripts/modulation.sh
 var modulationScript string
 
-// init writes all embedded scripts to filesystem on package initialization.
+// repeatScript contains the embedded script that runs a binary several
+// times with a gap in between
 //
-//nolint:gochecknoinits // Required for automatic script deployment
-func init() {
-	writeAllScripts()
-}
+//go:embed scripts/repeat.sh
+var repeatScript string
 
-// writeAllScripts writes all embedded scripts to filesystem unconditionally.
+// sequenceScript contains the embedded script that runs a binary once per
+// value, appending the value to its args
 //
-//nolint:funlen // Function length due to proper parameter formatting
-func writeAllScripts() {
-	var err error
+//go:embed scripts/sequence.sh
+var sequenceScript string
 
-	// Create directories
-	err = os.MkdirAll(
-		filepath.Dir(fskScriptPath),
-		dirPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to create script directory: %v", err)
-	}
+// helperScripts maps the helper scripts binary modules can be run through to
+// their content.
+//
+//nolint:gochecknoglobals
+var helperScripts = map[string]string{
+	repeatScriptName:   repeatScript,
+	sequenceScriptName: sequenceScript,
+}
 
-	err = os.MkdirAll(
-		filepath.Dir(audioSockBroadcastPath),
-		dirPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to create script directory: %v", err)
+// ModuleNameToScriptName returns the path of the main script of a
+// script-based module in the script directory the instance runs it from,
+// Config.ScriptDir or /tmp. The second result is false when the module
+// isn't registered or doesn't run a script.
+func (r *RPITX) ModuleNameToScriptName(moduleName ModuleName) (string, bool) {
+	if !r.IsScriptModule(moduleName) {
+		return "", false
 	}
 
-	err = os.MkdirAll(
-		filepath.Dir(modulationPath),
-		dirPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to create script directory: %v", err)
-	}
+	return scriptPath(r.scriptDir(), moduleName)
+}
 
-	// Write FSK script
-	err = os.WriteFile(
-		fskScriptPath,
-		[]byte(fskScript),
-		scriptPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to write FSK script: %v", err)
+// scriptPath returns the path of the main script of a module deployed in
+// dir.
+func scriptPath(dir string, moduleName ModuleName) (string, bool) {
+	switch moduleName {
+	case ModuleNameFSK:
+		return filepath.Join(dir, fskScriptName), true
+	case ModuleNameAudioSockBroadcast:
+		return filepath.Join(dir, audioSockBroadcastName), true
+	case ModuleNamePIDTMF:
+		return filepath.Join(dir, dtmfScriptName), true
+	case ModuleNameSSB:
+		return filepath.Join(dir, ssbScriptName), true
+	default:
+		return "", false
 	}
+}
 
-	err = os.Chmod(fskScriptPath, execPerm)
-	if err != nil {
-		logrus.Fatalf("failed to make FSK script executable: %v", err)
+// EnsureScriptExists writes the embedded scripts for a script-based module
+// to the script directory the instance runs it from, Config.ScriptDir or
+// /tmp. Scripts are written lazily and only when the file on disk is missing or
+// its hash doesn't match the embedded copy, so modules without scripts never
+// touch the filesystem. Pass force to rewrite them unconditionally. It's a
+// no-op for other modules.
+func (r *RPITX) EnsureScriptExists(moduleName ModuleName, force bool) error {
+	if !r.IsScriptModule(moduleName) {
+		return nil
 	}
 
-	// Write AudioSock script
-	err = os.WriteFile(
-		audioSockBroadcastPath,
-		[]byte(audioSockBroadcastScript),
-		scriptPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to write AudioSock script: %v", err)
+	written, err := ensureScripts(r.scriptDir(), moduleName, force)
+	for _, path := range written {
+		r.log().Infof("wrote embedded script %s", path)
 	}
 
-	err = os.Chmod(audioSockBroadcastPath, execPerm)
-	if err != nil {
-		logrus.Fatalf("failed to make AudioSock script executable: %v", err)
-	}
+	return err
+}
 
-	// Write modulation script
-	err = os.WriteFile(
-		modulationPath,
-		[]byte(modulationScript),
-		scriptPerm,
-	)
-	if err != nil {
-		logrus.Fatalf("failed to write modulation script: %v", err)
+// scriptFile is an embedded script and the path it is deployed to.
+type scriptFile struct {
+	path    string
+	content string
+}
+
+// moduleScripts returns every script a module needs deployed in dir, main
+// script first.
+func moduleScripts(dir string, moduleName ModuleName) ([]scriptFile, error) {
+	mainPath, isScript := scriptPath(dir, moduleName)
+	if !isScript {
+		return nil, nil
 	}
 
-	err = os.Chmod(modulationPath, execPerm)
+	content, err := getScriptContent(moduleName)
 	if err != nil {
-		logrus.Fatalf("failed to make modulation script executable: %v", err)
+		return nil, err
 	}
-}
 
-// ModuleNameToScriptName returns the script path for script-based modules.
-func ModuleNameToScriptName(moduleName ModuleName) (string, bool) {
-	switch moduleName {
-	case ModuleNameFSK:
-		return fskScriptPath, true
-	case ModuleNameAudioSockBroadcast:
-		return audioSockBroadcastPath, true
-	default:
-		return "", false
+	scripts := []scriptFile{{path: mainPath, content: content}}
+
+	// AudioSock, DTMF and SSB pipe their audio through the shared
+	// modulation script, which they expect next to themselves
+	if moduleName == ModuleNameAudioSockBroadcast ||
+		moduleName == ModuleNamePIDTMF ||
+		moduleName == ModuleNameSSB {
+		scripts = append(scripts, scriptFile{
+			path:    filepath.Join(dir, modulationName),
+			content: modulationScript,
+		})
 	}
+
+	return scripts, nil
 }
 
-// EnsureScriptExists writes the embedded script if it doesn't exist.
-func EnsureScriptExists(moduleName ModuleName) error {
-	scriptPath, isScript := ModuleNameToScriptName(moduleName)
-	if !isScript {
-		return nil
+// ensureScripts deploys the scripts of a module in dir and returns the paths
+// it (re)wrote.
+func ensureScripts(
+	dir string,
+	moduleName ModuleName,
+	force bool,
+) ([]string, error) {
+	scripts, err := moduleScripts(dir, moduleName)
+	if err != nil {
+		return nil, err
 	}
 
-	if scriptExists(scriptPath) {
-		return ensureAudioSockModulation(moduleName)
-	}
+	var written []string
 
-	return writeScript(moduleName, scriptPath)
-}
+	for _, script := range scripts {
+		if !force && scriptUpToDate(script.path, script.content) {
+			continue
+		}
 
-// scriptExists checks if a script file exists.
-func scriptExists(scriptPath string) bool {
-	_, err := os.Stat(scriptPath)
+		if err := writeScript(script.path, script.content); err != nil {
+			return written, err
+		}
+
+		written = append(written, script.path)
+	}
 
-	return err == nil
+	return written, nil
 }
 
-// ensureAudioSockModulation ensures modulation script exists for AudioSock.
-func ensureAudioSockModulation(moduleName ModuleName) error {
-	if moduleName != ModuleNameAudioSockBroadcast {
-		return nil
+// ensureHelperScript deploys a helper script binary modules are run
+// through, like the repeat script, in dir and returns its path and whether
+// it was (re)written.
+func ensureHelperScript(dir, name, content string) (string, bool, error) {
+	path := filepath.Join(dir, name)
+	if scriptUpToDate(path, content) {
+		return path, false, nil
 	}
 
-	if _, err := os.Stat(modulationPath); err != nil {
-		return ensureModulationScript(scriptPerm, execPerm)
+	if err := writeScript(path, content); err != nil {
+		return "", false, err
 	}
 
-	return nil
+	return path, true, nil
 }
 
-// writeScript writes a script to the filesystem.
-func writeScript(moduleName ModuleName, scriptPath string) error {
-	scriptContent, err := getScriptContent(moduleName)
+// scriptUpToDate reports whether the file at scriptPath has the same hash as
+// the embedded content.
+func scriptUpToDate(scriptPath, content string) bool {
+	onDisk, err := os.ReadFile(scriptPath)
 	if err != nil {
-		return err
+		return false
 	}
 
-	if err := createScriptDir(scriptPath); err != nil {
-		return err
-	}
+	return sha256.Sum256(onDisk) == sha256.Sum256([]byte(content))
+}
 
-	if err := writeScriptFile(scriptPath, scriptContent); err != nil {
+// writeScript writes a script to the filesystem and makes it executable.
+func writeScript(scriptPath, content string) error {
+	if err := createScriptDir(scriptPath); err != nil {
 		return err
 	}
 
-	if err := makeExecutable(scriptPath); err != nil {
+	if err := writeScriptFile(scriptPath, content); err != nil {
 		return err
 	}
 
-	return ensureAudioSockModulation(moduleName)
+	return makeExecutable(scriptPath)
 }
 
 // getScriptContent returns the embedded script content for a module.
@@ -193,6 +230,10 @@ func getScriptContent(moduleName ModuleName) (string, error) {
 		return fskScript, nil
 	case ModuleNameAudioSockBroadcast:
 		return audioSockBroadcastScript, nil
+	case ModuleNamePIDTMF:
+		return dtmfScript, nil
+	case ModuleNameSSB:
+		return ssbScript, nil
 	default:
 		return "", ctxerrors.Wrapf(
 			ErrUnknownModule,
@@ -247,37 +288,14 @@ func makeExecutable(scriptPath string) error {
 	return nil
 }
 
-// ensureModulationScript writes modulation.sh if it doesn't exist.
-func ensureModulationScript(scriptPerm, execPerm os.FileMode) error {
-	// Check if script already exists
-	if _, err := os.Stat(modulationPath); err == nil {
-		return nil // Script already exists
+// IsScriptModule reports whether the module registered under the name runs
+// an embedded script. It is false for modules that aren't registered.
+func (r *RPITX) IsScriptModule(moduleName ModuleName) bool {
+	if _, ok := r.module(moduleName); !ok {
+		return false
 	}
 
-	if err := os.WriteFile(
-		modulationPath,
-		[]byte(modulationScript),
-		scriptPerm,
-	); err != nil {
-		return ctxerrors.Wrapf(err,
-			"failed to write modulation.sh: %s", modulationPath)
-	}
-
-	// Make modulation.sh executable
-	if err := os.Chmod(modulationPath, execPerm); err != nil {
-		return ctxerrors.Wrapf(
-			err,
-			"failed to make modulation.sh executable: %s",
-			modulationPath,
-		)
-	}
-
-	return nil
-}
-
-// IsScriptModule returns true if the module uses an embedded script.
-func IsScriptModule(moduleName ModuleName) bool {
-	_, isScript := ModuleNameToScriptName(moduleName)
+	_, isScript := scriptPath(r.scriptDir(), moduleName)
 
 	return isScript
 }
@@ -1,8 +1,8 @@
 #!/bin/bash
 
 # AudioSock Broadcast Script
-# Reads audio from unix socket and transmits via rpitx with modulation types
-# Usage: ./audiosock_broadcast.sh <frequency_hz> <unix_socket_path> <sample_rate> <modulation> <gain>
+# Reads audio from a unix or tcp socket and transmits via rpitx with modulation types
+# Usage: ./audiosock_broadcast.sh <frequency_hz> <socket_path_or_host:port> <sample_rate> <modulation> <gain> [unix|tcp] [ramp_ms]
 
 # Configuration
 FREQUENCY="${1:-144500000}"  # Default 144.5 MHz
@@ -10,6 +10,8 @@ SOCKET_PATH="${2:-/tmp/audio_socket}"
 SAMPLE_RATE="${3:-48000}"
 MODULATION="${4:-FM}"          # Default FM modulation
 GAIN="${5:-1.0}"  # Default gain
+SOURCE_TYPE="${6:-unix}"       # unix socket path or tcp host:port
+RAMP_MS="${7:-0}"              # Audio fade-in length, 0 disables it
 LOG_FILE="/tmp/audiosock_broadcast.log"
 
 # Function to log events
@@ -28,11 +30,23 @@ cleanup() {
 # Set up signal handlers
 trap cleanup SIGINT SIGTERM
 
-# Check if socket exists
-if [ ! -S "$SOCKET_PATH" ]; then
-    log_event "ERROR: Unix socket $SOCKET_PATH does not exist"
-    exit 1
-fi
+# Check if socket exists, tcp sources are only reachable once connected
+case "$SOURCE_TYPE" in
+    unix)
+        if [ ! -S "$SOCKET_PATH" ]; then
+            log_event "ERROR: Unix socket $SOCKET_PATH does not exist"
+            exit 1
+        fi
+        SOCAT_ADDRESS="UNIX-CONNECT:$SOCKET_PATH"
+        ;;
+    tcp)
+        SOCAT_ADDRESS="TCP:$SOCKET_PATH"
+        ;;
+    *)
+        log_event "ERROR: Unknown source type $SOURCE_TYPE"
+        exit 1
+        ;;
+esac
 
 # Check if rpitx sendiq exists
 SENDIQ_PATH="./sendiq"
@@ -45,7 +59,7 @@ if [ ! -f "$SENDIQ_PATH" ]; then
     exit 1
 fi
 
-log_event "Starting AudioSock broadcast on $FREQUENCY Hz from socket $SOCKET_PATH"
+log_event "Starting AudioSock broadcast on $FREQUENCY Hz from $SOURCE_TYPE socket $SOCKET_PATH"
 log_event "Sample rate: $SAMPLE_RATE Hz"
 log_event "Modulation: $MODULATION"
 log_event "Gain: $GAIN"
@@ -53,12 +67,31 @@ log_event "Using sendiq path: $SENDIQ_PATH"
 
 # Main AudioSock transmission pipeline using modulation types
 log_event "Using modulation: $MODULATION with gain $GAIN"
-log_event "Full command: socat UNIX-CONNECT:$SOCKET_PATH STDOUT | modulation.sh $MODULATION $GAIN | $SENDIQ_PATH -i /dev/stdin -s $SAMPLE_RATE -f $FREQUENCY -t float"
+log_event "Full command: socat $SOCAT_ADDRESS STDOUT | fade in $RAMP_MS ms | modulation.sh $MODULATION $GAIN | $SENDIQ_PATH -i /dev/stdin -s $SAMPLE_RATE -f $FREQUENCY -t float"
+
+# Use modulation.sh from the directory this script is deployed in
+MODULATION_PATH="$(dirname "$0")/modulation.sh"
 
-# Use modulation.sh from same tmp directory
-MODULATION_PATH="/tmp/modulation.sh"
+# Linearly fade the audio in from silence. This only shapes the audio, the
+# carrier keys at full power: FM has a constant envelope and agc_ff levels
+# the AM/DSB/SSB envelope back up
+ramp() {
+    if [ "$RAMP_MS" -gt 0 ]; then
+        RAMP_SECONDS=$(awk "BEGIN { print $RAMP_MS / 1000 }")
+        sox -q --buffer 1024 \
+            -t raw -r "$SAMPLE_RATE" -e signed-integer -b 16 -c 1 - \
+            -t raw - fade t "$RAMP_SECONDS"
+    else
+        cat
+    fi
+}
+
+if [ "$RAMP_MS" -gt 0 ]; then
+    log_event "Fading audio in over $RAMP_MS ms"
+fi
 
-socat UNIX-CONNECT:"$SOCKET_PATH" STDOUT | \
+socat "$SOCAT_ADDRESS" STDOUT | \
+ramp | \
 "$MODULATION_PATH" "$MODULATION" "$GAIN" | \
 "$SENDIQ_PATH" -i /dev/stdin -s "$SAMPLE_RATE" -f "$FREQUENCY" -t float
 
@@ -0,0 +1,78 @@
+#!/bin/bash
+set -e
+set -o pipefail
+
+# Script parameters
+FREQUENCY="$1"
+DIGITS="$2"
+TONE_MS="${3:-100}"
+GAP_MS="${4:-100}"
+
+# Validate parameters
+if [ -z "$FREQUENCY" ] || [ -z "$DIGITS" ]; then
+    echo "Usage: $0 <frequency_hz> <digits> [tone_ms] [gap_ms]" >&2
+    exit 1
+fi
+
+SAMPLE_RATE=48000
+SENDIQ_PATH="${RPITX_PATH}/sendiq"
+
+# Use modulation.sh from the directory this script is deployed in
+MODULATION_PATH="$(dirname "$0")/modulation.sh"
+
+TONE_SECONDS=$(awk "BEGIN { print $TONE_MS / 1000 }")
+GAP_SECONDS=$(awk "BEGIN { print $GAP_MS / 1000 }")
+
+# tones prints the low and high frequencies of a DTMF digit
+tones() {
+    case "$1" in
+        1) echo "697 1209" ;;
+        2) echo "697 1336" ;;
+        3) echo "697 1477" ;;
+        A) echo "697 1633" ;;
+        4) echo "770 1209" ;;
+        5) echo "770 1336" ;;
+        6) echo "770 1477" ;;
+        B) echo "770 1633" ;;
+        7) echo "852 1209" ;;
+        8) echo "852 1336" ;;
+        9) echo "852 1477" ;;
+        C) echo "852 1633" ;;
+        "*") echo "941 1209" ;;
+        0) echo "941 1336" ;;
+        "#") echo "941 1477" ;;
+        D) echo "941 1633" ;;
+        *)
+            echo "Invalid DTMF digit: $1" >&2
+            exit 1
+            ;;
+    esac
+}
+
+# generate writes the tone sequence as raw 16-bit mono audio to stdout
+generate() {
+    local i digit low high
+
+    for (( i = 0; i < ${#DIGITS}; i++ )); do
+        digit="${DIGITS:$i:1}"
+        read -r low high <<< "$(tones "$digit")"
+
+        echo "Sending digit $digit ($low Hz + $high Hz)" >&2
+
+        sox -n -r "$SAMPLE_RATE" -b 16 -e signed -t raw - \
+            synth "$TONE_SECONDS" sine "$low" sine "$high" channels 1
+
+        if [ "$GAP_MS" -gt 0 ]; then
+            sox -n -r "$SAMPLE_RATE" -c 1 -b 16 -e signed -t raw - \
+                trim 0 "$GAP_SECONDS"
+        fi
+    done
+}
+
+echo "Transmitting DTMF $DIGITS at ${FREQUENCY} Hz..."
+
+generate | \
+"$MODULATION_PATH" FM 1.0 | \
+"$SENDIQ_PATH" -i /dev/stdin -s "$SAMPLE_RATE" -f "$FREQUENCY" -t float
+
+echo "DTMF transmission completed successfully"
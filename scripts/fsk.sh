@@ -1,5 +1,6 @@
 #!/bin/bash
 set -e
+set -o pipefail
 
 # Script parameters
 BAUD_RATE="$1"
@@ -7,30 +8,47 @@ FREQUENCY="$2"
 
 # Validate parameters
 if [ -z "$BAUD_RATE" ] || [ -z "$FREQUENCY" ]; then
-    echo "Usage: $0 <baud_rate> <frequency_hz>" >&2
+    echo "Usage: $0 <baud_rate> <frequency_hz> [minimodem_options...]" >&2
     exit 1
 fi
 
-# Generate unique temp file
-TEMP_FILE="/tmp/fsk_$$.wav"
+# Any remaining arguments are passed through to minimodem
+shift 2
+
+# minimodem only writes audio to files, so it writes to a FIFO that sox
+# reads as the audio is generated and the input is transmitted while it is
+# still being read. AU is used as it can be written to a pipe, unlike WAV.
+FIFO_DIR="$(mktemp -d /tmp/fsk_XXXXXX)"
+FIFO="$FIFO_DIR/audio.au"
 
 # Cleanup function
 cleanup() {
-    rm -f "$TEMP_FILE"
+    rm -rf "$FIFO_DIR"
 }
 trap cleanup EXIT
 
-# Process pipeline with progress reporting
-echo "Encoding input to FSK audio at ${BAUD_RATE} baud..."
-if ! cat | minimodem --tx "$BAUD_RATE" -f "$TEMP_FILE"; then
+mkfifo "$FIFO"
+
+echo "Encoding input to FSK audio at ${BAUD_RATE} baud and transmitting at ${FREQUENCY} Hz..."
+
+# Converted to 16-bit 48kHz stereo and transmitted in the background, sox
+# waits for minimodem to open the FIFO
+sox -t au "$FIFO" -t raw -e signed -b 16 -r 48000 -c 2 - | \
+"${RPITX_PATH}/sendiq" -i /dev/stdin -s 48000 -f "$FREQUENCY" -t i16 &
+TRANSMIT_PID=$!
+
+if ! minimodem --tx "$@" "$BAUD_RATE" -f "$FIFO"; then
     echo "Failed to encode input to FSK audio" >&2
+
+    # Open and close the FIFO so sox doesn't wait for a writer forever
+    : <>"$FIFO"
+    wait "$TRANSMIT_PID" || true
     exit 1
 fi
 
-echo "Converting to 16-bit 48kHz stereo and transmitting at ${FREQUENCY} Hz..."
-if ! sox "$TEMP_FILE" -t raw -e signed -b 16 -r 48000 -c 2 - | "${RPITX_PATH}/sendiq" -i /dev/stdin -s 48000 -f "$FREQUENCY" -t i16; then
+if ! wait "$TRANSMIT_PID"; then
     echo "Failed to convert and transmit FSK data" >&2
     exit 1
 fi
 
-echo "FSK transmission completed successfully"
\ No newline at end of file
+echo "FSK transmission completed successfully"
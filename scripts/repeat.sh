@@ -0,0 +1,39 @@
+#!/bin/bash
+set -e
+
+# Script parameters
+COUNT="$1"
+GAP_MS="$2"
+
+# Validate parameters
+if [ -z "$COUNT" ] || [ -z "$GAP_MS" ] || [ -z "$3" ]; then
+    echo "Usage: $0 <count> <gap_ms> <command> [args...]" >&2
+    exit 1
+fi
+
+# The remaining arguments are the command to repeat
+shift 2
+
+# Keep stdin so every run gets the same input, in a file only this script
+# can have created
+TEMP_FILE="$(mktemp /tmp/repeat_XXXXXX)"
+
+# Cleanup function
+cleanup() {
+    rm -f "$TEMP_FILE"
+}
+trap cleanup EXIT
+
+cat > "$TEMP_FILE"
+
+GAP_SECONDS=$(awk "BEGIN { print $GAP_MS / 1000 }")
+
+# Each run is a separate process, so the carrier is off during the gap
+for (( i = 1; i <= COUNT; i++ )); do
+    echo "Transmission $i of $COUNT..."
+    "$@" < "$TEMP_FILE"
+
+    if [ "$i" -lt "$COUNT" ] && [ "$GAP_MS" -gt 0 ]; then
+        sleep "$GAP_SECONDS"
+    fi
+done
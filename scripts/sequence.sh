@@ -0,0 +1,23 @@
+#!/bin/bash
+set -e
+
+# Script parameters
+COUNT="$1"
+
+# Validate parameters
+if [ -z "$COUNT" ] || [ "$#" -lt $((COUNT + 2)) ]; then
+    echo "Usage: $0 <count> <value>... <command> [args...]" >&2
+    exit 1
+fi
+
+shift
+
+# The first COUNT arguments are the values, the rest is the command
+VALUES=("${@:1:$COUNT}")
+shift "$COUNT"
+
+# Each value is appended to a separate run of the command
+for (( i = 0; i < COUNT; i++ )); do
+    echo "Transmission $((i + 1)) of $COUNT: ${VALUES[$i]}"
+    "$@" "${VALUES[$i]}"
+done
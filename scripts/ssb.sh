@@ -0,0 +1,29 @@
+#!/bin/bash
+set -e
+set -o pipefail
+
+# Script parameters
+FREQUENCY="$1"
+AUDIO="$2"
+MODE="$3"
+
+# Validate parameters
+if [ -z "$FREQUENCY" ] || [ -z "$AUDIO" ] || [ -z "$MODE" ]; then
+    echo "Usage: $0 <frequency_hz> <audio_wav> <USB|LSB>" >&2
+    exit 1
+fi
+
+SAMPLE_RATE=48000
+SENDIQ_PATH="${RPITX_PATH}/sendiq"
+
+# Use modulation.sh from the directory this script is deployed in
+MODULATION_PATH="$(dirname "$0")/modulation.sh"
+
+echo "Transmitting $AUDIO in $MODE at ${FREQUENCY} Hz..."
+
+# Convert to the raw 16-bit mono audio the modulation expects
+sox "$AUDIO" -t raw -r "$SAMPLE_RATE" -e signed -b 16 -c 1 - | \
+"$MODULATION_PATH" "$MODE" 1.0 | \
+"$SENDIQ_PATH" -i /dev/stdin -s "$SAMPLE_RATE" -f "$FREQUENCY" -t float
+
+echo "SSB transmission completed successfully"
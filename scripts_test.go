@@ -1,71 +1,89 @@
 package gorpitx
 
 import (
+	"bufio"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestScriptExists(t *testing.T) {
-	// Test with existing file
-	tempFile := "/tmp/test_script_exists.sh"
-	err := os.WriteFile(tempFile, []byte("test"), 0o600)
-	require.NoError(t, err)
+func TestScriptUpToDate(t *testing.T) {
+	tempFile := "/tmp/test_script_up_to_date.sh"
 
 	defer func() { _ = os.Remove(tempFile) }()
 
-	assert.True(t, scriptExists(tempFile))
+	// Missing file
+	assert.False(t, scriptUpToDate(tempFile, "echo hi"))
 
-	// Test with non-existing file
-	assert.False(t, scriptExists("/tmp/nonexistent_file.sh"))
+	require.NoError(t, os.WriteFile(tempFile, []byte("echo hi"), 0o600))
+
+	assert.True(t, scriptUpToDate(tempFile, "echo hi"))
+	assert.False(t, scriptUpToDate(tempFile, "echo bye"))
 }
 
-func TestEnsureAudioSockModulation(t *testing.T) {
+func TestModuleScripts(t *testing.T) {
+	dir := t.TempDir()
+
 	tests := []struct {
-		name       string
-		moduleName ModuleName
-		setupFunc  func()
-		expectErr  bool
+		name          string
+		moduleName    ModuleName
+		expectedPaths []string
 	}{
 		{
-			name:       "non-audiosock module",
-			moduleName: ModuleNameFSK,
-			setupFunc:  func() {},
-			expectErr:  false,
+			name:       "non-script module",
+			moduleName: ModuleNameTUNE,
+		},
+		{
+			name:          "fsk",
+			moduleName:    ModuleNameFSK,
+			expectedPaths: []string{filepath.Join(dir, fskScriptName)},
 		},
 		{
-			name:       "audiosock module with existing modulation",
+			name:       "audiosock includes modulation",
 			moduleName: ModuleNameAudioSockBroadcast,
-			setupFunc: func() {
-				_ = os.WriteFile(modulationPath, []byte("test"), 0o600)
+			expectedPaths: []string{
+				filepath.Join(dir, audioSockBroadcastName),
+				filepath.Join(dir, modulationName),
 			},
-			expectErr: false,
 		},
 		{
-			name:       "audiosock module without modulation",
-			moduleName: ModuleNameAudioSockBroadcast,
-			setupFunc: func() {
-				_ = os.Remove(modulationPath)
+			name:       "dtmf includes modulation",
+			moduleName: ModuleNamePIDTMF,
+			expectedPaths: []string{
+				filepath.Join(dir, dtmfScriptName),
+				filepath.Join(dir, modulationName),
+			},
+		},
+		{
+			name:       "ssb includes modulation",
+			moduleName: ModuleNameSSB,
+			expectedPaths: []string{
+				filepath.Join(dir, ssbScriptName),
+				filepath.Join(dir, modulationName),
 			},
-			expectErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.setupFunc()
+			scripts, err := moduleScripts(dir, tt.moduleName)
+			require.NoError(t, err)
 
-			defer func() { _ = os.Remove(modulationPath) }()
+			var paths []string
+			for _, script := range scripts {
+				assert.NotEmpty(t, script.content)
 
-			err := ensureAudioSockModulation(tt.moduleName)
-			if tt.expectErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
+				paths = append(paths, script.path)
 			}
+
+			assert.Equal(t, tt.expectedPaths, paths)
 		})
 	}
 }
@@ -86,6 +104,16 @@ func TestGetScriptContent(t *testing.T) {
 			moduleName: ModuleNameAudioSockBroadcast,
 			expectErr:  false,
 		},
+		{
+			name:       "DTMF module",
+			moduleName: ModuleNamePIDTMF,
+			expectErr:  false,
+		},
+		{
+			name:       "SSB module",
+			moduleName: ModuleNameSSB,
+			expectErr:  false,
+		},
 		{
 			name:       "unknown module",
 			moduleName: ModuleName("unknown"),
@@ -163,7 +191,7 @@ func TestWriteScript(t *testing.T) {
 	// Clean up
 	defer func() { _ = os.RemoveAll(tempDir) }()
 
-	err := writeScript(ModuleNameFSK, testPath)
+	err := writeScript(testPath, fskScript)
 	assert.NoError(t, err)
 
 	// Verify script was written and is executable
@@ -176,3 +204,239 @@ func TestWriteScript(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, string(content), "#!/bin/bash")
 }
+
+func TestRPITX_EnsureScriptExists(t *testing.T) {
+	dir := t.TempDir()
+	rpitx := &RPITX{
+		config:  Config{ScriptDir: dir},
+		modules: defaultModules(),
+	}
+
+	fskScriptPath := filepath.Join(dir, fskScriptName)
+	audioSockBroadcastPath := filepath.Join(dir, audioSockBroadcastName)
+	modulationPath := filepath.Join(dir, modulationName)
+
+	tests := []struct {
+		name            string
+		moduleName      ModuleName
+		force           bool
+		setup           func()
+		expectedWritten []string
+	}{
+		{
+			name:       "non-script module is a no-op",
+			moduleName: ModuleNameTUNE,
+		},
+		{
+			name:       "stale script is rewritten",
+			moduleName: ModuleNameFSK,
+			setup: func() {
+				_ = os.WriteFile(fskScriptPath, []byte("stale"), scriptPerm)
+			},
+			expectedWritten: []string{fskScriptPath},
+		},
+		{
+			name:       "missing script is written",
+			moduleName: ModuleNameFSK,
+			setup: func() {
+				_ = os.Remove(fskScriptPath)
+			},
+			expectedWritten: []string{fskScriptPath},
+		},
+		{
+			name:       "matching script is left alone",
+			moduleName: ModuleNameFSK,
+		},
+		{
+			name:            "force rewrites matching script",
+			moduleName:      ModuleNameFSK,
+			force:           true,
+			expectedWritten: []string{fskScriptPath},
+		},
+		{
+			name:       "stale modulation script is rewritten",
+			moduleName: ModuleNameAudioSockBroadcast,
+			setup: func() {
+				_ = writeScript(audioSockBroadcastPath, audioSockBroadcastScript)
+				_ = os.WriteFile(modulationPath, []byte("stale"), scriptPerm)
+			},
+			expectedWritten: []string{modulationPath},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			written, err := ensureScripts(dir, tt.moduleName, tt.force)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedWritten, written)
+
+			scripts, err := moduleScripts(dir, tt.moduleName)
+			require.NoError(t, err)
+
+			for _, script := range scripts {
+				assert.True(t, scriptUpToDate(script.path, script.content))
+			}
+
+			require.NoError(t, rpitx.EnsureScriptExists(tt.moduleName, false))
+		})
+	}
+
+	// Forcing goes to the configured directory, not /tmp
+	require.NoError(t, os.Remove(fskScriptPath))
+	require.NoError(t, rpitx.EnsureScriptExists(ModuleNameFSK, true))
+	assert.True(t, scriptUpToDate(fskScriptPath, fskScript))
+}
+
+func TestRPITX_ScriptModules(t *testing.T) {
+	dir := t.TempDir()
+	rpitx := &RPITX{
+		config:  Config{ScriptDir: dir},
+		modules: defaultModules(),
+	}
+
+	path, ok := rpitx.ModuleNameToScriptName(ModuleNameFSK)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, fskScriptName), path)
+	assert.True(t, rpitx.IsScriptModule(ModuleNameFSK))
+
+	path, ok = rpitx.ModuleNameToScriptName(ModuleNameTUNE)
+	assert.False(t, ok)
+	assert.Empty(t, path)
+	assert.False(t, rpitx.IsScriptModule(ModuleNameTUNE))
+
+	// A script module that isn't registered doesn't run a script
+	rpitx.modules = map[ModuleName]Module{ModuleNameTUNE: &TUNE{}}
+
+	_, ok = rpitx.ModuleNameToScriptName(ModuleNameFSK)
+	assert.False(t, ok)
+	assert.False(t, rpitx.IsScriptModule(ModuleNameFSK))
+	require.NoError(t, rpitx.EnsureScriptExists(ModuleNameFSK, true))
+	assert.NoFileExists(t, filepath.Join(dir, fskScriptName))
+}
+
+func TestHelperScripts(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		script   string
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{
+			name:   "repeat feeds stdin to every run",
+			script: repeatScriptName,
+			args:   []string{"2", "0", "sed", "s/^/run: /"},
+			stdin:  "1:hello\n",
+			expected: "Transmission 1 of 2...\nrun: 1:hello\n" +
+				"Transmission 2 of 2...\nrun: 1:hello\n",
+		},
+		{
+			name:   "sequence appends one value per run",
+			script: sequenceScriptName,
+			args:   []string{"2", "CQ W1AW FN31", "RR73", "echo", "-m"},
+			expected: "Transmission 1 of 2: CQ W1AW FN31\n-m CQ W1AW FN31\n" +
+				"Transmission 2 of 2: RR73\n-m RR73\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, _, err := ensureHelperScript(
+				dir, tt.script, helperScripts[tt.script],
+			)
+			require.NoError(t, err)
+
+			cmd := exec.Command(path, tt.args...)
+			cmd.Stdin = strings.NewReader(tt.stdin)
+
+			output, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(output))
+			assert.Equal(t, tt.expected, string(output))
+		})
+	}
+}
+
+func TestFSKScript_TransmitsBeforeEndOfInput(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ensureScripts(dir, ModuleNameFSK, false)
+	require.NoError(t, err)
+
+	path, _ := scriptPath(dir, ModuleNameFSK)
+
+	// Stand-ins passing the input through as it comes: minimodem writes it
+	// to its output file, sox reads it from the FIFO and sendiq prints it
+	bin := t.TempDir()
+	fakes := map[string]string{
+		"minimodem": `while [ "$1" != "-f" ]; do shift; done; cat > "$2"`,
+		"sox":       `for a; do [ -p "$a" ] && exec cat "$a"; done; exit 1`,
+		"sendiq":    `while IFS= read -r line; do echo "sent: $line"; done`,
+	}
+
+	for name, body := range fakes {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(bin, name), []byte("#!/bin/sh\n"+body+"\n"), 0o700,
+		))
+	}
+
+	cmd := exec.Command(path, "45.45", "14080000", "--baudot")
+	cmd.Env = append(
+		os.Environ(),
+		"PATH="+bin+":"+os.Getenv("PATH"),
+		envVarNameRpitxPath+"="+bin,
+	)
+
+	stdin, err := cmd.StdinPipe()
+	require.NoError(t, err)
+
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+
+	require.NoError(t, cmd.Start())
+
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	lines := make(chan string)
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	_, err = io.WriteString(stdin, "CQ CQ DE W1AW\n")
+	require.NoError(t, err)
+
+	// The first line goes out while the input is still open
+	timeout := time.After(3 * time.Second)
+
+	for sent := false; !sent; {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "script ended before the input did")
+
+			sent = line == "sent: CQ CQ DE W1AW"
+		case <-timeout:
+			t.Fatal("nothing transmitted before the end of the input")
+		}
+	}
+
+	require.NoError(t, stdin.Close())
+
+	var rest []string
+	for line := range lines {
+		rest = append(rest, line)
+	}
+
+	require.NoError(t, cmd.Wait())
+	assert.Equal(t, []string{"FSK transmission completed successfully"}, rest)
+}
@@ -0,0 +1,165 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	ModuleNameSENDIQ ModuleName = "sendiq"
+)
+
+// IQFormat defines the sample format of an IQ file.
+type IQFormat = string
+
+const (
+	// IQFormatI16 is interleaved signed 16-bit I/Q samples.
+	IQFormatI16 IQFormat = "i16"
+	// IQFormatU8 is interleaved unsigned 8-bit I/Q samples, as recorded by
+	// RTL-SDR dongles.
+	IQFormatU8 IQFormat = "u8"
+	// IQFormatFloat is interleaved 32-bit float I/Q samples, as written by
+	// GNU Radio file sinks.
+	IQFormatFloat IQFormat = "float"
+)
+
+type SENDIQ struct {
+	// FilePath specifies the path to the IQ file to transmit. Required
+	// parameter. File must exist and be accessible.
+	FilePath string `json:"filePath"`
+
+	// Frequency specifies the carrier frequency in Hz. Required parameter.
+	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
+
+	// SampleRate specifies the sample rate of the IQ file in Hz. Optional
+	// parameter. Must be positive if specified. Default: 48000 Hz
+	SampleRate *int `json:"sampleRate,omitempty" schema:"exclusiveMinimum=0"`
+
+	// Format specifies the sample format of the IQ file ("i16", "u8" or
+	// "float"). Optional parameter. Default: "i16"
+	Format IQFormat `json:"format,omitempty" schema:"enum=i16|u8|float"`
+}
+
+func (m *SENDIQ) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
+	if err := json.Unmarshal(args, m); err != nil {
+		return nil, nil, ctxerrors.Wrap(err, "failed to unmarshal args")
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return m.buildArgs(), nil, nil
+}
+
+// buildArgs converts the struct fields into command-line arguments for
+// sendiq binary.
+func (m *SENDIQ) buildArgs() []string {
+	var args []string
+
+	// Add input file argument (required)
+	args = append(args, "-i", m.FilePath)
+
+	// Add frequency argument (required)
+	args = append(args, "-f",
+		strconv.FormatFloat(m.Frequency, 'f', 0, 64))
+
+	// Add sample rate argument (optional)
+	if m.SampleRate != nil {
+		args = append(args, "-s", strconv.Itoa(*m.SampleRate))
+	}
+
+	// Add format argument (optional)
+	if m.Format != "" {
+		args = append(args, "-t", m.Format)
+	}
+
+	return args
+}
+
+// validate validates all SENDIQ parameters.
+func (m *SENDIQ) validate() error {
+	return validateFields(
+		fieldCheck{"filePath", m.validateFilePath},
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"sampleRate", m.validateSampleRate},
+		fieldCheck{"format", m.validateFormat},
+	)
+}
+
+// validateFilePath validates the IQ file parameter.
+func (m *SENDIQ) validateFilePath() error {
+	if m.FilePath == "" {
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "filePath")
+	}
+
+	if _, err := os.Stat(m.FilePath); os.IsNotExist(err) {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrFileNotFound,
+			"file: %s",
+			m.FilePath,
+		)
+	}
+
+	return nil
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *SENDIQ) frequencyHz() float64 {
+	return m.Frequency
+}
+
+// validateFrequency validates the frequency parameter.
+func (m *SENDIQ) validateFrequency() error {
+	if m.Frequency <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency must be positive, got: %f",
+			m.Frequency,
+		)
+	}
+
+	// Validate frequency range using Hz-based validation
+	if !isValidFreqHz(m.Frequency) {
+		return ctxerrors.Wrapf(
+			ErrFreqOutOfRange,
+			"(%d kHz to %.0f MHz), got: %f Hz",
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
+		)
+	}
+
+	return nil
+}
+
+// validateSampleRate validates the sample rate parameter.
+func (m *SENDIQ) validateSampleRate() error {
+	if m.SampleRate != nil && *m.SampleRate <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"sampleRate must be positive, got: %d",
+			*m.SampleRate,
+		)
+	}
+
+	return nil
+}
+
+// validateFormat validates the sample format parameter.
+func (m *SENDIQ) validateFormat() error {
+	switch m.Format {
+	case "", IQFormatI16, IQFormatU8, IQFormatFloat:
+		return nil
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"format must be 'i16', 'u8' or 'float', got: %s",
+			m.Format,
+		)
+	}
+}
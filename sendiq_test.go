@@ -0,0 +1,157 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIQFile writes a small IQ file to a temp dir and returns its path.
+func newIQFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "recording.iq")
+	require.NoError(t, os.WriteFile(path, make([]byte, 1024), 0o600))
+
+	return path
+}
+
+func TestSENDIQ_ParseArgs(t *testing.T) {
+	iqFile := newIQFile(t)
+
+	tests := []struct {
+		name        string
+		input       map[string]any
+		expectedErr error
+		expectArgs  []string
+	}{
+		{
+			name: "valid complete args",
+			input: map[string]any{
+				"filePath":   iqFile,
+				"frequency":  434000000.0,
+				"sampleRate": 250000,
+				"format":     IQFormatFloat,
+			},
+			expectArgs: []string{
+				"-i", iqFile, "-f", "434000000", "-s", "250000", "-t", "float",
+			},
+		},
+		{
+			name: "valid minimal args",
+			input: map[string]any{
+				"filePath":  iqFile,
+				"frequency": 144500000.0,
+			},
+			expectArgs: []string{"-i", iqFile, "-f", "144500000"},
+		},
+		{
+			name: "u8 format",
+			input: map[string]any{
+				"filePath":  iqFile,
+				"frequency": 28000000.0,
+				"format":    IQFormatU8,
+			},
+			expectArgs: []string{"-i", iqFile, "-f", "28000000", "-t", "u8"},
+		},
+		{
+			name: "missing file path",
+			input: map[string]any{
+				"frequency": 434000000.0,
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "file does not exist",
+			input: map[string]any{
+				"filePath":  filepath.Join(t.TempDir(), "missing.iq"),
+				"frequency": 434000000.0,
+			},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+		{
+			name: "missing frequency",
+			input: map[string]any{
+				"filePath": iqFile,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "frequency too high",
+			input: map[string]any{
+				"filePath":  iqFile,
+				"frequency": 2000000000.0,
+			},
+			expectedErr: ErrFreqOutOfRange,
+		},
+		{
+			name: "zero sample rate",
+			input: map[string]any{
+				"filePath":   iqFile,
+				"frequency":  434000000.0,
+				"sampleRate": 0,
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "unknown format",
+			input: map[string]any{
+				"filePath":  iqFile,
+				"frequency": 434000000.0,
+				"format":    "wav",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendiq := &SENDIQ{}
+			inputBytes, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+
+			args, stdin, err := sendiq.ParseArgs(inputBytes)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Nil(t, stdin)
+			assert.Equal(t, tt.expectArgs, args)
+		})
+	}
+}
+
+func TestSENDIQ_ValidateFormat(t *testing.T) {
+	tests := []struct {
+		format      IQFormat
+		expectError bool
+	}{
+		{format: ""},
+		{format: IQFormatI16},
+		{format: IQFormatU8},
+		{format: IQFormatFloat},
+		{format: "double", expectError: true},
+		{format: "I16", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := (&SENDIQ{Format: tt.format}).validateFormat()
+			if tt.expectError {
+				require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// Step is a module execution of a sequence run with ExecSequence.
+type Step struct {
+	// Module is the module to run.
+	Module ModuleName
+
+	// Args are the JSON args of the module.
+	Args json.RawMessage
+
+	// Timeout stops the step once it elapses, like the timeout of Exec.
+	// NoTimeout runs it until the module exits.
+	Timeout time.Duration
+}
+
+// ExecSequence runs the steps one after the other and blocks until the last
+// one finishes, e.g. a TUNE calibration right before a MORSE beacon. The
+// execution slot is held for the whole sequence, so no other execution can
+// slip in between two steps. Every step is validated like ValidateBatch
+// before the first one starts, so invalid args never leave a sequence half
+// run. It stops at the first step that fails, returning its error wrapped
+// with the step index, and Stop ends the running step and skips the rest.
+// Every step is a regular execution with its hooks, metrics and
+// transmission log entry, and the ExecOptions apply to all of them. With a
+// transmit cooldown, each step waits for it to elapse after the previous
+// one.
+func (r *RPITX) ExecSequence(
+	ctx context.Context,
+	steps []Step,
+	opts ...ExecOption,
+) error {
+	if len(steps) == 0 {
+		return ctxerrors.Wrap(
+			commonerrors.ErrInvalidValue,
+			"sequence needs at least one step",
+		)
+	}
+
+	for i, step := range steps {
+		if err := checkTimeout(step.Timeout); err != nil {
+			return ctxerrors.Wrapf(err, "step %d", i)
+		}
+
+		if err := r.ValidateArgs(step.Module, step.Args); err != nil {
+			return ctxerrors.Wrapf(err, "step %d (%s)", i, step.Module)
+		}
+	}
+
+	options, err := newExecOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if !r.isExecuting.CompareAndSwap(false, true) {
+		err := r.busyError()
+		r.metrics().ExecutionFailed(steps[0].Module, err)
+
+		return err
+	}
+
+	defer r.isExecuting.Store(false)
+
+	if err := r.checkCooldown(); err != nil {
+		r.metrics().ExecutionFailed(steps[0].Module, err)
+
+		return err
+	}
+
+	// One stop channel for the whole sequence, so a stop between two steps
+	// skips the rest too
+	r.processMu.Lock()
+	r.stopCh = make(chan struct{})
+	r.processMu.Unlock()
+
+	for i, step := range steps {
+		if err := r.waitCooldown(ctx); err != nil {
+			return ctxerrors.Wrapf(err, "step %d", i)
+		}
+
+		if r.stopRequested() {
+			return nil
+		}
+
+		if err := r.execStep(ctx, step, options); err != nil {
+			return ctxerrors.Wrapf(err, "step %d (%s)", i, step.Module)
+		}
+	}
+
+	return nil
+}
+
+// execStep runs a step of a sequence in the execution slot it holds.
+func (r *RPITX) execStep(
+	ctx context.Context,
+	step Step,
+	options execOptions,
+) error {
+	execution, err := r.startExecution(
+		ctx, step.Module, step.Args, step.Timeout, options, true,
+	)
+	if err != nil {
+		return err
+	}
+
+	return execution.Wait()
+}
+
+// waitCooldown waits for the transmit cooldown to elapse, returning early
+// when a stop is requested.
+func (r *RPITX) waitCooldown(ctx context.Context) error {
+	remaining := r.cooldownRemaining()
+	if remaining <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.stopChan():
+		return nil
+	case <-ctx.Done():
+		return ctxerrors.Wrap(ctx.Err(), "cancelled while cooling down")
+	}
+}
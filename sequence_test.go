@@ -0,0 +1,222 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSequenceTestRPITX returns a dev mode RPITX with TUNE and MORSE, whose
+// mock runs until stopped unless iterations is set.
+func newSequenceTestRPITX(t *testing.T, iterations int) *RPITX {
+	t.Helper()
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	return &RPITX{
+		config: Config{Mock: MockConfig{
+			Interval:   10 * time.Millisecond,
+			Iterations: iterations,
+		}},
+		commander: commander.New(),
+		modules: map[ModuleName]Module{
+			ModuleNameTUNE:  &TUNE{},
+			ModuleNameMORSE: &MORSE{},
+		},
+	}
+}
+
+// tuneThenMorse is a calibration carrier followed by a beacon.
+func tuneThenMorse() []Step {
+	return []Step{
+		{
+			Module: ModuleNameTUNE,
+			Args:   []byte(`{"frequency": 434000000}`),
+		},
+		{
+			Module: ModuleNameMORSE,
+			Args: []byte(
+				`{"frequency": 434000000, "rate": 20, "message": "CQ"}`,
+			),
+		},
+	}
+}
+
+// loggedModules returns the modules of the transmission log with their
+// status.
+func loggedModules(rpitx *RPITX) []string {
+	var modules []string
+	for _, record := range rpitx.TransmissionLog() {
+		modules = append(modules, record.Module+" "+string(record.Status))
+	}
+
+	return modules
+}
+
+func TestRPITX_ExecSequence(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 2)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- rpitx.ExecSequence(context.Background(), tuneThenMorse())
+	}()
+
+	require.Eventually(t, func() bool {
+		return rpitx.Status().Module == ModuleNameTUNE
+	}, 3*time.Second, time.Millisecond)
+
+	expected := []string{
+		ModuleNameTUNE + " completed",
+		ModuleNameMORSE + " completed",
+	}
+
+	// Other callers never get the slot, not even between the steps
+	for running := true; running; {
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+
+			running = false
+		default:
+			execution, err := rpitx.ExecAsync(
+				context.Background(), ModuleNameTUNE,
+				[]byte(`{"frequency": 434000000}`), 0,
+			)
+			if err != nil {
+				require.ErrorIs(t, err, ErrExecuting)
+
+				continue
+			}
+
+			// Only once the whole sequence is over
+			assert.Equal(t, expected, loggedModules(rpitx)[:2])
+			require.NoError(t, execution.Wait())
+			require.NoError(t, <-done)
+
+			running = false
+		}
+	}
+
+	assert.Equal(t, expected, loggedModules(rpitx)[:2])
+}
+
+func TestRPITX_ExecSequence_FailedStep(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 1)
+
+	steps := tuneThenMorse()
+	steps[0].Args = []byte(`{"frequency": 1}`)
+
+	err := rpitx.ExecSequence(context.Background(), steps)
+	require.ErrorIs(t, err, ErrFreqOutOfRange)
+	assert.Contains(t, err.Error(), "step 0 (tune)")
+
+	// The MORSE step never started
+	assert.Empty(t, rpitx.TransmissionLog())
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecSequence_InvalidLaterStep(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 1)
+
+	steps := tuneThenMorse()
+	steps[1].Args = []byte(`{"frequency": 434000000, "rate": 20}`)
+
+	err := rpitx.ExecSequence(context.Background(), steps)
+	require.ErrorIs(t, err, commonerrors.ErrRequiredFieldNotSet)
+	assert.Contains(t, err.Error(), "step 1 (morse)")
+
+	// Validated up front, so the TUNE step never transmitted either
+	assert.Empty(t, rpitx.TransmissionLog())
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecSequence_Stop(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 0)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- rpitx.ExecSequence(context.Background(), tuneThenMorse())
+	}()
+
+	require.Eventually(t, func() bool {
+		return rpitx.Status().Module == ModuleNameTUNE
+	}, 3*time.Second, time.Millisecond)
+
+	require.NoError(t, rpitx.Stop(context.Background()))
+
+	select {
+	case err := <-done:
+		// Reported like Exec reports a stopped process
+		require.ErrorIs(t, err, commonerrors.ErrTerminated)
+	case <-time.After(5 * time.Second):
+		t.Fatal("sequence did not end after stop")
+	}
+
+	// MORSE was skipped
+	assert.Equal(
+		t, []string{ModuleNameTUNE + " stopped"}, loggedModules(rpitx),
+	)
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_ExecSequence_Cooldown(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 1)
+	rpitx.transmitCooldown = 200 * time.Millisecond
+
+	start := time.Now()
+
+	require.NoError(t, rpitx.ExecSequence(
+		context.Background(), tuneThenMorse(),
+	))
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestRPITX_ExecSequence_Invalid(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 1)
+
+	tests := []struct {
+		name        string
+		steps       []Step
+		expectedErr error
+	}{
+		{
+			name:        "no steps",
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "negative timeout",
+			steps: []Step{
+				{Module: ModuleNameTUNE, Timeout: -time.Second},
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rpitx.ExecSequence(context.Background(), tt.steps)
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestRPITX_ExecSequence_Busy(t *testing.T) {
+	rpitx := newSequenceTestRPITX(t, 0)
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), 0,
+	)
+	require.NoError(t, err)
+
+	err = rpitx.ExecSequence(context.Background(), tuneThenMorse())
+	require.ErrorIs(t, err, ErrExecuting)
+
+	require.NoError(t, execution.Stop(context.Background()))
+	<-execution.Done()
+}
@@ -0,0 +1,63 @@
+package gorpitx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleSignals stops the running transmission with Stop when the process
+// gets SIGINT or SIGTERM, so a Ctrl-C doesn't leave the carrier on. Nothing
+// executing is fine, the signal is then only logged. Handling the signals
+// replaces their default of exiting the program, so programs that should
+// still exit must watch for them too, e.g. with signal.NotifyContext.
+//
+// The returned func removes the handlers and waits for a stop in progress,
+// it is safe to call more than once. The handlers are also removed once ctx
+// is done.
+func (r *RPITX) HandleSignals(ctx context.Context) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer signal.Stop(sigCh)
+
+		for {
+			select {
+			case sig := <-sigCh:
+				r.stopOnSignal(ctx, sig)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// stopOnSignal stops the running transmission after the signal.
+func (r *RPITX) stopOnSignal(ctx context.Context, sig os.Signal) {
+	r.log().Infof("received %s, stopping transmission", sig)
+
+	// The stop must finish even if the handlers are removed meanwhile
+	err := r.Stop(context.WithoutCancel(ctx))
+	if err == nil || errors.Is(err, ErrNotExecuting) {
+		return
+	}
+
+	r.log().Errorf("failed to stop transmission on %s: %v", sig, err)
+}
@@ -0,0 +1,72 @@
+package gorpitx
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_HandleSignals(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal syscall.Signal
+	}{
+		{name: "SIGINT", signal: syscall.SIGINT},
+		{name: "SIGTERM", signal: syscall.SIGTERM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx, args := newExecutionTestRPITX(t)
+
+			cleanup := rpitx.HandleSignals(context.Background())
+			defer cleanup()
+
+			execution, err := rpitx.ExecAsync(
+				context.Background(), ModuleNameMORSE, args, 0,
+			)
+			require.NoError(t, err)
+
+			require.NoError(t, syscall.Kill(os.Getpid(), tt.signal))
+
+			select {
+			case <-execution.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("execution did not stop on signal")
+			}
+
+			assert.ErrorIs(t, execution.Wait(), commonerrors.ErrTerminated)
+			assert.False(t, rpitx.isExecuting.Load())
+		})
+	}
+}
+
+func TestRPITX_HandleSignals_NotExecuting(t *testing.T) {
+	rpitx, _ := newExecutionTestRPITX(t)
+	logger := &recordingLogger{messages: make(chan string, 10)}
+	rpitx.logger = logger
+
+	cleanup := rpitx.HandleSignals(context.Background())
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+	select {
+	case msg := <-logger.messages:
+		assert.Equal(t, "info: received %s, stopping transmission", msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not handled")
+	}
+
+	// Safe to call more than once, and waits for the stop
+	cleanup()
+	cleanup()
+
+	// Nothing executing is not an error
+	assert.Empty(t, logger.messages)
+}
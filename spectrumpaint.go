@@ -12,6 +12,8 @@ import (
 
 const (
 	ModuleNameSPECTRUMPAINT ModuleName = "spectrumpaint"
+
+	spectrumPaintDefaultExcursion = 100000 // Binary default excursion in Hz
 )
 
 type SPECTRUMPAINT struct {
@@ -22,11 +24,13 @@ type SPECTRUMPAINT struct {
 
 	// Frequency specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// Excursion specifies the frequency excursion in Hz. Optional parameter.
-	// Must be positive if specified. Default: 100000 Hz (100 kHz)
-	Excursion *float64 `json:"excursion,omitempty"`
+	// Must be positive if specified, and the painted band (Frequency ±
+	// Excursion/2) must stay within the RPiTX range. Default: 100000 Hz
+	// (100 kHz)
+	Excursion *float64 `json:"excursion,omitempty" schema:"exclusiveMinimum=0"`
 }
 
 func (s *SPECTRUMPAINT) ParseArgs(
@@ -66,19 +70,12 @@ func (s *SPECTRUMPAINT) buildArgs() []string {
 
 // validate validates all SPECTRUMPAINT parameters.
 func (s *SPECTRUMPAINT) validate() error {
-	if err := s.validatePictureFile(); err != nil {
-		return err
-	}
-
-	if err := s.validateFrequency(); err != nil {
-		return err
-	}
-
-	if err := s.validateExcursion(); err != nil {
-		return err
-	}
-
-	return nil
+	return validateFields(
+		fieldCheck{"pictureFile", s.validatePictureFile},
+		fieldCheck{"frequency", s.validateFrequency},
+		fieldCheck{"excursion", s.validateExcursion},
+		fieldCheck{"frequency", s.validateExcursionRange},
+	)
 }
 
 // validatePictureFile validates the picture file parameter.
@@ -98,6 +95,11 @@ func (s *SPECTRUMPAINT) validatePictureFile() error {
 	return nil
 }
 
+// frequencyHz returns the carrier frequency in Hz.
+func (s *SPECTRUMPAINT) frequencyHz() float64 {
+	return s.Frequency
+}
+
 // validateFrequency validates the frequency parameter.
 func (s *SPECTRUMPAINT) validateFrequency() error {
 	if s.Frequency <= 0 {
@@ -113,7 +115,7 @@ func (s *SPECTRUMPAINT) validateFrequency() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), s.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), s.Frequency,
 		)
 	}
 
@@ -132,3 +134,35 @@ func (s *SPECTRUMPAINT) validateExcursion() error {
 
 	return nil
 }
+
+// validateExcursionRange checks that the painted band, Frequency ±
+// Excursion/2 with the default excursion when unset, stays within the
+// RPiTX range so it can't go below 0 Hz or out of the hardware range.
+func (s *SPECTRUMPAINT) validateExcursionRange() error {
+	// An invalid carrier or excursion is reported on its own
+	if s.validateFrequency() != nil || s.validateExcursion() != nil {
+		return nil
+	}
+
+	excursion := float64(spectrumPaintDefaultExcursion)
+	if s.Excursion != nil {
+		excursion = *s.Excursion
+	}
+
+	// The excursion is split evenly around the carrier
+	half := excursion / 2
+	low := s.Frequency - half
+	high := s.Frequency + half
+
+	if !isValidFreqHz(low) || !isValidFreqHz(high) {
+		return ctxerrors.Wrapf(
+			ErrFreqOutOfRange,
+			"(%d kHz to %.0f MHz), painted band %f to %f Hz with excursion "+
+				"%f Hz around %f Hz",
+			minFreqKHz, MaxFrequencyMHz(), low, high,
+			excursion, s.Frequency,
+		)
+	}
+
+	return nil
+}
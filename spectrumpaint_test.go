@@ -480,3 +480,57 @@ func TestSPECTRUMPAINT_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSPECTRUMPAINT_ValidateExcursionRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency float64
+		excursion *float64
+		expectErr bool
+	}{
+		{
+			name:      "default excursion well within range",
+			frequency: 434000000.0,
+		},
+		{
+			name:      "band touching the lower limit",
+			frequency: 55000.0,
+			excursion: floatPtr(100000.0),
+		},
+		{
+			name:      "default excursion below the lower limit",
+			frequency: 50000.0,
+			expectErr: true,
+		},
+		{
+			name:      "excursion below 0 Hz",
+			frequency: 1000000.0,
+			excursion: floatPtr(4000000.0),
+			expectErr: true,
+		},
+		{
+			name:      "excursion above the upper limit",
+			frequency: 1499990000.0,
+			excursion: floatPtr(100000.0),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spectrum := SPECTRUMPAINT{
+				Frequency: tt.frequency,
+				Excursion: tt.excursion,
+			}
+
+			err := spectrum.validateExcursionRange()
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrFreqOutOfRange)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	ModuleNameSSB ModuleName = "ssb"
+)
+
+type SSB struct {
+	// Frequency specifies the carrier frequency in Hz. Required parameter.
+	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
+
+	// Audio specifies the path to the WAV file to transmit. Required
+	// parameter. Any sample rate and channel count is converted to the
+	// 48 kHz mono audio the modulation runs on.
+	Audio string `json:"audio"`
+
+	// Mode specifies the sideband, "USB" or "LSB". Required parameter.
+	Mode ModulationType `json:"mode" schema:"enum=USB|LSB"`
+}
+
+func (m *SSB) ParseArgs(args json.RawMessage) ([]string, io.Reader, error) {
+	if err := json.Unmarshal(args, m); err != nil {
+		return nil, nil, ctxerrors.Wrap(err, "failed to unmarshal args")
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return m.buildArgs(), nil, nil
+}
+
+// buildArgs converts the struct fields into command-line arguments for the
+// SSB script.
+func (m *SSB) buildArgs() []string {
+	return []string{
+		strconv.FormatFloat(m.Frequency, 'f', 0, 64),
+		m.Audio,
+		m.Mode,
+	}
+}
+
+// validate validates all SSB parameters.
+func (m *SSB) validate() error {
+	return validateFields(
+		fieldCheck{"frequency", m.validateFrequency},
+		fieldCheck{"audio", m.validateAudio},
+		fieldCheck{"mode", m.validateMode},
+	)
+}
+
+// frequencyHz returns the carrier frequency in Hz.
+func (m *SSB) frequencyHz() float64 {
+	return m.Frequency
+}
+
+// validateFrequency validates the frequency parameter.
+func (m *SSB) validateFrequency() error {
+	if m.Frequency <= 0 {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"frequency must be positive, got: %f",
+			m.Frequency,
+		)
+	}
+
+	// Validate frequency range using Hz-based validation
+	if !isValidFreqHz(m.Frequency) {
+		return ctxerrors.Wrapf(
+			ErrFreqOutOfRange,
+			"(%d kHz to %.0f MHz), got: %f Hz",
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
+		)
+	}
+
+	return nil
+}
+
+// validateAudio validates the audio parameter.
+func (m *SSB) validateAudio() error {
+	if strings.TrimSpace(m.Audio) == "" {
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "audio")
+	}
+
+	return checkWAVFile(m.Audio)
+}
+
+// validateMode validates the mode parameter.
+func (m *SSB) validateMode() error {
+	switch m.Mode {
+	case "":
+		return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "mode")
+	case ModulationUSB, ModulationLSB:
+		return nil
+	default:
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"mode must be '%s' or '%s', got: %s",
+			ModulationUSB, ModulationLSB, m.Mode,
+		)
+	}
+}
+
+// checkWAVFile checks the file exists and starts with a WAV header.
+func checkWAVFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ctxerrors.Wrapf(commonerrors.ErrFileNotFound, "file: %s", path)
+		}
+
+		return ctxerrors.Wrapf(err, "failed to open audio file: %s", path)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	header := make([]byte, wavHeaderSize)
+	if _, err := io.ReadFull(file, header); err != nil ||
+		string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return ctxerrors.Wrapf(
+			commonerrors.ErrInvalidValue,
+			"audio must be a WAV file: %s",
+			path,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSB_ParseArgs(t *testing.T) {
+	notWAV := filepath.Join(t.TempDir(), "voice.raw")
+	require.NoError(t, os.WriteFile(notWAV, make([]byte, 64), 0o600))
+
+	tests := []struct {
+		name        string
+		input       map[string]any
+		expectedErr error
+		expectArgs  []string
+	}{
+		{
+			name: "usb",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     ".fixtures/test.wav",
+				"mode":      "USB",
+			},
+			expectArgs: []string{"14200000", ".fixtures/test.wav", "USB"},
+		},
+		{
+			name: "lsb",
+			input: map[string]any{
+				"frequency": 7100000.0,
+				"audio":     ".fixtures/test.wav",
+				"mode":      "LSB",
+			},
+			expectArgs: []string{"7100000", ".fixtures/test.wav", "LSB"},
+		},
+		{
+			name: "missing mode",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     ".fixtures/test.wav",
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "other modulation",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     ".fixtures/test.wav",
+				"mode":      "AM",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "lowercase mode",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     ".fixtures/test.wav",
+				"mode":      "usb",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "missing audio",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"mode":      "USB",
+			},
+			expectedErr: commonerrors.ErrRequiredFieldNotSet,
+		},
+		{
+			name: "audio not found",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     "/nonexistent/voice.wav",
+				"mode":      "USB",
+			},
+			expectedErr: commonerrors.ErrFileNotFound,
+		},
+		{
+			name: "audio not a WAV file",
+			input: map[string]any{
+				"frequency": 14200000.0,
+				"audio":     notWAV,
+				"mode":      "USB",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "missing frequency",
+			input: map[string]any{
+				"audio": ".fixtures/test.wav",
+				"mode":  "USB",
+			},
+			expectedErr: commonerrors.ErrInvalidValue,
+		},
+		{
+			name: "frequency too high",
+			input: map[string]any{
+				"frequency": 2000000000.0,
+				"audio":     ".fixtures/test.wav",
+				"mode":      "USB",
+			},
+			expectedErr: ErrFreqOutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ssb := &SSB{}
+			inputBytes, err := json.Marshal(tt.input)
+			require.NoError(t, err)
+
+			args, stdin, err := ssb.ParseArgs(inputBytes)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Nil(t, stdin)
+			assert.Equal(t, tt.expectArgs, args)
+		})
+	}
+}
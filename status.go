@@ -0,0 +1,50 @@
+package gorpitx
+
+import "time"
+
+// ExecutionStatus describes what the RPITX instance is currently running.
+type ExecutionStatus struct {
+	// Executing is true while a module process is running.
+	Executing bool `json:"executing"`
+
+	// Module is the name of the running module.
+	Module ModuleName `json:"module,omitempty"`
+
+	// StartedAt is the time the module process was started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Elapsed is the time the module process has been running for.
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Paused is true while the module process is suspended with Pause.
+	Paused bool `json:"paused"`
+
+	// DevMode is true when the execution is a dev-mode mock instead of a real
+	// transmission, including dry runs.
+	DevMode bool `json:"devMode"`
+}
+
+// Status returns the current execution status. A zero-value status is
+// returned when nothing is running.
+func (r *RPITX) Status() ExecutionStatus {
+	if !r.isExecuting.Load() {
+		return ExecutionStatus{}
+	}
+
+	r.processMu.RLock()
+	defer r.processMu.RUnlock()
+
+	// The execution slot is taken but the process hasn't been started yet
+	if r.process == nil {
+		return ExecutionStatus{}
+	}
+
+	return ExecutionStatus{
+		Executing: true,
+		Module:    r.currentModule,
+		StartedAt: r.startedAt,
+		Elapsed:   time.Since(r.startedAt),
+		Paused:    r.isPaused.Load(),
+		DevMode:   r.mockMode(),
+	}
+}
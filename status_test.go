@@ -0,0 +1,40 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_Status_Idle(t *testing.T) {
+	rpitx, _ := newExecutionTestRPITX(t)
+
+	assert.Equal(t, ExecutionStatus{}, rpitx.Status())
+}
+
+func TestRPITX_Status_Executing(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	before := time.Now()
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+
+	status := rpitx.Status()
+	assert.True(t, status.Executing)
+	assert.Equal(t, ModuleNameMORSE, status.Module)
+	assert.True(t, status.DevMode)
+	assert.False(t, status.StartedAt.Before(before))
+	assert.GreaterOrEqual(t, status.Elapsed, 50*time.Millisecond)
+
+	_ = execution.Stop(ctx)
+	_ = execution.Wait()
+
+	assert.Equal(t, ExecutionStatus{}, rpitx.Status())
+}
@@ -0,0 +1,145 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStubbornRPITX returns an RPITX executing a real process that ignores
+// SIGTERM.
+func newStubbornRPITX(t *testing.T) *RPITX {
+	t.Helper()
+
+	cmd := commander.New()
+
+	process, err := cmd.Start(
+		context.Background(),
+		"sh",
+		[]string{"-c", `trap "" TERM; while true; do sleep 0.05; done`},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = process.Kill(context.Background()) })
+
+	rpitx := &RPITX{commander: cmd, process: process}
+	rpitx.isExecuting.Store(true)
+
+	// Give the shell time to install the trap
+	time.Sleep(100 * time.Millisecond)
+
+	return rpitx
+}
+
+func TestRPITX_StopWithTimeout_NotExecuting(t *testing.T) {
+	rpitx := &RPITX{}
+
+	err := rpitx.StopWithTimeout(context.Background(), time.Second)
+	require.ErrorIs(t, err, ErrNotExecuting)
+}
+
+func TestRPITX_StopWithTimeout_GracePeriod(t *testing.T) {
+	rpitx := newStubbornRPITX(t)
+
+	start := time.Now()
+	err := rpitx.StopWithTimeout(context.Background(), 300*time.Millisecond)
+	elapsed := time.Since(start)
+
+	// The process ignores SIGTERM so it gets killed once the grace period
+	// is over, which still counts as a clean stop
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 300*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestRPITX_StopWithTimeout_Immediate(t *testing.T) {
+	rpitx := newStubbornRPITX(t)
+
+	start := time.Now()
+	err := rpitx.StopWithTimeout(context.Background(), 0)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Less(t, elapsed, 300*time.Millisecond)
+}
+
+func TestRPITX_Kill(t *testing.T) {
+	rpitx := newStubbornRPITX(t)
+
+	start := time.Now()
+	require.NoError(t, rpitx.Kill(context.Background()))
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+
+	require.ErrorIs(t, (&RPITX{}).Kill(context.Background()), ErrNotExecuting)
+}
+
+func TestRPITX_Kill_Execution(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), ModuleNameMORSE, args, 0,
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, rpitx.Kill(context.Background()))
+
+	select {
+	case <-execution.Done():
+	case <-time.After(time.Second):
+		t.Fatal("execution did not finish after kill")
+	}
+
+	assert.False(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_Exec_NoTimeoutRunsUntilStop(t *testing.T) {
+	rpitx, _ := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	beacon := []byte(
+		`{"frequency": 14070000, "rate": 12, "message": "VVV BEACON"}`,
+	)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- rpitx.Exec(ctx, ModuleNameMORSE, beacon, NoTimeout)
+	}()
+
+	// No deadline ends the beacon on its own
+	select {
+	case err := <-errCh:
+		t.Fatalf("beacon ended without Stop: %v", err)
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	assert.True(t, rpitx.Status().Executing)
+
+	// Stop succeeds while Exec reports how the process ended, which is a
+	// termination here
+	require.NoError(t, rpitx.Stop(ctx))
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, commonerrors.ErrTerminated)
+	case <-time.After(5 * time.Second):
+		t.Fatal("beacon did not end after Stop")
+	}
+}
+
+func TestRPITX_Exec_NegativeTimeout(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	err := rpitx.Exec(ctx, ModuleNameMORSE, args, -time.Second)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	_, _, err = rpitx.ExecOutput(ctx, ModuleNameMORSE, args, -time.Second)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
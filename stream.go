@@ -0,0 +1,257 @@
+package gorpitx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/psyb0t/commander"
+)
+
+const (
+	// defaultStreamBuffer is how many lines of each stream gorpitx buffers
+	// for a consumer by default, see WithStreamBuffer.
+	defaultStreamBuffer = 100
+
+	// streamInternalBuffer is the buffer size of the channels gorpitx
+	// registers with the process, which are read right away.
+	streamInternalBuffer = 16
+
+	// StderrLinePrefix tags stderr lines in the combined output stream.
+	StderrLinePrefix = "[stderr] "
+)
+
+// StreamOutputsCtx forwards the output of the running process to the given
+// channels until the output ends or ctx is cancelled, and blocks until then.
+// The channels are closed when the output ends but left open when ctx is
+// cancelled or nothing is executing, so the caller keeps ownership. Pass nil
+// for channels you don't want to listen to.
+func (r *RPITX) StreamOutputsCtx(
+	ctx context.Context,
+	stdout, stderr chan<- string,
+) {
+	if !r.isExecuting.Load() {
+		r.log().Warnf("not executing: %v", ErrNotExecuting)
+
+		return
+	}
+
+	r.processMu.RLock()
+	process := r.process
+	r.processMu.RUnlock()
+
+	if process == nil {
+		r.log().Warnf("no process to stream")
+
+		return
+	}
+
+	r.forwardStream(ctx, process, stdout, stderr)
+}
+
+// StreamCombined sends the stdout and stderr lines of the running process to
+// a single channel, which is closed when the output ends. Stderr lines are
+// prefixed with StderrLinePrefix. Lines are interleaved in the order they
+// are received, which is best-effort: each line is delivered whole, but a
+// stdout and a stderr line printed at nearly the same time may swap places.
+// The channel is left open when nothing is executing.
+func (r *RPITX) StreamCombined(out chan<- string) {
+	if !r.isExecuting.Load() {
+		r.log().Warnf("not executing: %v", ErrNotExecuting)
+
+		return
+	}
+
+	r.processMu.RLock()
+	process := r.process
+	r.processMu.RUnlock()
+
+	if process == nil {
+		r.log().Warnf("no process to stream")
+
+		return
+	}
+
+	// Unbuffered, the lines queue in the relays while out is busy
+	stdout := make(chan string)
+	stderr := make(chan string)
+	r.forwardStreamAsync(context.Background(), process, stdout, stderr)
+
+	go combineStreams(stdout, stderr, out)
+}
+
+// combineStreams multiplexes both streams into out and closes it once both
+// are closed.
+func combineStreams(stdout, stderr <-chan string, out chan<- string) {
+	defer close(out)
+
+	for stdout != nil || stderr != nil {
+		select {
+		case line, ok := <-stdout:
+			if !ok {
+				stdout = nil
+
+				continue
+			}
+
+			out <- line
+		case line, ok := <-stderr:
+			if !ok {
+				stderr = nil
+
+				continue
+			}
+
+			out <- StderrLinePrefix + line
+		}
+	}
+}
+
+// streamBufferSize returns how many lines of each stream are buffered for
+// a consumer.
+func (r *RPITX) streamBufferSize() int {
+	if r.streamBuffer <= 0 {
+		return defaultStreamBuffer
+	}
+
+	return r.streamBuffer
+}
+
+// forwardStream forwards the output of the process to the user channels
+// until the output ends or ctx is done, and blocks until then.
+func (r *RPITX) forwardStream(
+	ctx context.Context,
+	process commander.Process,
+	stdout, stderr chan<- string,
+) {
+	r.forwardStreamAsync(ctx, process, stdout, stderr).Wait()
+}
+
+// forwardStreamAsync is forwardStream in the background, with the internal
+// channels registered before it returns so no line printed after the call
+// is missed. The returned WaitGroup is done once forwarding ends.
+func (r *RPITX) forwardStreamAsync(
+	ctx context.Context,
+	process commander.Process,
+	stdout, stderr chan<- string,
+) *sync.WaitGroup {
+	stream, wg := r.startRelays(ctx, stdout, stderr)
+	process.Stream(stream.stdout, stream.stderr)
+
+	return wg
+}
+
+// startRelays starts relaying the lines sent to the returned stream to the
+// user channels that are set. The WaitGroup is done once relaying ends.
+func (r *RPITX) startRelays(
+	ctx context.Context,
+	stdout, stderr chan<- string,
+) (hubStream, *sync.WaitGroup) {
+	var (
+		stream hubStream
+		wg     sync.WaitGroup
+	)
+
+	relay := func(name string, out chan<- string) chan<- string {
+		if out == nil {
+			return nil
+		}
+
+		in := make(chan string, streamInternalBuffer)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r.relayLines(ctx, name, in, out)
+		}()
+
+		return in
+	}
+
+	stream.stdout = relay("stdout", stdout)
+	stream.stderr = relay("stderr", stderr)
+
+	return stream, &wg
+}
+
+// relayLines forwards the lines of in to out until in is closed, then
+// closes out, or until ctx is done. in is always read right away so the
+// process never gives up on it: lines wait in a queue of up to
+// streamBufferSize while out is busy, and the oldest are dropped once it is
+// full, so a slow consumer misses lines instead of the rest of the stream.
+func (r *RPITX) relayLines(
+	ctx context.Context,
+	name string,
+	in <-chan string,
+	out chan<- string,
+) {
+	limit := r.streamBufferSize()
+
+	var (
+		queue   []string
+		dropped int
+	)
+
+	for in != nil || len(queue) > 0 {
+		var (
+			send chan<- string
+			next string
+		)
+
+		if len(queue) > 0 {
+			send = out
+			next = queue[0]
+		}
+
+		select {
+		case <-ctx.Done():
+			// Keep draining so the process never blocks on our channel
+			go drainLines(in)
+
+			return
+		case line, ok := <-in:
+			if !ok {
+				in = nil
+
+				continue
+			}
+
+			if len(queue) == limit {
+				if dropped == 0 {
+					r.log().Warnf(
+						"%s consumer is %d lines behind, dropping the oldest lines",
+						name, limit,
+					)
+				}
+
+				queue = queue[1:]
+				dropped++
+			}
+
+			queue = append(queue, line)
+		case send <- next:
+			queue = queue[1:]
+
+			if len(queue) == 0 && dropped > 0 {
+				r.log().Warnf(
+					"%s consumer caught up, %d lines were dropped", name, dropped,
+				)
+
+				dropped = 0
+			}
+		}
+	}
+
+	close(out)
+}
+
+// drainLines discards lines until the channel is closed.
+func drainLines(in <-chan string) {
+	if in == nil {
+		return
+	}
+
+	for range in {
+	}
+}
@@ -0,0 +1,289 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/commander"
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockStreamingRPITX returns an RPITX marked as executing a mock process
+// that outputs the given lines.
+func newMockStreamingRPITX(t *testing.T, lines string) *RPITX {
+	t.Helper()
+
+	mockCommander := commander.NewMock()
+	mockCommander.Expect("mock").ReturnOutput([]byte(lines))
+
+	process, err := mockCommander.Start(context.Background(), "mock", nil)
+	require.NoError(t, err)
+
+	rpitx := &RPITX{commander: mockCommander, process: process}
+	rpitx.isExecuting.Store(true)
+
+	return rpitx
+}
+
+func TestRPITX_StreamOutputsCtx_NotExecuting(t *testing.T) {
+	rpitx := &RPITX{commander: commander.NewMock()}
+
+	stdout := make(chan string, 1)
+	stderr := make(chan string, 1)
+
+	rpitx.StreamOutputsCtx(context.Background(), stdout, stderr)
+
+	// Channels must be left open
+	stdout <- "test"
+	stderr <- "test"
+
+	close(stdout)
+	close(stderr)
+}
+
+func TestRPITX_StreamOutputsCtx_OutputEnds(t *testing.T) {
+	rpitx := newMockStreamingRPITX(t, "line 1\nline 2\n")
+
+	stdout := make(chan string, 10)
+	stderr := make(chan string, 10)
+
+	rpitx.StreamOutputsCtx(context.Background(), stdout, stderr)
+
+	lines := []string{}
+	for line := range stdout {
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, []string{"line 1", "line 2"}, lines)
+
+	_, ok := <-stderr
+	assert.False(t, ok, "stderr should be closed when the output ends")
+}
+
+func TestRPITX_StreamOutputsCtx_Cancel(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), ModuleNameMORSE, args, 0,
+	)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = execution.Stop(context.Background())
+		_ = execution.Wait()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stdout := make(chan string, 10)
+	returned := make(chan struct{})
+
+	go func() {
+		rpitx.StreamOutputsCtx(ctx, stdout, nil)
+		close(returned)
+	}()
+
+	select {
+	case line := <-stdout:
+		assert.Contains(t, line, "mocking execution of morse")
+	case <-time.After(3 * time.Second):
+		t.Fatal("no output received")
+	}
+
+	cancel()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("StreamOutputsCtx did not return after cancel")
+	}
+
+	// The channel stays open for the caller
+	select {
+	case stdout <- "still open":
+	default:
+		t.Fatal("stdout should still accept sends")
+	}
+
+	assert.True(t, rpitx.isExecuting.Load())
+}
+
+func TestRPITX_StreamCombined(t *testing.T) {
+	rpitx := newMockStreamingRPITX(t, "line 1\nline 2\n")
+
+	out := make(chan string, 10)
+	rpitx.StreamCombined(out)
+
+	lines := []string{}
+	for line := range out {
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, []string{"line 1", "line 2"}, lines)
+}
+
+func TestRPITX_StreamCombined_NotExecuting(t *testing.T) {
+	rpitx := &RPITX{commander: commander.NewMock()}
+
+	out := make(chan string, 1)
+	rpitx.StreamCombined(out)
+
+	// The channel must be left open
+	out <- "test"
+
+	close(out)
+}
+
+func TestCombineStreams(t *testing.T) {
+	stdout := make(chan string)
+	stderr := make(chan string)
+	out := make(chan string, 10)
+
+	go combineStreams(stdout, stderr, out)
+
+	stdout <- "out 1"
+	stderr <- "err 1"
+	stdout <- "out 2"
+	close(stdout)
+	stderr <- "err 2"
+	close(stderr)
+
+	lines := []string{}
+	for line := range out {
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, []string{
+		"out 1",
+		StderrLinePrefix + "err 1",
+		"out 2",
+		StderrLinePrefix + "err 2",
+	}, lines)
+}
+
+func TestExecution_Stream_SlowConsumer(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	rpitx := &RPITX{
+		config: Config{Mock: MockConfig{
+			Interval:   time.Millisecond,
+			Iterations: 300,
+		}},
+		commander:    commander.New(),
+		modules:      map[ModuleName]Module{ModuleNameTUNE: &TUNE{}},
+		streamBuffer: 1000,
+	}
+
+	execution, err := rpitx.ExecAsync(
+		context.Background(), ModuleNameTUNE,
+		[]byte(`{"frequency": 434000000}`), 0,
+	)
+	require.NoError(t, err)
+
+	// Unbuffered, and not read for longer than the process waits on a
+	// blocked channel before giving up on it
+	stdout := make(chan string)
+	execution.Stream(stdout, nil)
+	time.Sleep(300 * time.Millisecond)
+
+	received := 0
+	timeout := time.After(10 * time.Second)
+
+	for open := true; open; {
+		select {
+		case _, open = <-stdout:
+			if open {
+				received++
+			}
+		case <-timeout:
+			t.Fatalf("stream not closed, got %d lines", received)
+		}
+	}
+
+	require.NoError(t, execution.Wait())
+	assert.Greater(t, received, defaultStreamBuffer)
+}
+
+func TestWithStreamBuffer(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, defaultStreamBuffer, r.streamBufferSize())
+
+	r, err = New(WithStreamBuffer(500))
+	require.NoError(t, err)
+	assert.Equal(t, 500, r.streamBufferSize())
+
+	_, err = New(WithStreamBuffer(-1))
+	assert.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
+
+func TestRPITX_RelayLines_DropsOldest(t *testing.T) {
+	rpitx := &RPITX{streamBuffer: 3, logger: NopLogger()}
+
+	in := make(chan string)
+	out := make(chan string)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		rpitx.relayLines(context.Background(), "stdout", in, out)
+	}()
+
+	// Nobody reads out yet, the relay keeps reading in anyway
+	for i := range 10 {
+		select {
+		case in <- string(rune('0' + i)):
+		case <-time.After(time.Second):
+			t.Fatalf("relay stopped reading at line %d", i)
+		}
+	}
+
+	close(in)
+
+	var received []string
+	for line := range out {
+		received = append(received, line)
+	}
+
+	assert.Equal(t, []string{"7", "8", "9"}, received)
+	<-done
+}
+
+func TestRPITX_RelayLines_Cancel(t *testing.T) {
+	rpitx := &RPITX{logger: NopLogger()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	out := make(chan string)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		rpitx.relayLines(ctx, "stderr", in, out)
+	}()
+
+	in <- "line"
+
+	cancel()
+	<-done
+
+	// Still drained after the relay is gone, and out is left open
+	select {
+	case in <- "more":
+	case <-time.After(time.Second):
+		t.Fatal("input not drained after cancel")
+	}
+
+	select {
+	case <-out:
+		t.Fatal("out must stay open and empty")
+	default:
+	}
+}
@@ -0,0 +1,73 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// timeoutDefaulter is implemented by modules with a natural runtime worked
+// out from their args, e.g. FT8 waiting for its slot and sending a frame,
+// used as the timeout when the caller doesn't set one.
+type timeoutDefaulter interface {
+	defaultTimeout() time.Duration
+}
+
+// DefaultTimeout returns the configured timeout for executions that don't
+// set one, zero meaning none.
+func (r *RPITX) DefaultTimeout() time.Duration {
+	return r.config.DefaultTimeout
+}
+
+// ModuleDefaultTimeout returns the default timeout of the module before its
+// args are known: its natural runtime if it has one, e.g. enough for one FT8
+// transmission, and DefaultTimeout otherwise.
+func (r *RPITX) ModuleDefaultTimeout(name ModuleName) time.Duration {
+	module, err := r.newModule(name)
+	if err != nil {
+		return r.DefaultTimeout()
+	}
+
+	return r.moduleTimeout(module)
+}
+
+// ArgsDefaultTimeout returns the timeout ExecDefault uses for the args: the
+// natural runtime of the module parsed from them, e.g. one slot cycle more
+// per message of an FT8 sequence, and ModuleDefaultTimeout when they don't
+// parse. Like ValidateArgs, it is safe to call while a module is executing.
+func (r *RPITX) ArgsDefaultTimeout(
+	name ModuleName,
+	args json.RawMessage,
+) time.Duration {
+	module, err := r.newModule(name)
+	if err != nil {
+		return r.DefaultTimeout()
+	}
+
+	if _, _, err := r.parseModuleArgs(module, args); err != nil {
+		return r.ModuleDefaultTimeout(name)
+	}
+
+	return r.moduleTimeout(module)
+}
+
+// moduleTimeout returns the natural runtime of the module if it has one and
+// DefaultTimeout otherwise.
+func (r *RPITX) moduleTimeout(module Module) time.Duration {
+	if defaulter, ok := module.(timeoutDefaulter); ok {
+		return defaulter.defaultTimeout()
+	}
+
+	return r.DefaultTimeout()
+}
+
+// ExecDefault runs the module like Exec with its ArgsDefaultTimeout. Use
+// Exec to set the timeout explicitly, e.g. for a repeating FT8 beacon.
+func (r *RPITX) ExecDefault(
+	ctx context.Context,
+	name ModuleName,
+	args []byte,
+	opts ...ExecOption,
+) error {
+	return r.Exec(ctx, name, args, r.ArgsDefaultTimeout(name, args), opts...)
+}
@@ -0,0 +1,164 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFT8_DefaultTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		expected time.Duration
+	}{
+		{
+			name:     "single message",
+			expected: 45 * time.Second,
+		},
+		{
+			name:     "sequence",
+			messages: []string{"CQ CA0ALL JN06", "CA0ALL CA0BOB -10", "RR73"},
+			expected: 105 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := &FT8{Messages: tt.messages}
+			timeout := module.defaultTimeout()
+
+			assert.Equal(t, tt.expected, timeout)
+
+			// Ends on a slot boundary relative to the start
+			assert.Zero(t, timeout%ft8SlotDuration)
+
+			// Covers the longest wait for an even or odd slot plus every
+			// transmission, including the estimated one
+			estimate, ok := module.estimatedDuration()
+			require.True(t, ok)
+			assert.GreaterOrEqual(t, timeout, 2*ft8SlotDuration+estimate)
+		})
+	}
+}
+
+func TestFT8_DefaultTimeout_Repeat(t *testing.T) {
+	module := &FT8{Repeat: boolPtr(true)}
+	assert.Equal(t, NoTimeout, module.defaultTimeout())
+
+	rpitx := &RPITX{
+		config:  Config{DefaultTimeout: time.Minute},
+		modules: defaultModules(),
+	}
+
+	// Runs until stopped instead of being cut off after one cycle
+	timeout := rpitx.ArgsDefaultTimeout(ModuleNameFT8, []byte(
+		`{"frequency": 14074000, "message": "CQ CA0ALL JN06", "repeat": true}`,
+	))
+	assert.Equal(t, NoTimeout, timeout)
+}
+
+func TestRPITX_ModuleDefaultTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		module   ModuleName
+		config   time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "ft8 natural runtime",
+			module:   ModuleNameFT8,
+			config:   time.Minute,
+			expected: 45 * time.Second,
+		},
+		{
+			name:     "config fallback",
+			module:   ModuleNameMORSE,
+			config:   time.Minute,
+			expected: time.Minute,
+		},
+		{
+			name:     "no timeout fallback",
+			module:   ModuleNameTUNE,
+			expected: NoTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{
+				config:  Config{DefaultTimeout: tt.config},
+				modules: defaultModules(),
+			}
+			assert.Equal(t, tt.expected, rpitx.ModuleDefaultTimeout(tt.module))
+		})
+	}
+}
+
+func TestRPITX_ArgsDefaultTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		module   ModuleName
+		args     string
+		expected time.Duration
+	}{
+		{
+			name:     "ft8 single message",
+			module:   ModuleNameFT8,
+			args:     `{"frequency": 14074000, "message": "CQ CA0ALL JN06"}`,
+			expected: 45 * time.Second,
+		},
+		{
+			name:   "ft8 sequence",
+			module: ModuleNameFT8,
+			args: `{"frequency": 14074000, "autoSlot": true, ` +
+				`"messages": ["CQ CA0ALL JN06", "RR73"]}`,
+			expected: 75 * time.Second,
+		},
+		{
+			name:     "ft8 invalid args",
+			module:   ModuleNameFT8,
+			args:     `{"frequency": 14074000}`,
+			expected: 45 * time.Second,
+		},
+		{
+			name:     "config fallback",
+			module:   ModuleNameTUNE,
+			args:     `{"frequency": 434000000}`,
+			expected: time.Minute,
+		},
+		{
+			name:     "unknown module",
+			module:   "nope",
+			args:     `{}`,
+			expected: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpitx := &RPITX{
+				config:  Config{DefaultTimeout: time.Minute},
+				modules: defaultModules(),
+			}
+
+			assert.Equal(t, tt.expected, rpitx.ArgsDefaultTimeout(
+				tt.module,
+				[]byte(tt.args),
+			))
+		})
+	}
+}
+
+func TestRPITX_ExecDefault(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	rpitx.config.DefaultTimeout = 200 * time.Millisecond
+
+	err := rpitx.ExecDefault(context.Background(), ModuleNameMORSE, args)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+	assert.False(t, rpitx.isExecuting.Load())
+}
@@ -0,0 +1,209 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+const (
+	// maxTransmissionRecords is how many records TransmissionLog keeps.
+	maxTransmissionRecords = 100
+
+	// transmissionLogFileMode is the mode the log file is created with.
+	transmissionLogFileMode = 0o644
+)
+
+// TransmissionStatus is how a logged transmission ended.
+type TransmissionStatus string
+
+const (
+	// TransmissionStatusCompleted is a transmission whose module exited
+	// successfully.
+	TransmissionStatusCompleted TransmissionStatus = "completed"
+	// TransmissionStatusStopped is a transmission ended with Stop, Kill,
+	// Execution.Stop or by cancelling the context of the execution.
+	TransmissionStatusStopped TransmissionStatus = "stopped"
+	// TransmissionStatusTimeout is a transmission stopped once its timeout
+	// or the max transmit duration elapsed.
+	TransmissionStatusTimeout TransmissionStatus = "timeout"
+	// TransmissionStatusFailed is a transmission whose module didn't exit
+	// successfully.
+	TransmissionStatusFailed TransmissionStatus = "failed"
+)
+
+// TransmissionRecord is an entry of the transmission log.
+type TransmissionRecord struct {
+	// Module is the name of the module that transmitted.
+	Module ModuleName `json:"module"`
+
+	// FrequencyHz is the carrier frequency the module transmitted on. It is
+	// zero for modules without a single carrier frequency.
+	FrequencyHz float64 `json:"frequencyHz,omitempty"`
+
+	// StartedAt is the time the module process was started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// EndedAt is the time the transmission ended.
+	EndedAt time.Time `json:"endedAt"`
+
+	// Status is how the transmission ended.
+	Status TransmissionStatus `json:"status"`
+
+	// Error is the error the execution ended with, empty on success.
+	Error string `json:"error,omitempty"`
+
+	// DevMode is true when nothing was actually transmitted because the
+	// execution was a dev-mode mock or a dry run.
+	DevMode bool `json:"devMode"`
+}
+
+// transmissionLog holds the latest transmission records.
+type transmissionLog struct {
+	mu      sync.Mutex
+	records []TransmissionRecord
+	file    string
+}
+
+// TransmissionLog returns the latest transmissions, oldest first, up to the
+// last 100. Every execution whose module process was started is logged once
+// it ends, executions failing before that are not. Use
+// WithTransmissionLogFile to keep a complete log.
+func (r *RPITX) TransmissionLog() []TransmissionRecord {
+	r.transmissions.mu.Lock()
+	defer r.transmissions.mu.Unlock()
+
+	return slices.Clone(r.transmissions.records)
+}
+
+// startTransmissionRecord returns the record of the execution of the module
+// whose process just started.
+func (r *RPITX) startTransmissionRecord(
+	name ModuleName,
+	module Module,
+) TransmissionRecord {
+	record := TransmissionRecord{
+		Module:    name,
+		StartedAt: time.Now(),
+		DevMode:   r.mockMode(),
+	}
+
+	// The module instance holds the args of this execution
+	if reporter, ok := module.(frequencyReporter); ok {
+		record.FrequencyHz = reporter.frequencyHz()
+	}
+
+	return record
+}
+
+// logTransmission completes the record with how the execution ended and
+// adds it to the log. A failure to append to the log file is logged without
+// failing the execution.
+func (r *RPITX) logTransmission(
+	record TransmissionRecord,
+	err error,
+	stopped bool,
+) {
+	record.EndedAt = time.Now()
+	record.Status = transmissionStatus(err, stopped)
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	log := &r.transmissions
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.records = append(log.records, record)
+	if len(log.records) > maxTransmissionRecords {
+		log.records = slices.Delete(
+			log.records, 0, len(log.records)-maxTransmissionRecords,
+		)
+	}
+
+	if log.file == "" {
+		return
+	}
+
+	if err := appendTransmissionRecord(log.file, record); err != nil {
+		r.log().Errorf("failed to write transmission log: %v", err)
+	}
+}
+
+// transmissionStatus maps the error the execution ended with to its status.
+// stopped tells whether a stop was requested.
+func transmissionStatus(err error, stopped bool) TransmissionStatus {
+	switch {
+	case errors.Is(err, commonerrors.ErrTimeout),
+		errors.Is(err, ErrMaxDurationExceeded):
+		return TransmissionStatusTimeout
+	case stopped, errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return TransmissionStatusStopped
+	case err != nil:
+		return TransmissionStatusFailed
+	default:
+		return TransmissionStatusCompleted
+	}
+}
+
+// checkTransmissionLogFile makes sure the log file can be appended to,
+// creating it if needed.
+func checkTransmissionLogFile(path string) error {
+	file, err := os.OpenFile(
+		path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, transmissionLogFileMode,
+	)
+	if err != nil {
+		return ctxerrors.Wrapf(
+			err, "failed to open transmission log file %s", path,
+		)
+	}
+
+	if err := file.Close(); err != nil {
+		return ctxerrors.Wrapf(
+			err, "failed to close transmission log file %s", path,
+		)
+	}
+
+	return nil
+}
+
+// appendTransmissionRecord appends the record to the log file as a JSON
+// line.
+func appendTransmissionRecord(path string, record TransmissionRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return ctxerrors.Wrap(err, "failed to marshal transmission record")
+	}
+
+	file, err := os.OpenFile(
+		path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, transmissionLogFileMode,
+	)
+	if err != nil {
+		return ctxerrors.Wrapf(
+			err, "failed to open transmission log file %s", path,
+		)
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return ctxerrors.Wrapf(
+			err, "failed to append to transmission log file %s", path,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package gorpitx
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransmissionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		stopped  bool
+		expected TransmissionStatus
+	}{
+		{
+			name:     "completed",
+			expected: TransmissionStatusCompleted,
+		},
+		{
+			name:     "stopped",
+			err:      commonerrors.ErrTerminated,
+			stopped:  true,
+			expected: TransmissionStatusStopped,
+		},
+		{
+			name:     "stopped after exiting cleanly",
+			stopped:  true,
+			expected: TransmissionStatusStopped,
+		},
+		{
+			name:     "timeout",
+			err:      commonerrors.ErrTimeout,
+			expected: TransmissionStatusTimeout,
+		},
+		{
+			name:     "max duration exceeded",
+			err:      ctxerrors.Wrap(ErrMaxDurationExceeded, "morse"),
+			expected: TransmissionStatusTimeout,
+		},
+		{
+			name:     "context cancelled",
+			err:      ctxerrors.Wrap(context.Canceled, "execution cancelled"),
+			expected: TransmissionStatusStopped,
+		},
+		{
+			name:     "failed",
+			err:      &ExecError{Module: ModuleNameMORSE, ExitCode: 1},
+			expected: TransmissionStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, transmissionStatus(tt.err, tt.stopped))
+		})
+	}
+}
+
+func TestRPITX_TransmissionLog(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	ctx := context.Background()
+
+	logFile := filepath.Join(t.TempDir(), "transmissions.jsonl")
+	rpitx.transmissions.file = logFile
+
+	assert.Empty(t, rpitx.TransmissionLog())
+
+	execution, err := rpitx.ExecAsync(ctx, ModuleNameMORSE, args, 0)
+	require.NoError(t, err)
+	require.NoError(t, execution.Stop(ctx))
+	<-execution.Done()
+
+	err = rpitx.Exec(ctx, ModuleNameMORSE, args, 200*time.Millisecond)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	// Failures before the process starts aren't transmissions
+	err = rpitx.Exec(ctx, ModuleNameTUNE, args, time.Second)
+	require.ErrorIs(t, err, ErrUnknownModule)
+
+	records := rpitx.TransmissionLog()
+	require.Len(t, records, 2)
+
+	for _, record := range records {
+		assert.Equal(t, ModuleNameMORSE, record.Module)
+		assert.InDelta(t, 434000000.0, record.FrequencyHz, 0)
+		assert.True(t, record.DevMode)
+		assert.False(t, record.StartedAt.IsZero())
+		assert.False(t, record.EndedAt.Before(record.StartedAt))
+	}
+
+	assert.Equal(t, TransmissionStatusStopped, records[0].Status)
+	assert.NotEmpty(t, records[0].Error)
+	assert.Equal(t, TransmissionStatusTimeout, records[1].Status)
+
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	for i, line := range lines {
+		var record TransmissionRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		assert.Equal(t, records[i].Status, record.Status)
+		assert.True(t, records[i].StartedAt.Equal(record.StartedAt))
+	}
+}
+
+func TestRPITX_TransmissionLog_ExecOutput(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	rpitx.config.Mock.Iterations = 1
+
+	_, _, err := rpitx.ExecOutput(
+		context.Background(), ModuleNameMORSE, args, 5*time.Second,
+	)
+	require.NoError(t, err)
+
+	records := rpitx.TransmissionLog()
+	require.Len(t, records, 1)
+	assert.Equal(t, TransmissionStatusCompleted, records[0].Status)
+	assert.Empty(t, records[0].Error)
+}
+
+func TestRPITX_TransmissionLog_KeepsLatest(t *testing.T) {
+	rpitx := &RPITX{}
+
+	for i := range maxTransmissionRecords + 5 {
+		rpitx.logTransmission(
+			TransmissionRecord{FrequencyHz: float64(i)}, nil, false,
+		)
+	}
+
+	records := rpitx.TransmissionLog()
+	require.Len(t, records, maxTransmissionRecords)
+	assert.InDelta(t, 5.0, records[0].FrequencyHz, 0)
+	assert.InDelta(
+		t,
+		float64(maxTransmissionRecords+4),
+		records[len(records)-1].FrequencyHz,
+		0,
+	)
+}
+
+func TestNew_TransmissionLogFile(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	dir := t.TempDir()
+
+	rpitx, err := New(
+		WithTransmissionLogFile(filepath.Join(dir, "log.jsonl")),
+	)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "log.jsonl"))
+	assert.NotNil(t, rpitx)
+
+	_, err = New(
+		WithTransmissionLogFile(filepath.Join(dir, "missing", "log.jsonl")),
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package gorpitx
+
+import (
+	"errors"
+	"time"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+)
+
+// transmitTimeout returns the timeout the execution runs with: the given one
+// or the max transmit duration when that is shorter or no timeout was set.
+// capped tells whether the max transmit duration applies.
+func (r *RPITX) transmitTimeout(
+	timeout time.Duration,
+) (time.Duration, bool) {
+	limit := r.maxTransmitDuration
+	if limit <= 0 || (timeout > 0 && timeout <= limit) {
+		return timeout, false
+	}
+
+	return limit, true
+}
+
+// transmitLimitError replaces the timeout of an execution cut short by the
+// max transmit duration with ErrMaxDurationExceeded.
+func (r *RPITX) transmitLimitError(
+	name ModuleName,
+	err error,
+	capped bool,
+) error {
+	if !capped || !errors.Is(err, commonerrors.ErrTimeout) {
+		return err
+	}
+
+	r.log().Warnf(
+		"module %s stopped after the max transmit duration of %s",
+		name, r.maxTransmitDuration,
+	)
+
+	return ctxerrors.Wrapf(
+		ErrMaxDurationExceeded,
+		"module %s stopped after %s",
+		name, r.maxTransmitDuration,
+	)
+}
+
+// markTransmitEnded records when the last transmission ended, starting the
+// cooldown.
+func (r *RPITX) markTransmitEnded() {
+	r.transmitEndedAt.Store(time.Now().UnixNano())
+}
+
+// checkCooldown returns ErrCoolingDown while the cooldown after the last
+// transmission hasn't elapsed yet.
+func (r *RPITX) checkCooldown() error {
+	remaining := r.cooldownRemaining()
+	if remaining <= 0 {
+		return nil
+	}
+
+	return ctxerrors.Wrapf(
+		ErrCoolingDown,
+		"next transmission allowed in %s",
+		remaining.Round(time.Millisecond),
+	)
+}
+
+// cooldownRemaining returns how long until the cooldown after the last
+// transmission has elapsed, zero or less once it has.
+func (r *RPITX) cooldownRemaining() time.Duration {
+	endedAt := r.transmitEndedAt.Load()
+	if r.transmitCooldown <= 0 || endedAt == 0 {
+		return 0
+	}
+
+	return r.transmitCooldown - time.Since(time.Unix(0, endedAt))
+}
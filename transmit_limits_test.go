@@ -0,0 +1,159 @@
+package gorpitx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/psyb0t/common-go/env"
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPITX_TransmitTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxDuration     time.Duration
+		timeout         time.Duration
+		expectedTimeout time.Duration
+		expectedCapped  bool
+	}{
+		{
+			name:            "no cap",
+			timeout:         time.Minute,
+			expectedTimeout: time.Minute,
+		},
+		{
+			name:            "no cap and no timeout",
+			expectedTimeout: NoTimeout,
+		},
+		{
+			name:            "timeout within cap",
+			maxDuration:     time.Minute,
+			timeout:         time.Second,
+			expectedTimeout: time.Second,
+		},
+		{
+			name:            "timeout equal to cap",
+			maxDuration:     time.Minute,
+			timeout:         time.Minute,
+			expectedTimeout: time.Minute,
+		},
+		{
+			name:            "timeout over cap",
+			maxDuration:     time.Minute,
+			timeout:         time.Hour,
+			expectedTimeout: time.Minute,
+			expectedCapped:  true,
+		},
+		{
+			name:            "no timeout is capped",
+			maxDuration:     time.Minute,
+			expectedTimeout: time.Minute,
+			expectedCapped:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &RPITX{maxTransmitDuration: tt.maxDuration}
+
+			timeout, capped := r.transmitTimeout(tt.timeout)
+			assert.Equal(t, tt.expectedTimeout, timeout)
+			assert.Equal(t, tt.expectedCapped, capped)
+		})
+	}
+}
+
+func TestRPITX_MaxTransmitDuration(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	rpitx.maxTransmitDuration = 200 * time.Millisecond
+	ctx := context.Background()
+
+	// A caller that never stops the transmission
+	start := time.Now()
+	err := rpitx.Exec(ctx, ModuleNameMORSE, args, NoTimeout)
+	require.ErrorIs(t, err, ErrMaxDurationExceeded)
+	assert.NotErrorIs(t, err, commonerrors.ErrTimeout)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	// The caller's own timeout is still reported as a timeout
+	err = rpitx.Exec(ctx, ModuleNameMORSE, args, 100*time.Millisecond)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	_, _, err = rpitx.ExecOutput(ctx, ModuleNameMORSE, args, NoTimeout)
+	require.ErrorIs(t, err, ErrMaxDurationExceeded)
+}
+
+func TestRPITX_TransmitCooldown(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	rpitx.transmitCooldown = 300 * time.Millisecond
+	ctx := context.Background()
+
+	// No transmission yet, so nothing to cool down from
+	err := rpitx.Exec(ctx, ModuleNameMORSE, args, 50*time.Millisecond)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+
+	_, err = rpitx.ExecAsync(ctx, ModuleNameMORSE, args, NoTimeout)
+	require.ErrorIs(t, err, ErrCoolingDown)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	_, _, err = rpitx.ExecOutput(ctx, ModuleNameMORSE, args, NoTimeout)
+	require.ErrorIs(t, err, ErrCoolingDown)
+	assert.False(t, rpitx.isExecuting.Load())
+
+	time.Sleep(rpitx.cooldownRemaining())
+
+	err = rpitx.Exec(ctx, ModuleNameMORSE, args, 50*time.Millisecond)
+	require.ErrorIs(t, err, commonerrors.ErrTimeout)
+}
+
+func TestRPITX_TransmitCooldown_Queue(t *testing.T) {
+	rpitx, args := newExecutionTestRPITX(t)
+	rpitx.transmitCooldown = 300 * time.Millisecond
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := rpitx.Enqueue(ctx, ModuleNameMORSE, args, 50*time.Millisecond)
+		require.NoError(t, err)
+	}
+
+	// The second job waits for the cooldown rather than failing with it
+	var jobs []Job
+
+	require.Eventually(t, func() bool {
+		jobs = rpitx.Jobs()
+
+		return len(jobs) == 2 && !jobs[1].FinishedAt.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, job := range jobs {
+		require.ErrorIs(t, job.Err, commonerrors.ErrTimeout)
+	}
+
+	assert.GreaterOrEqual(
+		t,
+		jobs[1].FinishedAt.Sub(jobs[0].FinishedAt),
+		rpitx.transmitCooldown,
+	)
+}
+
+func TestNew_TransmitLimits(t *testing.T) {
+	t.Setenv(env.EnvVarName, env.EnvTypeDev)
+
+	r, err := New(
+		WithMaxTransmitDuration(time.Minute),
+		WithTransmitCooldown(10*time.Second),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, r.maxTransmitDuration)
+	assert.Equal(t, 10*time.Second, r.transmitCooldown)
+
+	_, err = New(WithMaxTransmitDuration(-time.Second))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+
+	_, err = New(WithTransmitCooldown(-time.Second))
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}
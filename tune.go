@@ -16,7 +16,7 @@ const (
 type TUNE struct {
 	// `-f` specifies the carrier frequency in Hz. Required parameter.
 	// Range: 50 kHz to 1500 MHz (50000 to 1500000000 Hz)
-	Frequency float64 `json:"frequency"`
+	Frequency float64 `json:"frequency" schema:"freq=hz"`
 
 	// `-e` flag exits immediately without killing the carrier.
 	// Optional parameter, defaults to false.
@@ -64,15 +64,15 @@ func (m *TUNE) buildArgs() []string {
 
 // validate validates all TUNE parameters.
 func (m *TUNE) validate() error {
-	if err := m.validateFreq(); err != nil {
-		return err
-	}
-
-	if err := m.validatePPM(); err != nil {
-		return err
-	}
+	return validateFields(
+		fieldCheck{"frequency", m.validateFreq},
+		fieldCheck{"ppm", m.validatePPM},
+	)
+}
 
-	return nil
+// frequencyHz returns the carrier frequency in Hz.
+func (m *TUNE) frequencyHz() float64 {
+	return m.Frequency
 }
 
 // validateFreq validates the frequency parameter.
@@ -90,7 +90,7 @@ func (m *TUNE) validateFreq() error {
 		return ctxerrors.Wrapf(
 			ErrFreqOutOfRange,
 			"(%d kHz to %.0f MHz), got: %f Hz",
-			minFreqKHz, getMaxFreqMHzDisplay(), m.Frequency,
+			minFreqKHz, MaxFrequencyMHz(), m.Frequency,
 		)
 	}
 
@@ -1,5 +1,7 @@
 package gorpitx
 
+import "github.com/psyb0t/ctxerrors"
+
 const (
 	hzToMhzDivisor    = 1000000.0 // conversion factor from Hz to MHz
 	kHzToMHzDivisor   = 1000.0    // conversion factor from kHz to MHz
@@ -28,30 +30,32 @@ func mHzToHz(mHz float64) float64 {
 	return mHz * hzToMhzDivisor
 }
 
-// getMinFreqHz returns the minimum supported frequency in Hz.
-func getMinFreqHz() float64 {
+// MinFrequencyHz returns the lowest frequency RPiTX can transmit on, in Hz.
+// Frequencies below it are rejected with ErrFreqOutOfRange.
+func MinFrequencyHz() float64 {
 	return float64(minFreqKHz) * khzToHzMultiplier // Convert kHz to Hz
 }
 
-// getMaxFreqHz returns the maximum supported frequency in Hz.
-func getMaxFreqHz() float64 {
+// MaxFrequencyHz returns the highest frequency RPiTX can transmit on, in Hz.
+// Frequencies above it are rejected with ErrFreqOutOfRange.
+func MaxFrequencyHz() float64 {
 	return float64(maxFreqKHz) * khzToHzMultiplier // Convert kHz to Hz
 }
 
 // isValidFreqHz checks if a frequency in Hz is within RPiTX hardware limits.
 func isValidFreqHz(freqHz float64) bool {
-	return freqHz >= getMinFreqHz() && freqHz <= getMaxFreqHz()
+	return freqHz >= MinFrequencyHz() && freqHz <= MaxFrequencyHz()
 }
 
-// getMinFreqMHzDisplay returns the minimum supported frequency in MHz for
-// display purposes.
-func getMinFreqMHzDisplay() float64 {
+// MinFrequencyMHz returns MinFrequencyHz in MHz, e.g. for labels and error
+// messages.
+func MinFrequencyMHz() float64 {
 	return kHzToMHz(float64(minFreqKHz))
 }
 
-// getMaxFreqMHzDisplay returns the maximum supported frequency in MHz for
-// display purposes.
-func getMaxFreqMHzDisplay() float64 {
+// MaxFrequencyMHz returns MaxFrequencyHz in MHz, e.g. for labels and error
+// messages.
+func MaxFrequencyMHz() float64 {
 	return kHzToMHz(float64(maxFreqKHz))
 }
 
@@ -64,3 +68,22 @@ func hasValidFreqPrecision(freqMHz float64) bool {
 
 	return freqMHz == rounded
 }
+
+// validateFreqPrecision checks the frequency precision for modules limited
+// to 0.1 MHz steps, any frequency passes for the other modules.
+func validateFreqPrecision(module Module, freqMHz float64) error {
+	limited, ok := module.(freqStepLimited)
+	if !ok || !limited.limitsFreqStep() {
+		return nil
+	}
+
+	if !hasValidFreqPrecision(freqMHz) {
+		return ctxerrors.Wrapf(
+			ErrFreqPrecision,
+			"(0.1 MHz precision), got: %f",
+			freqMHz,
+		)
+	}
+
+	return nil
+}
@@ -90,29 +90,29 @@ func TestMHzToKHz(t *testing.T) {
 	}
 }
 
-func TestGetMinFreqHz(t *testing.T) {
-	result := getMinFreqHz()
+func TestMinFrequencyHz(t *testing.T) {
+	result := MinFrequencyHz()
 	expected := float64(minFreqKHz * 1000) // Convert kHz to Hz
 	assert.Equal(t, expected, result)
 	assert.Equal(t, 5000.0, result) // 5 kHz = 5000 Hz
 }
 
-func TestGetMaxFreqHz(t *testing.T) {
-	result := getMaxFreqHz()
+func TestMaxFrequencyHz(t *testing.T) {
+	result := MaxFrequencyHz()
 	expected := float64(maxFreqKHz * 1000) // Convert kHz to Hz
 	assert.Equal(t, expected, result)
 	assert.Equal(t, 1500000000.0, result) // 1500000 kHz = 1500000000 Hz (1.5 GHz)
 }
 
-func TestGetMinFreqMHzDisplay(t *testing.T) {
-	result := getMinFreqMHzDisplay()
+func TestMinFrequencyMHz(t *testing.T) {
+	result := MinFrequencyMHz()
 	expected := kHzToMHz(float64(minFreqKHz))
 	assert.Equal(t, expected, result)
 	assert.Equal(t, 0.005, result) // 5 kHz = 0.005 MHz
 }
 
-func TestGetMaxFreqMHzDisplay(t *testing.T) {
-	result := getMaxFreqMHzDisplay()
+func TestMaxFrequencyMHz(t *testing.T) {
+	result := MaxFrequencyMHz()
 	expected := kHzToMHz(float64(maxFreqKHz))
 	assert.Equal(t, expected, result)
 	assert.Equal(t, 1500.0, result) // 1500000 kHz = 1500 MHz
@@ -165,6 +165,33 @@ func TestHasValidFreqPrecision(t *testing.T) {
 	}
 }
 
+func TestValidateFreqPrecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		module      Module
+		freq        float64
+		expectError bool
+	}{
+		{"pifmrds 0.1 MHz step", &PIFMRDS{}, 88.5, false},
+		{"pifmrds finer step", &PIFMRDS{}, 88.55, true},
+		{"tune finer step", &TUNE{}, 88.55, false},
+		{"pocsag finer step", &POCSAG{}, 466.230075, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFreqPrecision(tt.module, tt.freq)
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrFreqPrecision)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestFrequencyConversionRoundTrip(t *testing.T) {
 	// Test that converting kHz -> MHz -> kHz returns original value - math
 	// better fucking work
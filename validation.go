@@ -0,0 +1,127 @@
+package gorpitx
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// errorLocationPattern matches the source location ctxerrors appends to its
+// messages, e.g. " [/src/tune.go:97 in gorpitx.(*TUNE).validateFreq]".
+var errorLocationPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	` \[[^\[\]]+:\d+ in [^\[\]]*\]`,
+)
+
+// ValidationError is a module arg that failed validation, so e.g. a form
+// can show the problem next to the field.
+type ValidationError struct {
+	// Field is the JSON name of the arg, e.g. "frequency", or
+	// "messages[2]" for an item of a list.
+	Field string
+
+	// Message describes the problem without the source locations Err
+	// carries, e.g. "frequency must be positive, got: 0.000000: invalid
+	// value".
+	Message string
+
+	// Err is the underlying error, matching the sentinel it wraps with
+	// errors.Is, e.g. ErrFreqOutOfRange.
+	Err error
+}
+
+// Error returns the field with the underlying error.
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is every invalid arg found while parsing the args of a
+// module, in the order the module checks them. ParseArgs returns it for
+// failed validations so all the bad fields are reported at once, errors.As
+// finds it and errors.Is matches the sentinel of any of them.
+type ValidationErrors []*ValidationError
+
+// Error joins the errors of the fields.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the fields.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// fieldError returns err as the ValidationError of the field, for checks
+// covering more than one field.
+func fieldError(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return newValidationError(field, err)
+}
+
+// newValidationError returns the ValidationError of the field for err.
+func newValidationError(field string, err error) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Message: errorLocationPattern.ReplaceAllString(err.Error(), ""),
+		Err:     err,
+	}
+}
+
+// fieldCheck is a validation of a module arg.
+type fieldCheck struct {
+	field    string
+	validate func() error
+}
+
+// validateFields runs all the checks and returns the errors of the failed
+// ones as ValidationErrors, nil when all pass.
+func validateFields(checks ...fieldCheck) error {
+	var errs ValidationErrors
+
+	for _, check := range checks {
+		errs = append(errs, fieldErrors(check.field, check.validate())...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// fieldErrors returns the errors of the field. Errors already naming their
+// fields, e.g. one per list item, keep those fields.
+func fieldErrors(field string, err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return errs
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ValidationErrors{validationErr}
+	}
+
+	return ValidationErrors{newValidationError(field, err)}
+}
@@ -0,0 +1,141 @@
+package gorpitx
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	commonerrors "github.com/psyb0t/common-go/errors"
+	"github.com/psyb0t/ctxerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationErrors(t *testing.T) {
+	err := validateFields(
+		fieldCheck{"frequency", func() error {
+			return ctxerrors.Wrap(ErrFreqOutOfRange, "got: 1")
+		}},
+		fieldCheck{"rate", func() error { return nil }},
+		fieldCheck{"message", func() error {
+			return ctxerrors.Wrap(commonerrors.ErrRequiredFieldNotSet, "message")
+		}},
+	)
+	require.Error(t, err)
+
+	// Wrapped like ParseArgs errors end up
+	err = ctxerrors.Wrap(err, "failed to parse args")
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, "frequency", errs[0].Field)
+	assert.Equal(t, "got: 1: frequency out of RPiTX range", errs[0].Message)
+	assert.Equal(t, "message", errs[1].Field)
+	assert.Equal(
+		t, "message: required field is not set", errs[1].Message,
+	)
+
+	require.ErrorIs(t, err, ErrFreqOutOfRange)
+	require.ErrorIs(t, err, commonerrors.ErrRequiredFieldNotSet)
+	assert.NotErrorIs(t, err, commonerrors.ErrInvalidValue)
+	assert.Contains(t, err.Error(), "frequency: got: 1")
+
+	var fieldErr *ValidationError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "frequency", fieldErr.Field)
+}
+
+func TestValidateFields_NoErrors(t *testing.T) {
+	err := validateFields(fieldCheck{"frequency", func() error { return nil }})
+	assert.NoError(t, err)
+}
+
+func TestModules_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		module         Module
+		args           map[string]any
+		expectedFields []string
+	}{
+		{
+			name:           "tune",
+			module:         &TUNE{},
+			args:           map[string]any{"frequency": 1, "ppm": -1},
+			expectedFields: []string{"frequency", "ppm"},
+		},
+		{
+			name:   "morse",
+			module: &MORSE{},
+			args: map[string]any{
+				"frequency": 0,
+				"rate":      0,
+			},
+			expectedFields: []string{"frequency", "rate", "message"},
+		},
+		{
+			name:   "pocsag messages",
+			module: &POCSAG{},
+			args: map[string]any{
+				"frequency": 466230000,
+				"messages": []map[string]any{
+					{"address": -1, "message": "A"},
+					{"address": 1, "message": "OK"},
+					{"address": 2, "message": " "},
+				},
+			},
+			expectedFields: []string{"messages[0]", "messages[2]"},
+		},
+		{
+			name:   "spectrumpaint band only checked for a valid carrier",
+			module: &SPECTRUMPAINT{},
+			args: map[string]any{
+				"pictureFile": "/nonexistent.rgb",
+				"frequency":   0,
+			},
+			expectedFields: []string{"pictureFile", "frequency"},
+		},
+		{
+			name:   "pidtmf timing",
+			module: &PIDTMF{},
+			args: map[string]any{
+				"frequency": 434000000,
+				"digits":    "123",
+				"gapMs":     -1,
+			},
+			expectedFields: []string{"gapMs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := json.Marshal(tt.args)
+			require.NoError(t, err)
+
+			_, _, err = tt.module.ParseArgs(args)
+
+			var errs ValidationErrors
+			require.ErrorAs(t, err, &errs)
+
+			fields := make([]string, len(errs))
+			for i, fieldErr := range errs {
+				fields[i] = fieldErr.Field
+			}
+
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	require.NoError(t, fieldError("gapMs", nil))
+
+	err := fieldError("gapMs", commonerrors.ErrInvalidValue)
+
+	var fieldErr *ValidationError
+	require.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "gapMs", fieldErr.Field)
+	assert.Equal(t, "invalid value", fieldErr.Message)
+	require.ErrorIs(t, err, commonerrors.ErrInvalidValue)
+}